@@ -15,26 +15,50 @@ package config
 
 import (
 	"flag"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"fmt"
+
+	"github.com/hackrish007/ginkgo/internal/spec/filefilter"
 )
 
 const VERSION = "1.16.4"
 
 type GinkgoConfigType struct {
-	RandomSeed         int64
-	RandomizeAllSpecs  bool
-	RegexScansFilePath bool
-	FocusStrings       []string
-	SkipStrings        []string
-	SkipMeasurements   bool
-	FailOnPending      bool
-	FailFast           bool
-	FlakeAttempts      int
-	EmitSpecProgress   bool
-	DryRun             bool
-	DebugParallel      bool
+	RandomSeed          int64
+	RandomizeAllSpecs   bool
+	RegexScansFilePath  bool
+	FocusStrings        []string
+	SkipStrings         []string
+	FocusFiles          []string
+	SkipFiles           []string
+	FocusIDs            []string
+	FocusIDFiles        []string
+	SkipMeasurements    bool
+	FailOnPending       bool
+	FailFast            bool
+	FlakeAttempts       int
+	EmitSpecProgress    bool
+	DryRun              bool
+	DebugParallel       bool
+	ScheduleByDuration  bool
+	RestartCrashedNodes bool
+	TimelineFile        string
+	CaptureOutput       bool
+	OutputMaxBytes      int
+	OutputHeadLines     int
+	OutputTailLines     int
+	OutputDir           string
+	WriterTimestamps    string
+	StreamSpec          string
+	StreamSlowSpecs     float64
+	Quarantine          string
+	ShardIndex          int
+	ShardTotal          int
 
 	ParallelNode  int
 	ParallelTotal int
@@ -54,6 +78,14 @@ type DefaultReporterConfigType struct {
 	FullTrace         bool
 	ReportPassed      bool
 	ReportFile        string
+	JSONReport        string
+	TAPReport         string
+	FailedSpecsFile   string
+	JUnitClassName    string
+	JUnitSystemErr    bool
+	TeamCity          bool
+	GitHubActions     bool
+	Test2JSON         bool
 }
 
 var DefaultReporterConfig = DefaultReporterConfigType{}
@@ -70,6 +102,12 @@ type flagFunc func(string)
 func (f flagFunc) String() string     { return "" }
 func (f flagFunc) Set(s string) error { f(s); return nil }
 
+//validatedFlagFunc is a flagFunc that rejects invalid values
+type validatedFlagFunc func(string) error
+
+func (f validatedFlagFunc) String() string     { return "" }
+func (f validatedFlagFunc) Set(s string) error { return f(s) }
+
 func Flags(flagSet *flag.FlagSet, prefix string, includeParallelFlags bool) {
 	prefix = processPrefix(prefix)
 	flagSet.Int64Var(&(GinkgoConfig.RandomSeed), prefix+"seed", time.Now().Unix(), "The seed used to randomize the spec suite.")
@@ -83,6 +121,15 @@ func Flags(flagSet *flag.FlagSet, prefix string, includeParallelFlags bool) {
 	flagSet.Var(flagFunc(flagFocus), prefix+"focus", "If set, ginkgo will only run specs that match this regular expression. Can be specified multiple times, values are ORed.")
 	flagSet.Var(flagFunc(flagSkip), prefix+"skip", "If set, ginkgo will only run specs that do not match this regular expression. Can be specified multiple times, values are ORed.")
 
+	flagSet.Var(validatedFlagFunc(flagFocusFile), prefix+"focusFile", "If set, ginkgo will only run specs defined in matching files.  Accepts path, path:line or path:line1-line2, where path is a regular expression over the file path that matches from the start of one of the path's components (foo_test.go matches dir/foo_test.go but not dir/xfoo_test.go); a spec matches if the lines fall within it or one of its containers.  Can be specified multiple times, values are ORed.")
+	flagSet.Var(validatedFlagFunc(flagSkipFile), prefix+"skipFile", "If set, ginkgo will skip specs defined in matching files.  Accepts the same values as -focusFile.  Can be specified multiple times, values are ORed.")
+	flagSet.Var(flagFunc(flagFocusID), prefix+"focusID", "If set, ginkgo will only run the spec with this stable ID, as recorded in JSON and JUnit reports.  If no spec has any of the IDs, e.g. because the specs were renamed or removed, ginkgo runs all specs.  Can be specified multiple times, values are ORed.")
+	flagSet.Var(validatedFlagFunc(flagFocusIDFile), prefix+"focusIDFile", "If set, ginkgo will also only run the specs whose stable IDs are listed, one per line, in this file.  Handy for more IDs than fit on a command line.  Can be specified multiple times, values are ORed with each other and with -focusID.")
+
+	flagSet.StringVar(&(GinkgoConfig.Quarantine), prefix+"quarantine", "", "If set, failures of the specs listed in this JSON file are reported as quarantined failures and do not fail the suite.  Entries identify specs by text, regex or location and may carry a reason and an expiry date.")
+
+	flagSet.Var(shardValue{}, prefix+"shard", "If set to i/n, ginkgo will only run the i-th of n shards of the suite.  Specs are assigned to shards by a hash of their stable ID, so the shards are independent of the random seed and of focus, and together run every spec exactly once.")
+
 	flagSet.BoolVar(&(GinkgoConfig.RegexScansFilePath), prefix+"regexScansFilePath", false, "If set, ginkgo regex matching also will look at the file path (code location).")
 
 	flagSet.IntVar(&(GinkgoConfig.FlakeAttempts), prefix+"flakeAttempts", 1, "Make up to this many attempts to run each spec. Please note that if any of the attempts succeed, the suite will not be failed. But any failures will still be recorded.")
@@ -91,6 +138,24 @@ func Flags(flagSet *flag.FlagSet, prefix string, includeParallelFlags bool) {
 
 	flagSet.BoolVar(&(GinkgoConfig.DebugParallel), prefix+"debug", false, "If set, ginkgo will emit node output to files when running in parallel.")
 
+	flagSet.BoolVar(&(GinkgoConfig.ScheduleByDuration), prefix+"scheduleByDuration", false, "If set, ginkgo will hand out specs to parallel nodes longest-first, using durations recorded by previous parallel runs (other than -stream runs, which don't record durations).")
+
+	flagSet.BoolVar(&(GinkgoConfig.RestartCrashedNodes), prefix+"restartCrashedNodes", false, "If set, ginkgo will replace a parallel node that crashes while running a spec with a new node that finishes the remaining specs.")
+
+	flagSet.BoolVar(&(GinkgoConfig.CaptureOutput), prefix+"captureOutput", false, "If set, ginkgo will capture stdout and stderr while each spec runs, even when not running in parallel, and only emit the output of failing specs (and of passing specs with -reportPassed).  Not supported on Windows.")
+
+	flagSet.IntVar(&(GinkgoConfig.OutputMaxBytes), prefix+"outputMaxBytes", 0, "If set, ginkgo will keep at most this many bytes of each spec's captured output, the beginning and the end, in reports and in memory while the spec runs.")
+	flagSet.IntVar(&(GinkgoConfig.OutputHeadLines), prefix+"outputHeadLines", 0, "If set, ginkgo will keep only the first this many lines, plus the lines kept by -outputTailLines, of each spec's captured output in reports.")
+	flagSet.IntVar(&(GinkgoConfig.OutputTailLines), prefix+"outputTailLines", 0, "If set, ginkgo will keep only the last this many lines, plus the lines kept by -outputHeadLines, of each spec's captured output in reports.")
+	flagSet.StringVar(&(GinkgoConfig.OutputDir), prefix+"outputDir", "", "If set, ginkgo will write the full output of each spec whose captured output was truncated to a file in this directory, relative to the suite's directory, and name the file in reports.")
+
+	flagSet.Var(writerTimestampsValue{}, prefix+"writerTimestamps", "If set to relative, ginkgo will prefix each line written to the GinkgoWriter with the time since the spec started.  If set to wallclock, with the time of day.")
+
+	flagSet.Var(streamSpecValue{}, prefix+"streamSpec", "If set, ginkgo will show what specs running on parallel nodes whose full text matches this regular expression write to the GinkgoWriter as they write it, rather than only once they complete.")
+	flagSet.Float64Var(&(GinkgoConfig.StreamSlowSpecs), prefix+"streamSlowSpecs", 0, "(in seconds) If set, ginkgo will show what specs running on parallel nodes write to the GinkgoWriter as they write it once they have run for this long.")
+
+	flagSet.StringVar(&(GinkgoConfig.TimelineFile), prefix+"timelineFile", "", "If set, ginkgo will write a timeline of when each spec and setup node ran on each parallel node to this file, relative to the suite's directory, in the Chrome Trace Event format.")
+
 	if includeParallelFlags {
 		flagSet.IntVar(&(GinkgoConfig.ParallelNode), prefix+"parallel.node", 1, "This worker node's (one-indexed) node number.  For running specs in parallel.")
 		flagSet.IntVar(&(GinkgoConfig.ParallelTotal), prefix+"parallel.total", 1, "The total number of worker nodes.  For running specs in parallel.")
@@ -107,6 +172,14 @@ func Flags(flagSet *flag.FlagSet, prefix string, includeParallelFlags bool) {
 	flagSet.BoolVar(&(DefaultReporterConfig.FullTrace), prefix+"trace", false, "If set, default reporter prints out the full stack trace when a failure occurs")
 	flagSet.BoolVar(&(DefaultReporterConfig.ReportPassed), prefix+"reportPassed", false, "If set, default reporter prints out captured output of passed tests.")
 	flagSet.StringVar(&(DefaultReporterConfig.ReportFile), prefix+"reportFile", "", "Override the default reporter output file path.")
+	flagSet.StringVar(&(DefaultReporterConfig.JSONReport), prefix+"jsonReport", "", "If set, write a JSON report of the suite's run to this file.")
+	flagSet.StringVar(&(DefaultReporterConfig.TAPReport), prefix+"tapReport", "", "If set, write a TAP (Test Anything Protocol) report of the suite's run to this file.")
+	flagSet.StringVar(&(DefaultReporterConfig.FailedSpecsFile), prefix+"failedSpecsFile", "", "If set, write the IDs of the specs that failed to this file, as JSON.  The Ginkgo CLI uses it to support -rerunFailed.")
+	flagSet.Var(junitClassNameValue{}, prefix+"junitClassName", "The classname of specs in JUnit reports: suite (the suite's description, the default), container (the spec's top-level container) or path (all of the spec's containers).")
+	flagSet.BoolVar(&(DefaultReporterConfig.JUnitSystemErr), prefix+"junitSystemErr", false, "If set, JUnit reports put the output specs capture in <system-err> rather than <system-out>.")
+	flagSet.BoolVar(&(DefaultReporterConfig.TeamCity), prefix+"teamcity", false, "If set, report the run to TeamCity by printing service messages to stdout.")
+	flagSet.BoolVar(&(DefaultReporterConfig.GitHubActions), prefix+"githubActions", false, "If set, print GitHub Actions workflow commands that fold each suite's output into a group and annotate failures.")
+	flagSet.BoolVar(&(DefaultReporterConfig.Test2JSON), prefix+"test2json", false, "If set, print the events `go test -json` prints for each spec to stdout, and everything else to stderr.")
 
 }
 
@@ -146,6 +219,30 @@ func BuildFlagArgs(prefix string, ginkgo GinkgoConfigType, reporter DefaultRepor
 		result = append(result, fmt.Sprintf("--%sskip=%s", prefix, s))
 	}
 
+	for _, s := range ginkgo.FocusFiles {
+		result = append(result, fmt.Sprintf("--%sfocusFile=%s", prefix, s))
+	}
+
+	for _, s := range ginkgo.SkipFiles {
+		result = append(result, fmt.Sprintf("--%sskipFile=%s", prefix, s))
+	}
+
+	for _, s := range ginkgo.FocusIDs {
+		result = append(result, fmt.Sprintf("--%sfocusID=%s", prefix, s))
+	}
+
+	for _, s := range ginkgo.FocusIDFiles {
+		result = append(result, fmt.Sprintf("--%sfocusIDFile=%s", prefix, s))
+	}
+
+	if ginkgo.Quarantine != "" {
+		result = append(result, fmt.Sprintf("--%squarantine=%s", prefix, ginkgo.Quarantine))
+	}
+
+	if ginkgo.ShardTotal > 1 {
+		result = append(result, fmt.Sprintf("--%sshard=%d/%d", prefix, ginkgo.ShardIndex, ginkgo.ShardTotal))
+	}
+
 	if ginkgo.FlakeAttempts > 1 {
 		result = append(result, fmt.Sprintf("--%sflakeAttempts=%d", prefix, ginkgo.FlakeAttempts))
 	}
@@ -158,6 +255,50 @@ func BuildFlagArgs(prefix string, ginkgo GinkgoConfigType, reporter DefaultRepor
 		result = append(result, fmt.Sprintf("--%sdebug", prefix))
 	}
 
+	if ginkgo.ScheduleByDuration {
+		result = append(result, fmt.Sprintf("--%sscheduleByDuration", prefix))
+	}
+
+	if ginkgo.RestartCrashedNodes {
+		result = append(result, fmt.Sprintf("--%srestartCrashedNodes", prefix))
+	}
+
+	if ginkgo.CaptureOutput {
+		result = append(result, fmt.Sprintf("--%scaptureOutput", prefix))
+	}
+
+	if ginkgo.OutputMaxBytes > 0 {
+		result = append(result, fmt.Sprintf("--%soutputMaxBytes=%d", prefix, ginkgo.OutputMaxBytes))
+	}
+
+	if ginkgo.OutputHeadLines > 0 {
+		result = append(result, fmt.Sprintf("--%soutputHeadLines=%d", prefix, ginkgo.OutputHeadLines))
+	}
+
+	if ginkgo.OutputTailLines > 0 {
+		result = append(result, fmt.Sprintf("--%soutputTailLines=%d", prefix, ginkgo.OutputTailLines))
+	}
+
+	if ginkgo.OutputDir != "" {
+		result = append(result, fmt.Sprintf("--%soutputDir=%s", prefix, ginkgo.OutputDir))
+	}
+
+	if ginkgo.WriterTimestamps != "" {
+		result = append(result, fmt.Sprintf("--%swriterTimestamps=%s", prefix, ginkgo.WriterTimestamps))
+	}
+
+	if ginkgo.StreamSpec != "" {
+		result = append(result, fmt.Sprintf("--%sstreamSpec=%s", prefix, ginkgo.StreamSpec))
+	}
+
+	if ginkgo.StreamSlowSpecs > 0 {
+		result = append(result, fmt.Sprintf("--%sstreamSlowSpecs=%.5f", prefix, ginkgo.StreamSlowSpecs))
+	}
+
+	if ginkgo.TimelineFile != "" {
+		result = append(result, fmt.Sprintf("--%stimelineFile=%s", prefix, ginkgo.TimelineFile))
+	}
+
 	if ginkgo.ParallelNode != 0 {
 		result = append(result, fmt.Sprintf("--%sparallel.node=%d", prefix, ginkgo.ParallelNode))
 	}
@@ -214,6 +355,38 @@ func BuildFlagArgs(prefix string, ginkgo GinkgoConfigType, reporter DefaultRepor
 		result = append(result, fmt.Sprintf("--%sreportFile=%s", prefix, reporter.ReportFile))
 	}
 
+	if reporter.JSONReport != "" {
+		result = append(result, fmt.Sprintf("--%sjsonReport=%s", prefix, reporter.JSONReport))
+	}
+
+	if reporter.TAPReport != "" {
+		result = append(result, fmt.Sprintf("--%stapReport=%s", prefix, reporter.TAPReport))
+	}
+
+	if reporter.FailedSpecsFile != "" {
+		result = append(result, fmt.Sprintf("--%sfailedSpecsFile=%s", prefix, reporter.FailedSpecsFile))
+	}
+
+	if reporter.JUnitClassName != "" {
+		result = append(result, fmt.Sprintf("--%sjunitClassName=%s", prefix, reporter.JUnitClassName))
+	}
+
+	if reporter.JUnitSystemErr {
+		result = append(result, fmt.Sprintf("--%sjunitSystemErr", prefix))
+	}
+
+	if reporter.TeamCity {
+		result = append(result, fmt.Sprintf("--%steamcity", prefix))
+	}
+
+	if reporter.GitHubActions {
+		result = append(result, fmt.Sprintf("--%sgithubActions", prefix))
+	}
+
+	if reporter.Test2JSON {
+		result = append(result, fmt.Sprintf("--%stest2json", prefix))
+	}
+
 	return result
 }
 
@@ -230,3 +403,115 @@ func flagSkip(arg string) {
 		GinkgoConfig.SkipStrings = append(GinkgoConfig.SkipStrings, arg)
 	}
 }
+
+// flagFocusFile implements the -focusFile flag.
+func flagFocusFile(arg string) error {
+	if arg == "" {
+		return nil
+	}
+	if _, err := filefilter.Parse(arg); err != nil {
+		return err
+	}
+	GinkgoConfig.FocusFiles = append(GinkgoConfig.FocusFiles, arg)
+	return nil
+}
+
+// flagSkipFile implements the -skipFile flag.
+func flagSkipFile(arg string) error {
+	if arg == "" {
+		return nil
+	}
+	if _, err := filefilter.Parse(arg); err != nil {
+		return err
+	}
+	GinkgoConfig.SkipFiles = append(GinkgoConfig.SkipFiles, arg)
+	return nil
+}
+
+// flagFocusID implements the -focusID flag.
+func flagFocusID(arg string) {
+	if arg != "" {
+		GinkgoConfig.FocusIDs = append(GinkgoConfig.FocusIDs, arg)
+	}
+}
+
+// flagFocusIDFile implements the -focusIDFile flag.  The path is made
+// absolute so that test binaries running in their package directories can
+// find it.
+func flagFocusIDFile(arg string) error {
+	if arg == "" {
+		return nil
+	}
+	path, err := filepath.Abs(arg)
+	if err != nil {
+		return err
+	}
+	GinkgoConfig.FocusIDFiles = append(GinkgoConfig.FocusIDFiles, path)
+	return nil
+}
+
+// shardValue implements the -shard flag.
+type shardValue struct{}
+
+func (s shardValue) String() string { return "" }
+
+func (s shardValue) Set(arg string) error {
+	components := strings.Split(arg, "/")
+	if len(components) != 2 {
+		return fmt.Errorf("shard must be of the form i/n")
+	}
+	index, err := strconv.Atoi(components[0])
+	if err != nil {
+		return fmt.Errorf("shard must be of the form i/n")
+	}
+	total, err := strconv.Atoi(components[1])
+	if err != nil {
+		return fmt.Errorf("shard must be of the form i/n")
+	}
+	if total < 1 || index < 1 || index > total {
+		return fmt.Errorf("shard i/n must satisfy 1 <= i <= n")
+	}
+	GinkgoConfig.ShardIndex = index
+	GinkgoConfig.ShardTotal = total
+	return nil
+}
+
+// writerTimestampsValue implements the -writerTimestamps flag.
+type writerTimestampsValue struct{}
+
+func (w writerTimestampsValue) String() string { return "" }
+
+func (w writerTimestampsValue) Set(arg string) error {
+	if arg != "relative" && arg != "wallclock" {
+		return fmt.Errorf("writerTimestamps must be relative or wallclock")
+	}
+	GinkgoConfig.WriterTimestamps = arg
+	return nil
+}
+
+// streamSpecValue implements the -streamSpec flag.
+type streamSpecValue struct{}
+
+func (s streamSpecValue) String() string { return "" }
+
+func (s streamSpecValue) Set(arg string) error {
+	_, err := regexp.Compile(arg)
+	if err != nil {
+		return fmt.Errorf("streamSpec must be a valid regular expression: %s", err.Error())
+	}
+	GinkgoConfig.StreamSpec = arg
+	return nil
+}
+
+// junitClassNameValue implements the -junitClassName flag.
+type junitClassNameValue struct{}
+
+func (j junitClassNameValue) String() string { return "" }
+
+func (j junitClassNameValue) Set(arg string) error {
+	if arg != "suite" && arg != "container" && arg != "path" {
+		return fmt.Errorf("junitClassName must be suite, container or path")
+	}
+	DefaultReporterConfig.JUnitClassName = arg
+	return nil
+}
@@ -100,8 +100,8 @@ func describeTable(description string, itBody interface{}, entries []TableEntry,
 	global.Suite.PushContainerNode(
 		description,
 		func() {
-			for _, entry := range entries {
-				entry.generateIt(itBodyValue)
+			for i, entry := range entries {
+				entry.generateIt(itBodyValue, i)
 			}
 		},
 		flag,
@@ -20,7 +20,7 @@ type TableEntry struct {
 	codeLocation types.CodeLocation
 }
 
-func (t TableEntry) generateIt(itBody reflect.Value) {
+func (t TableEntry) generateIt(itBody reflect.Value, entryIndex int) {
 	var description string
 	descriptionValue := reflect.ValueOf(t.Description)
 	switch descriptionValue.Kind() {
@@ -41,7 +41,7 @@ func (t TableEntry) generateIt(itBody reflect.Value) {
 	}
 
 	if t.Pending {
-		global.Suite.PushItNode(description, func() {}, types.FlagTypePending, t.codeLocation, 0)
+		global.Suite.PushTableEntryNode(description, func() {}, types.FlagTypePending, t.codeLocation, 0, entryIndex)
 		return
 	}
 
@@ -51,9 +51,9 @@ func (t TableEntry) generateIt(itBody reflect.Value) {
 	}
 
 	if t.Focused {
-		global.Suite.PushItNode(description, body, types.FlagTypeFocused, t.codeLocation, global.DefaultTimeout)
+		global.Suite.PushTableEntryNode(description, body, types.FlagTypeFocused, t.codeLocation, global.DefaultTimeout, entryIndex)
 	} else {
-		global.Suite.PushItNode(description, body, types.FlagTypeNone, t.codeLocation, global.DefaultTimeout)
+		global.Suite.PushTableEntryNode(description, body, types.FlagTypeNone, t.codeLocation, global.DefaultTimeout, entryIndex)
 	}
 }
 
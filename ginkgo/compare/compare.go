@@ -0,0 +1,274 @@
+/*
+Package compare compares the results of two runs of a set of suites, as saved
+in the JUnit or JSON reports Ginkgo writes with -reportFile or -jsonReport.
+
+Specs are matched by their suite and their stable ID, and by their suite and
+text when a report doesn't record IDs.  The comparison lists the specs that
+newly failed, that were fixed, that were added or removed, that are newly
+skipped, and that got significantly slower.  A BeforeSuite, AfterSuite or
+compilation that failed in the old run and isn't in the new run of its suite
+was fixed.
+*/
+package compare
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The statuses a result is compared by.  Panicked and timed-out specs failed,
+// and pending specs were skipped.  Failures of quarantined specs are neither
+// failures nor skips.
+const (
+	StatusPassed      = "passed"
+	StatusFailed      = "failed"
+	StatusSkipped     = "skipped"
+	StatusQuarantined = "quarantined"
+)
+
+// Result is the outcome of running a spec, or of a BeforeSuite, AfterSuite or
+// compilation that failed.  State is the state the report recorded, Status
+// the state's status.
+type Result struct {
+	Suite   string
+	ID      string
+	Name    string
+	State   string
+	Status  string
+	RunTime time.Duration
+}
+
+func (result Result) String() string {
+	return fmt.Sprintf("[%s] %s", result.Suite, result.Name)
+}
+
+// isSetup returns true if the result is that of a BeforeSuite, AfterSuite or
+// compilation.  Reports only record these when they fail, so one that is
+// missing from a suite that ran passed.
+func (result Result) isSetup() bool {
+	if result.ID != "" {
+		return false
+	}
+	switch result.Name {
+	case "BeforeSuite", "AfterSuite", "Compilation":
+		return true
+	}
+	return false
+}
+
+func (result Result) passed() Result {
+	return Result{Suite: result.Suite, Name: result.Name, State: StatusPassed, Status: StatusPassed}
+}
+
+// Thresholds determine which specs got significantly slower: those whose run
+// time grew by more than Percent percent and by more than Minimum.
+type Thresholds struct {
+	Percent float64
+	Minimum time.Duration
+}
+
+// Change pairs the results of a spec in the old and the new run.
+type Change struct {
+	Old Result
+	New Result
+}
+
+// Comparison lists how the results of the new run differ from those of the
+// old one.  New specs that fail are both new failures and added.
+type Comparison struct {
+	NewFailures   []Change
+	Fixed         []Change
+	Added         []Result
+	Removed       []Result
+	NewlySkipped  []Change
+	Slower        []Change
+	NumberOfSpecs int
+}
+
+// HasNewFailures returns true if a spec that didn't fail in the old run
+// failed in the new one.
+func (comparison Comparison) HasNewFailures() bool {
+	return len(comparison.NewFailures) > 0
+}
+
+// Compare compares the results of the old and new runs.
+func Compare(old []Result, new []Result, thresholds Thresholds) Comparison {
+	comparison := Comparison{NumberOfSpecs: len(new)}
+	matches := match(old, new)
+
+	suites := map[string]bool{}
+	for _, newResult := range new {
+		suites[newResult.Suite] = true
+	}
+
+	matched := map[int]bool{}
+	for i, oldResult := range old {
+		j, ok := matches[i]
+		if !ok {
+			if oldResult.isSetup() && oldResult.Status == StatusFailed && suites[oldResult.Suite] {
+				comparison.Fixed = append(comparison.Fixed, Change{Old: oldResult, New: oldResult.passed()})
+				continue
+			}
+			comparison.Removed = append(comparison.Removed, oldResult)
+			continue
+		}
+		matched[j] = true
+		change := Change{Old: oldResult, New: new[j]}
+		switch {
+		case change.New.Status == StatusFailed && change.Old.Status != StatusFailed:
+			comparison.NewFailures = append(comparison.NewFailures, change)
+		case change.Old.Status == StatusFailed && change.New.Status == StatusPassed:
+			comparison.Fixed = append(comparison.Fixed, change)
+		case change.New.Status == StatusSkipped && change.Old.Status != StatusSkipped:
+			comparison.NewlySkipped = append(comparison.NewlySkipped, change)
+		}
+		if change.Old.Status == StatusPassed && change.New.Status == StatusPassed && thresholds.exceededBy(change) {
+			comparison.Slower = append(comparison.Slower, change)
+		}
+	}
+	for j, newResult := range new {
+		if matched[j] {
+			continue
+		}
+		comparison.Added = append(comparison.Added, newResult)
+		if newResult.Status == StatusFailed {
+			comparison.NewFailures = append(comparison.NewFailures, Change{New: newResult})
+		}
+	}
+
+	sort.SliceStable(comparison.Slower, func(i, j int) bool {
+		return comparison.Slower[i].slowdown() > comparison.Slower[j].slowdown()
+	})
+	return comparison
+}
+
+// match returns the index of the new result of each old result that has one.
+// Results are matched by suite and ID, then those left by suite and name.
+func match(old []Result, new []Result) map[int]int {
+	matches := map[int]int{}
+	taken := map[int]bool{}
+	for _, key := range []func(Result) string{idKey, nameKey} {
+		index := map[string]int{}
+		for j, result := range new {
+			if k := key(result); k != "" && !taken[j] {
+				index[k] = j
+			}
+		}
+		for i, result := range old {
+			if _, ok := matches[i]; ok {
+				continue
+			}
+			if j, ok := index[key(result)]; ok && key(result) != "" && !taken[j] {
+				matches[i] = j
+				taken[j] = true
+			}
+		}
+	}
+	return matches
+}
+
+func idKey(result Result) string {
+	if result.ID == "" {
+		return ""
+	}
+	return result.Suite + "\x00" + result.ID
+}
+
+func nameKey(result Result) string {
+	return result.Suite + "\x00" + result.Name
+}
+
+func (thresholds Thresholds) exceededBy(change Change) bool {
+	growth := change.New.RunTime - change.Old.RunTime
+	if growth <= thresholds.Minimum {
+		return false
+	}
+	return float64(growth) > float64(change.Old.RunTime)*thresholds.Percent/100
+}
+
+func (change Change) slowdown() time.Duration {
+	return change.New.RunTime - change.Old.RunTime
+}
+
+// String describes each difference, grouped by kind, followed by a summary
+// line.
+func (comparison Comparison) String() string {
+	out := ""
+	section := func(title string, count int, lines func() []string) {
+		if count == 0 {
+			return
+		}
+		out += fmt.Sprintf("%s (%d):\n", title, count)
+		for _, line := range lines() {
+			out += "  " + line + "\n"
+		}
+		out += "\n"
+	}
+
+	section("New failures", len(comparison.NewFailures), func() []string {
+		lines := []string{}
+		for _, change := range comparison.NewFailures {
+			line := fmt.Sprintf("%s [%s]", change.New, strings.ToUpper(change.New.State))
+			if change.Old.Status == "" {
+				line += ", a new spec"
+			} else {
+				line += fmt.Sprintf(", was %s", change.Old.State)
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	})
+	section("Fixed", len(comparison.Fixed), func() []string {
+		lines := []string{}
+		for _, change := range comparison.Fixed {
+			lines = append(lines, fmt.Sprintf("%s, was %s", change.New, change.Old.State))
+		}
+		return lines
+	})
+	section("Added", len(comparison.Added), func() []string {
+		return resultLines(comparison.Added)
+	})
+	section("Removed", len(comparison.Removed), func() []string {
+		return resultLines(comparison.Removed)
+	})
+	section("Newly skipped", len(comparison.NewlySkipped), func() []string {
+		lines := []string{}
+		for _, change := range comparison.NewlySkipped {
+			lines = append(lines, fmt.Sprintf("%s [%s], was %s", change.New, strings.ToUpper(change.New.State), change.Old.State))
+		}
+		return lines
+	})
+	section("Slower", len(comparison.Slower), func() []string {
+		lines := []string{}
+		for _, change := range comparison.Slower {
+			line := fmt.Sprintf("%s: %.3fs -> %.3fs", change.New, change.Old.RunTime.Seconds(), change.New.RunTime.Seconds())
+			if change.Old.RunTime > 0 {
+				line += fmt.Sprintf(" (+%.0f%%)", 100*float64(change.slowdown())/float64(change.Old.RunTime))
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	})
+
+	out += fmt.Sprintf("Compared %d specs: %d new %s, %d fixed, %d added, %d removed, %d newly skipped, %d slower\n",
+		comparison.NumberOfSpecs, len(comparison.NewFailures), pluralize(len(comparison.NewFailures), "failure"),
+		len(comparison.Fixed), len(comparison.Added), len(comparison.Removed), len(comparison.NewlySkipped), len(comparison.Slower))
+	return out
+}
+
+func resultLines(results []Result) []string {
+	lines := []string{}
+	for _, result := range results {
+		lines = append(lines, fmt.Sprintf("%s [%s]", result, strings.ToUpper(result.State)))
+	}
+	return lines
+}
+
+func pluralize(count int, word string) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,13 @@
+package compare_test
+
+import (
+	"testing"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/gomega"
+)
+
+func TestCompare(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Compare Suite")
+}
@@ -0,0 +1,171 @@
+package compare_test
+
+import (
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/ginkgo/compare"
+	. "github.com/hackrish007/gomega"
+)
+
+func result(id string, name string, status string, runTime time.Duration) compare.Result {
+	state := status
+	if status == compare.StatusSkipped {
+		state = "pending"
+	}
+	return compare.Result{Suite: "Widget Suite", ID: id, Name: name, State: state, Status: status, RunTime: runTime}
+}
+
+var _ = Describe("Compare", func() {
+	var thresholds compare.Thresholds
+
+	BeforeEach(func() {
+		thresholds = compare.Thresholds{Percent: 50, Minimum: 100 * time.Millisecond}
+	})
+
+	It("categorizes the specs whose results changed", func() {
+		old := []compare.Result{
+			result("1", "breaks", compare.StatusPassed, time.Second),
+			result("2", "gets fixed", compare.StatusFailed, time.Second),
+			result("3", "is removed", compare.StatusPassed, time.Second),
+			result("4", "gets skipped", compare.StatusPassed, time.Second),
+			result("5", "is unchanged", compare.StatusPassed, time.Second),
+		}
+		new := []compare.Result{
+			result("1", "breaks", compare.StatusFailed, time.Second),
+			result("2", "gets fixed", compare.StatusPassed, time.Second),
+			result("4", "gets skipped", compare.StatusSkipped, 0),
+			result("5", "is unchanged", compare.StatusPassed, time.Second),
+			result("6", "is added", compare.StatusPassed, time.Second),
+			result("7", "is added broken", compare.StatusFailed, time.Second),
+		}
+
+		comparison := compare.Compare(old, new, thresholds)
+		Ω(comparison.NewFailures).Should(Equal([]compare.Change{
+			{Old: old[0], New: new[0]},
+			{New: new[5]},
+		}))
+		Ω(comparison.Fixed).Should(Equal([]compare.Change{{Old: old[1], New: new[1]}}))
+		Ω(comparison.Removed).Should(Equal([]compare.Result{old[2]}))
+		Ω(comparison.NewlySkipped).Should(Equal([]compare.Change{{Old: old[3], New: new[2]}}))
+		Ω(comparison.Added).Should(Equal([]compare.Result{new[4], new[5]}))
+		Ω(comparison.Slower).Should(BeEmpty())
+		Ω(comparison.NumberOfSpecs).Should(Equal(6))
+		Ω(comparison.HasNewFailures()).Should(BeTrue())
+	})
+
+	It("doesn't count quarantined failures as new failures", func() {
+		old := []compare.Result{result("1", "is flaky", compare.StatusPassed, time.Second)}
+		new := []compare.Result{result("1", "is flaky", compare.StatusQuarantined, time.Second)}
+
+		comparison := compare.Compare(old, new, thresholds)
+		Ω(comparison.HasNewFailures()).Should(BeFalse())
+		Ω(comparison.NewlySkipped).Should(BeEmpty())
+	})
+
+	It("matches specs by ID, and by name when there is no ID", func() {
+		old := []compare.Result{
+			result("1", "was renamed", compare.StatusPassed, time.Second),
+			result("", "has no ID", compare.StatusPassed, time.Second),
+		}
+		new := []compare.Result{
+			result("1", "has a new name", compare.StatusPassed, time.Second),
+			result("", "has no ID", compare.StatusFailed, time.Second),
+		}
+
+		comparison := compare.Compare(old, new, thresholds)
+		Ω(comparison.Added).Should(BeEmpty())
+		Ω(comparison.Removed).Should(BeEmpty())
+		Ω(comparison.NewFailures).Should(Equal([]compare.Change{{Old: old[1], New: new[1]}}))
+	})
+
+	It("counts failed setups missing from a suite that ran again as fixed", func() {
+		old := []compare.Result{
+			result("", "BeforeSuite", compare.StatusFailed, time.Second),
+			result("1", "runs", compare.StatusPassed, time.Second),
+			{Suite: "Gadget Suite", Name: "Compilation", State: "failed", Status: compare.StatusFailed},
+		}
+		new := []compare.Result{result("1", "runs", compare.StatusPassed, time.Second)}
+
+		comparison := compare.Compare(old, new, thresholds)
+		Ω(comparison.Fixed).Should(Equal([]compare.Change{{
+			Old: old[0],
+			New: compare.Result{Suite: "Widget Suite", Name: "BeforeSuite", State: "passed", Status: compare.StatusPassed},
+		}}))
+		Ω(comparison.Removed).Should(Equal([]compare.Result{old[2]}))
+	})
+
+	It("doesn't match specs of different suites", func() {
+		old := []compare.Result{result("1", "runs", compare.StatusPassed, time.Second)}
+		new := []compare.Result{result("1", "runs", compare.StatusPassed, time.Second)}
+		new[0].Suite = "Gadget Suite"
+
+		comparison := compare.Compare(old, new, thresholds)
+		Ω(comparison.Added).Should(Equal(new))
+		Ω(comparison.Removed).Should(Equal(old))
+	})
+
+	It("lists passing specs that got slower by more than both thresholds, slowest first", func() {
+		old := []compare.Result{
+			result("1", "got a little slower", compare.StatusPassed, time.Second),
+			result("2", "got briefly slower", compare.StatusPassed, 10*time.Millisecond),
+			result("3", "got much slower", compare.StatusPassed, time.Second),
+			result("4", "got slower", compare.StatusPassed, time.Second),
+			result("5", "failed slower", compare.StatusFailed, time.Second),
+		}
+		new := []compare.Result{
+			result("1", "got a little slower", compare.StatusPassed, 1400*time.Millisecond),
+			result("2", "got briefly slower", compare.StatusPassed, 90*time.Millisecond),
+			result("3", "got much slower", compare.StatusPassed, 5*time.Second),
+			result("4", "got slower", compare.StatusPassed, 2*time.Second),
+			result("5", "failed slower", compare.StatusFailed, 5*time.Second),
+		}
+
+		comparison := compare.Compare(old, new, thresholds)
+		Ω(comparison.Slower).Should(Equal([]compare.Change{
+			{Old: old[2], New: new[2]},
+			{Old: old[3], New: new[3]},
+		}))
+		Ω(comparison.HasNewFailures()).Should(BeFalse())
+	})
+
+	Describe("describing the comparison", func() {
+		It("lists each kind of difference and summarizes them", func() {
+			old := []compare.Result{
+				result("1", "breaks", compare.StatusPassed, time.Second),
+				result("2", "gets fixed", compare.StatusFailed, time.Second),
+				result("3", "is removed", compare.StatusPassed, time.Second),
+				result("4", "gets slower", compare.StatusPassed, time.Second),
+			}
+			new := []compare.Result{
+				result("1", "breaks", compare.StatusFailed, time.Second),
+				result("2", "gets fixed", compare.StatusPassed, time.Second),
+				result("4", "gets slower", compare.StatusPassed, 2*time.Second),
+				result("5", "is added", compare.StatusFailed, time.Second),
+			}
+
+			Ω(compare.Compare(old, new, thresholds).String()).Should(Equal("New failures (2):\n" +
+				"  [Widget Suite] breaks [FAILED], was passed\n" +
+				"  [Widget Suite] is added [FAILED], a new spec\n" +
+				"\n" +
+				"Fixed (1):\n" +
+				"  [Widget Suite] gets fixed, was failed\n" +
+				"\n" +
+				"Added (1):\n" +
+				"  [Widget Suite] is added [FAILED]\n" +
+				"\n" +
+				"Removed (1):\n" +
+				"  [Widget Suite] is removed [PASSED]\n" +
+				"\n" +
+				"Slower (1):\n" +
+				"  [Widget Suite] gets slower: 1.000s -> 2.000s (+100%)\n" +
+				"\n" +
+				"Compared 4 specs: 2 new failures, 1 fixed, 1 added, 1 removed, 0 newly skipped, 1 slower\n"))
+		})
+
+		It("only summarizes identical runs", func() {
+			results := []compare.Result{result("1", "runs", compare.StatusPassed, time.Second)}
+			Ω(compare.Compare(results, results, thresholds).String()).Should(Equal("Compared 1 specs: 0 new failures, 0 fixed, 0 added, 0 removed, 0 newly skipped, 0 slower\n"))
+		})
+	})
+})
@@ -0,0 +1,135 @@
+package compare
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/hackrish007/ginkgo/reporters"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+// ReadResults reads the results saved in a JUnit or JSON report.  Specs
+// retried with -flakeAttempts are described by their last attempt.
+func ReadResults(filename string) ([]Result, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		suites, err := reporters.ReadJUnitReport(filename)
+		if err != nil {
+			return nil, err
+		}
+		return lastAttempts(junitResults(suites)), nil
+	}
+	reports, err := reporters.ReadJSONReport(filename)
+	if err != nil {
+		return nil, err
+	}
+	return lastAttempts(jsonResults(reports)), nil
+}
+
+func jsonResults(reports []types.SuiteReport) []Result {
+	results := []Result{}
+	for _, report := range reports {
+		if report.CompilationFailure != "" {
+			results = append(results, Result{Suite: report.SuiteDescription, Name: "Compilation", State: "failed", Status: StatusFailed})
+		}
+		setup := func(name string, setup *types.SetupReport) {
+			if setup != nil && setup.HasFailureState() {
+				results = append(results, Result{Suite: report.SuiteDescription, Name: name, State: setup.State, Status: StatusFailed, RunTime: setup.RunTime})
+			}
+		}
+		setup("BeforeSuite", report.BeforeSuite)
+		for _, spec := range report.Specs {
+			result := Result{
+				Suite:   report.SuiteDescription,
+				ID:      spec.ID,
+				Name:    strings.Join(spec.ComponentTexts[1:], " "),
+				State:   spec.State,
+				Status:  StatusPassed,
+				RunTime: spec.RunTime,
+			}
+			switch {
+			case spec.HasQuarantinedFailure():
+				result.Status = StatusQuarantined
+			case spec.HasFailureState():
+				result.Status = StatusFailed
+			case spec.State == types.SpecStateSkipped.String() || spec.State == types.SpecStatePending.String():
+				result.Status = StatusSkipped
+			}
+			results = append(results, result)
+		}
+		setup("AfterSuite", report.AfterSuite)
+	}
+	return results
+}
+
+func junitResults(suites reporters.JUnitTestSuites) []Result {
+	results := []Result{}
+	for _, suite := range suites.TestSuites {
+		for _, testCase := range suite.TestCases {
+			result := Result{
+				Suite:   suite.Name,
+				Name:    testCase.Name,
+				State:   types.SpecStatePassed.String(),
+				Status:  StatusPassed,
+				RunTime: time.Duration(testCase.Time * float64(time.Second)),
+			}
+			if testCase.Properties != nil {
+				for _, property := range testCase.Properties.Properties {
+					if property.Name == "id" {
+						result.ID = property.Value
+					}
+				}
+			}
+			switch {
+			case testCase.ErrorMessage != nil:
+				result.State, result.Status = junitErrorState(testCase.ErrorMessage.Type), StatusFailed
+			case testCase.FailureMessage != nil:
+				result.State, result.Status = types.SpecStateFailed.String(), StatusFailed
+			case testCase.Skipped != nil && strings.HasPrefix(testCase.Skipped.Message, "Quarantined failure"):
+				result.State, result.Status = StatusQuarantined, StatusQuarantined
+			case testCase.Skipped != nil:
+				result.State, result.Status = types.SpecStateSkipped.String(), StatusSkipped
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// junitErrorState returns the state of an errored test case from the type of
+// its <error>.
+func junitErrorState(errorType string) string {
+	switch errorType {
+	case "Panic":
+		return types.SpecStatePanicked.String()
+	case "Timeout":
+		return types.SpecStateTimedOut.String()
+	default:
+		return types.SpecStateFailed.String()
+	}
+}
+
+// lastAttempts keeps the last of the results of each spec, in the order the
+// specs first ran.
+func lastAttempts(results []Result) []Result {
+	kept := []Result{}
+	indices := map[string]int{}
+	for _, result := range results {
+		key := idKey(result)
+		if key == "" {
+			key = nameKey(result)
+		}
+		if i, ok := indices[key]; ok {
+			kept[i] = result
+			continue
+		}
+		indices[key] = len(kept)
+		kept = append(kept, result)
+	}
+	return kept
+}
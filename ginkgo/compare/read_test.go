@@ -0,0 +1,111 @@
+package compare_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/ginkgo/compare"
+	"github.com/hackrish007/ginkgo/reporters"
+	"github.com/hackrish007/ginkgo/types"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("ReadResults", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "ginkgo-compare")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(name string, data []byte) string {
+		filename := filepath.Join(dir, name)
+		Ω(ioutil.WriteFile(filename, data, 0644)).Should(Succeed())
+		return filename
+	}
+
+	It("reads JSON reports, keeping the last attempt of each spec", func() {
+		reports := []types.SuiteReport{
+			{
+				Version:          types.JSONReportVersion,
+				SuiteDescription: "Widget Suite",
+				BeforeSuite:      &types.SetupReport{State: "passed"},
+				Specs: []types.SpecReport{
+					{ID: "1", ComponentTexts: []string{"[Top Level]", "Widget", "flakes"}, State: "failed", RunTime: time.Second},
+					{ID: "2", ComponentTexts: []string{"[Top Level]", "Widget", "is pending"}, State: "pending"},
+					{ID: "3", ComponentTexts: []string{"[Top Level]", "Widget", "is quarantined"}, State: "panicked", Quarantined: true},
+					{ID: "1", ComponentTexts: []string{"[Top Level]", "Widget", "flakes"}, State: "passed", RunTime: 2 * time.Second},
+				},
+				AfterSuite: &types.SetupReport{State: "timedout", RunTime: time.Second},
+			},
+			{
+				Version:            types.JSONReportVersion,
+				SuiteDescription:   "broken",
+				CompilationFailure: "undefined: x",
+			},
+		}
+		data, err := json.Marshal(reports)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		results, err := compare.ReadResults(write("report.json", data))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(results).Should(Equal([]compare.Result{
+			{Suite: "Widget Suite", ID: "1", Name: "Widget flakes", State: "passed", Status: compare.StatusPassed, RunTime: 2 * time.Second},
+			{Suite: "Widget Suite", ID: "2", Name: "Widget is pending", State: "pending", Status: compare.StatusSkipped},
+			{Suite: "Widget Suite", ID: "3", Name: "Widget is quarantined", State: "panicked", Status: compare.StatusQuarantined},
+			{Suite: "Widget Suite", Name: "AfterSuite", State: "timedout", Status: compare.StatusFailed, RunTime: time.Second},
+			{Suite: "broken", Name: "Compilation", State: "failed", Status: compare.StatusFailed},
+		}))
+	})
+
+	It("reads JUnit reports", func() {
+		id := func(value string) *reporters.JUnitProperties {
+			return &reporters.JUnitProperties{Properties: []reporters.JUnitProperty{{Name: "id", Value: value}}}
+		}
+		suites := reporters.JUnitTestSuites{
+			TestSuites: []reporters.JUnitTestSuite{{
+				Name: "Widget Suite",
+				TestCases: []reporters.JUnitTestCase{
+					{Name: "Widget passes", Properties: id("1"), Time: 1.5},
+					{Name: "Widget fails", Properties: id("2"), FailureMessage: &reporters.JUnitFailureMessage{Type: "Failure"}},
+					{Name: "Widget panics", Properties: id("3"), ErrorMessage: &reporters.JUnitFailureMessage{Type: "Panic"}},
+					{Name: "Widget times out", Properties: id("4"), ErrorMessage: &reporters.JUnitFailureMessage{Type: "Timeout"}},
+					{Name: "Widget is quarantined", Properties: id("5"), Skipped: &reporters.JUnitSkipped{Message: "Quarantined failure: flaky\nboom"}},
+					{Name: "Widget is skipped", Properties: id("6"), Skipped: &reporters.JUnitSkipped{}},
+					{Name: "BeforeSuite", ErrorMessage: &reporters.JUnitFailureMessage{Type: "Panic"}},
+				},
+			}},
+		}
+		data, err := xml.Marshal(suites)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		results, err := compare.ReadResults(write("junit.xml", data))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(results).Should(Equal([]compare.Result{
+			{Suite: "Widget Suite", ID: "1", Name: "Widget passes", State: "passed", Status: compare.StatusPassed, RunTime: 1500 * time.Millisecond},
+			{Suite: "Widget Suite", ID: "2", Name: "Widget fails", State: "failed", Status: compare.StatusFailed},
+			{Suite: "Widget Suite", ID: "3", Name: "Widget panics", State: "panicked", Status: compare.StatusFailed},
+			{Suite: "Widget Suite", ID: "4", Name: "Widget times out", State: "timedout", Status: compare.StatusFailed},
+			{Suite: "Widget Suite", ID: "5", Name: "Widget is quarantined", State: "quarantined", Status: compare.StatusQuarantined},
+			{Suite: "Widget Suite", ID: "6", Name: "Widget is skipped", State: "skipped", Status: compare.StatusSkipped},
+			{Suite: "Widget Suite", Name: "BeforeSuite", State: "panicked", Status: compare.StatusFailed},
+		}))
+	})
+
+	It("errors when the file is neither", func() {
+		_, err := compare.ReadResults(write("bogus.txt", []byte("bogus")))
+		Ω(err).Should(HaveOccurred())
+		_, err = compare.ReadResults(filepath.Join(dir, "missing.json"))
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hackrish007/ginkgo/ginkgo/compare"
+)
+
+func BuildCompareCommand() *Command {
+	thresholds := compare.Thresholds{}
+	flagSet := flag.NewFlagSet("compare", flag.ExitOnError)
+	flagSet.Float64Var(&thresholds.Percent, "slowdown", 50, "A spec got slower if its run time grew by more than this percentage, and by more than -minSlowdown.")
+	flagSet.DurationVar(&thresholds.Minimum, "minSlowdown", 100*time.Millisecond, "A spec got slower if its run time grew by more than this duration, and by more than -slowdown percent.")
+	return &Command{
+		Name:         "compare",
+		FlagSet:      flagSet,
+		UsageCommand: "ginkgo compare <FLAGS> <OLD REPORT> <NEW REPORT>",
+		Usage: []string{
+			"Compare the results of two runs, as saved in JUnit or JSON reports",
+			"Lists the specs that newly failed, were fixed, were added or removed, are newly skipped or got slower.",
+			"Exits with a non-zero status if any spec newly failed.",
+			"Accepts the following flags:",
+		},
+		Command: func(args []string, additionalArgs []string) {
+			compareRuns(args, thresholds)
+		},
+	}
+}
+
+func compareRuns(args []string, thresholds compare.Thresholds) {
+	if len(args) != 2 {
+		complainAndQuit("Please pass the reports of the old run and of the new run")
+	}
+	old, err := compare.ReadResults(args[0])
+	if err != nil {
+		complainAndQuit(err.Error())
+	}
+	new, err := compare.ReadResults(args[1])
+	if err != nil {
+		complainAndQuit(err.Error())
+	}
+
+	comparison := compare.Compare(old, new, thresholds)
+	fmt.Print(comparison)
+	if comparison.HasNewFailures() {
+		os.Exit(1)
+	}
+}
@@ -29,6 +29,74 @@ To pass arguments/flags to your tests:
 
 	ginkgo <flags> <packages> -- <pass-throughs>
 
+To capture what each spec writes to stdout and stderr and only show it for failing specs (and, with -reportPassed, passing specs), as parallel runs do:
+
+	ginkgo -captureOutput
+
+To keep a chatty spec's captured output from swamping reports, keep only its first and last lines, or its first and last bytes, and have Ginkgo write the full output of truncated specs to files, one per attempt at a spec, named after the spec, the node and the attempt:
+
+	ginkgo -outputHeadLines=50 -outputTailLines=200 -outputMaxBytes=65536 -outputDir=spec-output
+
+To prefix each line written to the GinkgoWriter with the time since its spec started (or, with wallclock, the time of day) so that it can be lined up with other logs:
+
+	ginkgo -writerTimestamps=relative
+
+To write a JUnit report, or a JSON report describing each suite's configuration, its BeforeSuite and AfterSuite and every spec's state, timings, failure, output and measurements:
+
+	ginkgo -r -reportFile=junit.xml -jsonReport=report.json
+
+Ginkgo writes a single report covering every suite of the run, and every parallel node of each suite, to the given path.  The JUnit report holds a <testsuite> per suite and the JSON report is an array
+with a versioned types.SuiteReport per suite.  Suites that fail to compile are reported as errored suites holding the compiler's output.
+
+In the JUnit report failed specs are <failure>s while panicked and timed-out specs are <error>s.  Each <testsuite> carries the suite's configuration as <properties> along with a timestamp and hostname,
+and each <testcase> the file and line of its spec and the node that ran it.  To name each testcase's class after its top-level container, or the path of its containers, rather than the suite,
+and to put captured output in <system-err> rather than <system-out>:
+
+	ginkgo -reportFile=junit.xml -junitClassName=path -junitSystemErr
+
+To write a TAP (Test Anything Protocol) report for harnesses such as prove:
+
+	ginkgo -r -tapReport=report.tap
+
+Skipped specs are reported with a SKIP directive, pending specs and failures of quarantined specs with a TODO directive, and failures with a YAML block holding the failure's message, location, duration and captured output.
+A report of a single suite is a TAP version 13 document; a report of several suites is a TAP version 14 document with a subtest per suite.
+
+To report the run to TeamCity with service messages:
+
+	ginkgo -teamcity
+
+Each parallel node's specs are reported in a TeamCity flow of their own, BeforeSuites and AfterSuites are reported with their durations, and pending and skipped specs with the reason they didn't run.
+With -flakeAttempts, Ginkgo enables TeamCity's support for retried tests and numbers each retry.
+
+To have failures show up as annotations of a GitHub Actions workflow run:
+
+	ginkgo -r -githubActions
+
+Ginkgo folds each suite's output into a group, then prints each failure in a group of its own followed by an error annotation pointing at the line that failed.
+Flaked specs and failures of quarantined specs are annotated as warnings.  Paths are relative to the root of the suite's module.
+
+To feed the run to tools that understand `go test -json`, such as IDEs and gotestsum:
+
+	gotestsum --raw-command -- ginkgo -r -test2json
+
+Ginkgo prints test2json events to stdout, reporting each suite as a test and each spec as a subtest named after its containers and text, e.g. "My_Suite/Widget/renders".
+Everything else Ginkgo and the test binaries print, including the default reporter's output and compilation errors, goes to stderr instead.
+Parallel suites are reported as their specs complete; -stream is ignored.
+
+To render the JSON reports of a run as a self-contained HTML page, for CI artifacts, and as a Markdown summary, for pull request comments:
+
+	ginkgo report -html=report.html -markdown=report.md report.json
+
+The page holds a collapsible tree of each suite's specs that can be filtered by state and text, the details of each failure along with the failing spec's captured output, and the slowest specs.
+Without -html or -markdown the Markdown summary is printed.  -slowest sets the number of slowest specs listed.
+
+To compare two runs, say of a pull request and of its base branch, pass the JUnit or JSON reports of the old run and of the new one:
+
+	ginkgo compare base/junit.xml pr/junit.xml
+
+This lists the specs that newly failed, that were fixed, that were added or removed, that are newly skipped and that got slower, and exits with a non-zero status if any spec newly failed.
+Specs are matched by their ID, or by their text when the report has no IDs.  A spec is slower when its run time grew by more than -slowdown percent (50 by default) and by more than -minSlowdown (100ms by default).
+
 To run tests in parallel
 
 	ginkgo -p
@@ -46,6 +114,64 @@ An alternative is to have the parallel nodes run and stream interleaved output b
 
 On windows, the default value for stream is true.
 
+Parallel nodes normally lease specs from the server in batches that shrink as the run progresses.  When scheduling by duration, batches are sized by the specs' cached durations, so the longest specs are leased one at a time.  With -stream the server doesn't receive the nodes' reports, so it can't tell how far a crashed node got through a batch; it hands out one spec at a time instead.
+
+The server listens on a Unix domain socket in a private temporary directory, falling back to a TCP port on 127.0.0.1 where Unix domain sockets are unavailable.
+
+To have the parallel nodes pick up the slowest specs first, based on the spec durations Ginkgo recorded during previous parallel runs of the suite:
+
+	ginkgo -nodes=N -scheduleByDuration
+
+Ginkgo caches these durations in a .ginkgo-spec-durations.json file in each suite's directory and reports how busy each node was at the end of the run.  Runs with -stream use the cached durations but don't record new ones: their nodes print their specs' results rather than reporting them to Ginkgo.
+
+If a parallel node crashes (e.g. it segfaults, calls os.Exit, or panics in a stray goroutine) Ginkgo reports the spec the node was running as failed, along with the node's output.  To have Ginkgo start a new node to run the remaining specs:
+
+	ginkgo -nodes=N -restartCrashedNodes
+
+To see where the time in a parallel run goes, have Ginkgo write a timeline of when each spec and each BeforeSuite/AfterSuite ran on each node:
+
+	ginkgo -nodes=N -timelineFile=timeline.json
+
+The timeline is written to the suite's directory in the Chrome Trace Event format; open it in chrome://tracing or https://ui.perfetto.dev.  Timelines are only recorded when Ginkgo aggregates the nodes' output, i.e. not with -stream.
+
+Parallel nodes normally hold on to a spec's output until the spec completes.  To watch what a particular spec writes to the GinkgoWriter while it runs, or what any spec that has run for more than 10 seconds writes:
+
+	ginkgo -nodes=N -streamSpec="uploads large files"
+	ginkgo -nodes=N -streamSlowSpecs=10
+
+Each line is prefixed with the node it came from.  The report Ginkgo prints once the spec completes, output included, is unchanged.
+
+To run only the specs defined in a file, or those whose containers or subject span a particular line or range of lines:
+
+	ginkgo -focusFile=foo_test.go:123 -skipFile=slow_test.go
+
+File paths are regular expressions matched from the start of one of the path's components, so foo_test.go doesn't match xfoo_test.go.  Both flags may be repeated.
+
+To run only the specs that failed in the previous run, in only the suites they failed in:
+
+	ginkgo -r -keepGoing
+	ginkgo -r -rerunFailed
+
+After every run Ginkgo records the stable IDs of each suite's failed specs in a file under ginkgo/failed-specs in the user's cache directory, leaving the suite's source alone, and removes the file once the suite passes.
+-rerunFailed runs those specs by ID, with no regular expressions involved.  Suites that failed without any of their specs failing, e.g. to compile or in their BeforeSuite, packages that aren't Ginkgo suites, and suites whose failed specs no longer exist run in full.
+To run specs by ID yourself, pass the IDs recorded in JSON and JUnit reports with -focusID, which may be repeated, or list them one per line in a file passed with -focusIDFile.
+
+To split a suite across several machines, run each machine with a different shard:
+
+	ginkgo -shard=1/3
+	ginkgo -shard=2/3
+	ginkgo -shard=3/3
+
+Specs are assigned to shards by a hash of their stable ID, independently of the random seed and of any focus, so together the shards run every spec exactly once.  Each shard can itself be run in parallel with -nodes.
+
+To keep running known-flaky specs without letting them fail the suite:
+
+	ginkgo -quarantine=quarantine.json
+
+where quarantine.json is a JSON array of entries such as {"text": "Foo does bar", "reason": "ISSUE-123", "expires": "2021-12-31"}.
+Entries identify specs with one of "text" (the full spec text), "regex" (a regular expression over the spec text) or "location" (file:line).
+Failures of quarantined specs are reported separately and do not fail the suite.  Expired entries no longer apply and are reported as warnings.
+
 By default, when running multiple tests (with -r or a list of packages) Ginkgo will abort when a test fails.  To have Ginkgo run subsequent test suites instead you can:
 
 	ginkgo -keepGoing
@@ -178,6 +304,8 @@ func init() {
 	Commands = append(Commands, BuildVersionCommand())
 	Commands = append(Commands, BuildHelpCommand())
 	Commands = append(Commands, BuildOutlineCommand())
+	Commands = append(Commands, BuildReportCommand())
+	Commands = append(Commands, BuildCompareCommand())
 }
 
 func main() {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"regexp"
@@ -103,7 +104,8 @@ func (n *Notifier) SendNotification(title string, subtitle string) {
 	}
 }
 
-func (n *Notifier) RunCommand(suite testsuite.TestSuite, suitePassed bool) {
+//RunCommand runs the -afterSuiteHook command, printing its output to out
+func (n *Notifier) RunCommand(suite testsuite.TestSuite, suitePassed bool, out io.Writer) {
 
 	command := n.commandFlags.AfterSuiteHook
 	if command != "" {
@@ -122,19 +124,19 @@ func (n *Notifier) RunCommand(suite testsuite.TestSuite, suitePassed bool) {
 
 		output, err := exec.Command(parts[0], parts[1:]...).CombinedOutput()
 		if err != nil {
-			fmt.Println("Post-suite command failed:")
+			fmt.Fprintln(out, "Post-suite command failed:")
 			if config.DefaultReporterConfig.NoColor {
-				fmt.Printf("\t%s\n", output)
+				fmt.Fprintf(out, "\t%s\n", output)
 			} else {
-				fmt.Printf("\t%s%s%s\n", redColor, string(output), defaultStyle)
+				fmt.Fprintf(out, "\t%s%s%s\n", redColor, string(output), defaultStyle)
 			}
 			n.SendNotification("Ginkgo [ERROR]", fmt.Sprintf(`After suite command "%s" failed`, n.commandFlags.AfterSuiteHook))
 		} else {
-			fmt.Println("Post-suite command succeeded:")
+			fmt.Fprintln(out, "Post-suite command succeeded:")
 			if config.DefaultReporterConfig.NoColor {
-				fmt.Printf("\t%s\n", output)
+				fmt.Fprintf(out, "\t%s\n", output)
 			} else {
-				fmt.Printf("\t%s%s%s\n", greenColor, string(output), defaultStyle)
+				fmt.Fprintf(out, "\t%s%s%s\n", greenColor, string(output), defaultStyle)
 			}
 		}
 	}
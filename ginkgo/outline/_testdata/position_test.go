@@ -4,8 +4,8 @@ import (
 	. "github.com/hackrish007/ginkgo"
 )
 
-// Describe start=101, end=237
-var _ = Describe("101,237", func() {
+// Describe start=108, end=244
+var _ = Describe("108,244", func() {
 
 	/*
 	* block comment
@@ -14,8 +14,8 @@ var _ = Describe("101,237", func() {
 
 	// line comment
 
-	// It start=206, end=233
-	It("206,233", func() {
+	// It start=213, end=240
+	It("213,240", func() {
 
 	})
 
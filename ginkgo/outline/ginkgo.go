@@ -4,6 +4,8 @@ import (
 	"go/ast"
 	"go/token"
 	"strconv"
+
+	"github.com/hackrish007/ginkgo/internal/specid"
 )
 
 const (
@@ -28,6 +30,10 @@ type ginkgoMetadata struct {
 	Spec    bool `json:"spec"`
 	Focused bool `json:"focused"`
 	Pending bool `json:"pending"`
+
+	// ID is the stable identifier Ginkgo assigns to the spec at runtime. It is
+	// empty for containers.
+	ID string `json:"id,omitempty"`
 }
 
 // ginkgoNode is used to construct the outline as a tree
@@ -101,6 +107,55 @@ func (n *ginkgoNode) BackpropagateUnfocus() {
 
 }
 
+// AssignSpecIDs sets the ID of every spec in the subtree rooted at n, using the
+// same scheme Ginkgo uses when it constructs the tree of specs at runtime.
+func (n *ginkgoNode) AssignSpecIDs(fileName string) {
+	disambiguator := specid.NewDisambiguator()
+	texts := []string{}
+	var assign func(thisNode *ginkgoNode, entryIndex int)
+	assign = func(thisNode *ginkgoNode, entryIndex int) {
+		if thisNode.Spec {
+			id := specid.New(fileName, append(texts, thisNode.Text), entryIndex)
+			thisNode.ID = disambiguator.Disambiguate(id)
+			return
+		}
+		prefix, isContainer := containerTextPrefixes[thisNode.Name]
+		if isContainer {
+			texts = append(texts, prefix+thisNode.Text)
+		}
+		entries := 0
+		for _, childNode := range thisNode.Nodes {
+			if entryNames[childNode.Name] {
+				assign(childNode, entries)
+				entries++
+			} else {
+				assign(childNode, specid.NotATableEntry)
+			}
+		}
+		if isContainer {
+			texts = texts[0 : len(texts)-1]
+		}
+	}
+	for _, childNode := range n.Nodes {
+		assign(childNode, specid.NotATableEntry)
+	}
+}
+
+// containerTextPrefixes maps the names of the containers whose text is part of a
+// spec's ID to the prefix Ginkgo adds to their text
+var containerTextPrefixes = map[string]string{
+	"Describe": "", "FDescribe": "", "PDescribe": "", "XDescribe": "",
+	"Context": "", "FContext": "", "PContext": "", "XContext": "",
+	"When": "when ", "FWhen": "when ", "PWhen": "when ", "XWhen": "when ",
+	"DescribeTable": "", "FDescribeTable": "", "PDescribeTable": "", "XDescribeTable": "",
+}
+
+// entryNames are the names of the table entries, which are identified by their
+// index within their table
+var entryNames = map[string]bool{
+	"Entry": true, "FEntry": true, "PEntry": true, "XEntry": true,
+}
+
 func packageAndIdentNamesFromCallExpr(ce *ast.CallExpr) (string, string, bool) {
 	switch ex := ce.Fun.(type) {
 	case *ast.Ident:
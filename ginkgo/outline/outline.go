@@ -1,11 +1,11 @@
 package outline
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/token"
-	"strings"
 
 	"golang.org/x/tools/go/ast/inspector"
 )
@@ -67,6 +67,8 @@ func FromASTFile(fset *token.FileSet, src *ast.File) (*outline, error) {
 	root.BackpropagateUnfocus()
 	// Now, propagate inherited properties, including focused and pending.
 	root.PropagateInheritedProperties()
+	// Finally, derive the stable IDs Ginkgo assigns to the specs at runtime.
+	root.AssignSpecIDs(fset.File(src.Pos()).Name())
 
 	return &outline{root.Nodes}, nil
 }
@@ -89,12 +91,12 @@ func (o *outline) String() string {
 // one 'width' of spaces for every level of nesting.
 func (o *outline) StringIndent(width int) string {
 	var b bytes.Buffer
-	b.WriteString("Name,Text,Start,End,Spec,Focused,Pending\n")
+	b.WriteString("Name,Text,Start,End,Spec,Focused,Pending,ID\n")
 
 	currentIndent := 0
 	pre := func(n *ginkgoNode) {
 		b.WriteString(fmt.Sprintf("%*s", currentIndent, ""))
-		b.WriteString(fmt.Sprintf("%s,%s,%d,%d,%t,%t,%t\n", n.Name, n.Text, n.Start, n.End, n.Spec, n.Focused, n.Pending))
+		b.WriteString(fmt.Sprintf("%s,%s,%d,%d,%t,%t,%t,%s\n", n.Name, n.Text, n.Start, n.End, n.Spec, n.Focused, n.Pending, n.ID))
 		currentIndent += width
 	}
 	post := func(n *ginkgoNode) {
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/internal/spec"
+)
+
+// prepareQuarantine validates the -quarantine file, warns out about expired
+// entries and makes the path absolute so that test binaries running in their
+// package directories can find it.
+func prepareQuarantine(out io.Writer) {
+	if config.GinkgoConfig.Quarantine == "" {
+		return
+	}
+
+	path, err := filepath.Abs(config.GinkgoConfig.Quarantine)
+	if err != nil {
+		complainAndQuit(fmt.Sprintf("Invalid quarantine file: %s", err.Error()))
+	}
+	entries, err := spec.LoadQuarantine(path)
+	if err != nil {
+		complainAndQuit(err.Error())
+	}
+	config.GinkgoConfig.Quarantine = path
+
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.Expired(now) {
+			message := fmt.Sprintf("Warning: quarantine entry %q expired on %s and no longer applies", entry.Identifier(), entry.Expires)
+			if entry.Reason != "" {
+				message += fmt.Sprintf(" (%s)", entry.Reason)
+			}
+			fmt.Fprintln(out, message)
+		}
+	}
+}
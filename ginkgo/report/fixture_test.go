@@ -0,0 +1,64 @@
+package report_test
+
+import (
+	"time"
+
+	"github.com/hackrish007/ginkgo/types"
+)
+
+func specReport(id string, texts []string, state types.SpecState, runTime time.Duration) types.SpecReport {
+	locations := []types.CodeLocation{{FileName: "/src/suite_test.go", LineNumber: 1}}
+	for i := range texts {
+		locations = append(locations, types.CodeLocation{FileName: "/src/widget_test.go", LineNumber: 10 * (i + 1)})
+	}
+	return types.SpecReport{
+		ID:                     id,
+		ComponentTexts:         append([]string{"[Top Level]"}, texts...),
+		ComponentCodeLocations: locations,
+		State:                  state.String(),
+		RunTime:                runTime,
+		ParallelNode:           1,
+	}
+}
+
+//fixtureReports returns the reports of a run of two suites: one with a spec in each state, and one that failed to compile
+func fixtureReports() []types.SuiteReport {
+	failed := specReport("fails", []string{"Widget", "when resized", "fails"}, types.SpecStateFailed, 1500*time.Millisecond)
+	failed.Failure = &types.FailureReport{Message: "Expected <int>: 1\nto equal 2", Location: types.CodeLocation{FileName: "/src/widget_test.go", LineNumber: 32}}
+	failed.CapturedOutput = "resizing\n"
+
+	flakedAttempt := specReport("flakes", []string{"Widget", "flakes"}, types.SpecStateFailed, time.Second)
+	flakedAttempt.Failure = &types.FailureReport{Message: "nope"}
+	flaked := specReport("flakes", []string{"Widget", "flakes"}, types.SpecStatePassed, 2*time.Second)
+
+	quarantined := specReport("quarantined", []string{"Widget", "is | quarantined"}, types.SpecStatePanicked, 0)
+	quarantined.Quarantined = true
+	quarantined.QuarantineReason = "ISSUE-123"
+	quarantined.Failure = &types.FailureReport{Message: "Test Panicked", ForwardedPanic: "boom"}
+
+	return []types.SuiteReport{
+		{
+			SuiteDescription: "Widget Suite",
+			SuitePath:        "/src",
+			RunTime:          5 * time.Second,
+			AfterSuite: &types.SetupReport{
+				State:   types.SpecStateFailed.String(),
+				Failure: &types.FailureReport{Message: "cleanup failed", Location: types.CodeLocation{FileName: "/src/suite_test.go", LineNumber: 20}},
+			},
+			Specs: []types.SpecReport{
+				specReport("renders", []string{"Widget", "renders <b>"}, types.SpecStatePassed, 3*time.Second),
+				failed,
+				flakedAttempt,
+				flaked,
+				quarantined,
+				specReport("pends", []string{"Widget", "pends"}, types.SpecStatePending, 0),
+				specReport("skips", []string{"skips"}, types.SpecStateSkipped, 0),
+			},
+		},
+		{
+			SuiteDescription:   "broken",
+			SuitePath:          "/src/broken",
+			CompilationFailure: "Failed to compile broken:\n\nundefined: x",
+		},
+	}
+}
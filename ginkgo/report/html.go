@@ -0,0 +1,298 @@
+package report
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/hackrish007/ginkgo/types"
+)
+
+// HTML returns a self-contained HTML page describing reports, listing the
+// slowest specs of the run.
+func HTML(reports []types.SuiteReport, slowest int) (string, error) {
+	buffer := &bytes.Buffer{}
+	err := htmlTemplate.Execute(buffer, newHTMLPage(newSummary(reports, slowest)))
+	return buffer.String(), err
+}
+
+type htmlPage struct {
+	Succeeded bool
+	Tally     string
+	RunTime   string
+	States    []htmlState
+	Failures  []failure
+	Slowest   []htmlSlowSpec
+	Suites    []htmlSuite
+}
+
+type htmlState struct {
+	Name  string
+	Count int
+}
+
+type htmlSlowSpec struct {
+	Anchor  string
+	Suite   string
+	Text    string
+	RunTime string
+}
+
+type htmlSuite struct {
+	Anchor             string
+	Description        string
+	Path               string
+	Succeeded          bool
+	Tally              string
+	RunTime            string
+	CompilationFailure string
+	BeforeSuite        *failure
+	AfterSuite         *failure
+	Nodes              []*htmlNode
+}
+
+// htmlNode is a container, holding further containers and specs, or a spec.
+type htmlNode struct {
+	Text     string
+	Location string
+	Children []*htmlNode
+	Spec     *htmlSpec
+}
+
+type htmlSpec struct {
+	Anchor     string
+	State      string
+	Failed     bool
+	Text       string
+	SearchText string
+	RunTime    string
+	Location   string
+	Node       int
+	Attempts   int
+	Quarantine string
+	Failure    string
+	FailedAt   string
+	Output     string
+}
+
+func newHTMLPage(s summary) htmlPage {
+	page := htmlPage{
+		Succeeded: s.Succeeded,
+		Tally:     tallyText(s.Tally),
+		RunTime:   formatDuration(s.RunTime),
+		Failures:  s.Failures,
+	}
+	for _, state := range stateOrder {
+		if s.Tally[state] > 0 {
+			page.States = append(page.States, htmlState{Name: state, Count: s.Tally[state]})
+		}
+	}
+
+	for i, report := range s.Reports {
+		suite := htmlSuite{
+			Anchor:             suiteAnchor(i),
+			Description:        report.SuiteDescription,
+			Path:               report.SuitePath,
+			Succeeded:          suiteSucceeded(report),
+			Tally:              tallyText(newTally(s.Specs[i])),
+			RunTime:            formatDuration(report.RunTime),
+			CompilationFailure: report.CompilationFailure,
+		}
+		for j := range s.Failures {
+			switch s.Failures[j].Anchor {
+			case suite.Anchor + "-BeforeSuite":
+				suite.BeforeSuite = &s.Failures[j]
+			case suite.Anchor + "-AfterSuite":
+				suite.AfterSuite = &s.Failures[j]
+			}
+		}
+		root := &htmlNode{}
+		for _, sp := range s.Specs[i] {
+			locations := sp.Report.ComponentCodeLocations
+			if len(locations) > 0 {
+				locations = locations[1:]
+			}
+			root.add(sp.Report.ComponentTexts[1:], locations, newHTMLSpec(sp))
+		}
+		suite.Nodes = root.Children
+		page.Suites = append(page.Suites, suite)
+	}
+
+	for _, sp := range s.Slowest {
+		page.Slowest = append(page.Slowest, htmlSlowSpec{
+			Anchor:  sp.Anchor,
+			Suite:   sp.Suite,
+			Text:    sp.Text(),
+			RunTime: formatDuration(sp.Report.RunTime),
+		})
+	}
+	return page
+}
+
+func newHTMLSpec(sp spec) *htmlSpec {
+	texts := sp.Report.ComponentTexts
+	htmlSpec := &htmlSpec{
+		Anchor:     sp.Anchor,
+		State:      sp.State,
+		Failed:     sp.Failed(),
+		Text:       texts[len(texts)-1],
+		SearchText: strings.ToLower(sp.Suite + " " + sp.Text()),
+		RunTime:    formatDuration(sp.Report.RunTime),
+		Location:   sp.Location(),
+		Node:       sp.Report.ParallelNode,
+		Attempts:   sp.Attempts,
+		Output:     sp.Report.CapturedOutput,
+	}
+	if sp.Report.Quarantined {
+		htmlSpec.Quarantine = sp.Report.QuarantineReason
+		if htmlSpec.Quarantine == "" {
+			htmlSpec.Quarantine = "quarantined"
+		}
+	}
+	if sp.Report.Failure != nil {
+		htmlSpec.Failure = failureText(sp.Report.Failure)
+		if sp.Report.Failure.Location.FileName != "" {
+			htmlSpec.FailedAt = sp.Report.Failure.Location.String()
+		}
+	}
+	return htmlSpec
+}
+
+// add adds a spec to the tree below node, creating the containers along the
+// way that don't exist yet.  texts and locations are those of the spec's
+// containers followed by those of the spec.
+func (node *htmlNode) add(texts []string, locations []types.CodeLocation, spec *htmlSpec) {
+	if len(texts) <= 1 {
+		node.Children = append(node.Children, &htmlNode{Spec: spec})
+		return
+	}
+	location := ""
+	if len(locations) > 0 {
+		location = locations[0].String()
+		locations = locations[1:]
+	}
+	for _, child := range node.Children {
+		if child.Spec == nil && child.Text == texts[0] && child.Location == location {
+			child.add(texts[1:], locations, spec)
+			return
+		}
+	}
+	child := &htmlNode{Text: texts[0], Location: location}
+	node.Children = append(node.Children, child)
+	child.add(texts[1:], locations, spec)
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`{{define "node"}}{{if .Spec}}{{template "spec" .Spec}}{{else}}<details class="container" open>
+<summary>{{.Text}} <span class="location">{{.Location}}</span></summary>
+{{range .Children}}{{template "node" .}}{{end}}</details>
+{{end}}{{end -}}
+{{define "spec"}}<details class="spec" id="{{.Anchor}}" data-state="{{.State}}" data-text="{{.SearchText}}"{{if .Failed}} open{{end}}>
+<summary><span class="badge {{.State}}">{{.State}}</span> {{.Text}} <span class="time">{{.RunTime}}</span></summary>
+<dl>
+<dt>Location</dt><dd>{{.Location}}</dd>
+{{if .Node}}<dt>Node</dt><dd>{{.Node}}</dd>
+{{end}}{{if gt .Attempts 1}}<dt>Attempts</dt><dd>{{.Attempts}}</dd>
+{{end}}{{if .Quarantine}}<dt>Quarantined</dt><dd>{{.Quarantine}}</dd>
+{{end}}</dl>
+{{if .Failure}}<pre class="failure">{{.Failure}}{{if .FailedAt}}
+{{.FailedAt}}{{end}}</pre>
+{{end}}{{if .Output}}<pre class="output">{{.Output}}</pre>
+{{end}}</details>
+{{end -}}
+{{define "setup"}}<details class="setup" id="{{.Anchor}}" open>
+<summary><span class="badge {{.State}}">{{.State}}</span> {{.Name}}</summary>
+<pre class="failure">{{.Message}}{{if .Location}}
+{{.Location}}{{end}}</pre>
+{{if .Output}}<pre class="output">{{.Output}}</pre>
+{{end}}</details>
+{{end -}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Ginkgo Report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+h1 { margin-bottom: 0.2em; }
+h2 { border-bottom: 1px solid #e1e4e8; padding-bottom: 0.2em; }
+a { color: #0366d6; text-decoration: none; }
+pre { background: #f6f8fa; padding: 0.8em; overflow-x: auto; white-space: pre-wrap; }
+pre.failure { background: #ffeef0; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #e1e4e8; padding: 0.3em 0.8em; text-align: left; }
+td.time { text-align: right; font-family: monospace; }
+details { margin: 0.2em 0 0.2em 1.2em; }
+summary { cursor: pointer; }
+.meta, .location, .time { color: #6a737d; font-size: 0.9em; }
+.badge { display: inline-block; min-width: 6em; padding: 0 0.4em; border-radius: 3px; color: #fff; font-size: 0.8em; text-align: center; text-transform: uppercase; }
+.passed { background: #28a745; }
+.failed, .panicked, .timedout { background: #d73a49; }
+.flaked, .quarantined { background: #e36209; }
+.pending, .skipped { background: #6a737d; }
+#filters { position: sticky; top: 0; background: #fff; padding: 0.5em 0; border-bottom: 1px solid #e1e4e8; }
+#filters label { margin-right: 1em; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 0.2em 1em; }
+dt { color: #6a737d; }
+dd { margin: 0; }
+</style>
+</head>
+<body>
+<h1>Ginkgo Report</h1>
+<p><span class="badge {{if .Succeeded}}passed{{else}}failed{{end}}">{{if .Succeeded}}passed{{else}}failed{{end}}</span> {{len .Suites}} suite{{if ne (len .Suites) 1}}s{{end}} &middot; {{.Tally}} &middot; {{.RunTime}}</p>
+{{if .Failures}}
+<h2>Failures</h2>
+<ul>
+{{range .Failures}}<li><a href="#{{.Anchor}}"><span class="badge {{.State}}">{{.State}}</span> {{.Name}}</a> <span class="meta">{{.Suite}}</span></li>
+{{end}}</ul>
+{{end}}
+{{if .Slowest}}
+<h2>Slowest specs</h2>
+<table>
+<tr><th>Spec</th><th>Suite</th><th>Run time</th></tr>
+{{range .Slowest}}<tr><td><a href="#{{.Anchor}}">{{.Text}}</a></td><td>{{.Suite}}</td><td class="time">{{.RunTime}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Specs</h2>
+<div id="filters">
+<input type="search" id="filter-text" placeholder="Filter specs by text">
+{{range .States}}<label><input type="checkbox" class="filter-state" value="{{.Name}}" checked> <span class="badge {{.Name}}">{{.Name}}</span> {{.Count}}</label>
+{{end}}</div>
+{{range .Suites}}
+<details class="suite" id="{{.Anchor}}" open>
+<summary><span class="badge {{if .Succeeded}}passed{{else}}failed{{end}}">{{if .Succeeded}}passed{{else}}failed{{end}}</span> <strong>{{.Description}}</strong> <span class="meta">{{.Path}} &middot; {{.Tally}} &middot; {{.RunTime}}</span></summary>
+{{if .CompilationFailure}}<pre class="failure">{{.CompilationFailure}}</pre>{{end}}
+{{with .BeforeSuite}}{{template "setup" .}}{{end}}
+{{range .Nodes}}{{template "node" .}}{{end}}
+{{with .AfterSuite}}{{template "setup" .}}{{end}}
+</details>
+{{end}}
+<script>
+(function() {
+  var text = document.getElementById("filter-text");
+  var states = document.querySelectorAll(".filter-state");
+  function filter() {
+    var shown = {};
+    states.forEach(function(state) { shown[state.value] = state.checked; });
+    var query = text.value.toLowerCase();
+    document.querySelectorAll("details.spec").forEach(function(spec) {
+      spec.hidden = !shown[spec.dataset.state] || spec.dataset.text.indexOf(query) === -1;
+    });
+    var containers = Array.prototype.slice.call(document.querySelectorAll("details.container"));
+    containers.reverse().forEach(function(container) {
+      container.hidden = container.querySelector("details.spec:not([hidden])") === null;
+    });
+  }
+  text.addEventListener("input", filter);
+  states.forEach(function(state) { state.addEventListener("change", filter); });
+  if (window.location.hash) {
+    var target = document.getElementById(window.location.hash.substring(1));
+    for (var element = target; element; element = element.parentElement) {
+      if (element.tagName === "DETAILS") { element.open = true; }
+    }
+  }
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,61 @@
+package report_test
+
+import (
+	"strings"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/ginkgo/report"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("HTML", func() {
+	var page string
+
+	BeforeEach(func() {
+		var err error
+		page, err = report.HTML(fixtureReports(), 2)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("is self-contained", func() {
+		Ω(page).Should(HavePrefix("<!DOCTYPE html>"))
+		Ω(page).Should(HaveSuffix("</html>\n"))
+		Ω(page).ShouldNot(ContainSubstring("<link"))
+		Ω(page).ShouldNot(ContainSubstring("src="))
+		Ω(page).ShouldNot(ContainSubstring("http"))
+	})
+
+	It("summarizes the run and offers a filter for each state of its specs", func() {
+		Ω(page).Should(ContainSubstring("2 suites &middot; 1 failed, 1 flaked, 1 quarantined, 1 passed, 1 pending, 1 skipped &middot; 5.000s"))
+		for _, state := range []string{"failed", "flaked", "quarantined", "passed", "pending", "skipped"} {
+			Ω(page).Should(ContainSubstring(`class="filter-state" value="` + state + `" checked>`))
+		}
+		Ω(page).ShouldNot(ContainSubstring(`value="panicked"`))
+	})
+
+	It("nests specs in their containers, opening failed specs", func() {
+		container := strings.Index(page, "<summary>when resized <span class=\"location\">/src/widget_test.go:20</span></summary>")
+		spec := strings.Index(page, `<details class="spec" id="suite-1-spec-2" data-state="failed" data-text="widget suite widget when resized fails" open>`)
+		Ω(container).Should(BeNumerically(">", 0))
+		Ω(spec).Should(BeNumerically(">", container))
+		Ω(strings.Count(page, "<summary>Widget <span")).Should(Equal(1))
+		Ω(page).Should(ContainSubstring("<pre class=\"failure\">Expected &lt;int&gt;: 1\nto equal 2\n/src/widget_test.go:32</pre>"))
+		Ω(page).Should(ContainSubstring("<pre class=\"output\">resizing\n</pre>"))
+	})
+
+	It("describes retried and quarantined specs", func() {
+		Ω(page).Should(ContainSubstring(`id="suite-1-spec-3" data-state="flaked"`))
+		Ω(page).Should(ContainSubstring("<dt>Attempts</dt><dd>2</dd>"))
+		Ω(page).Should(ContainSubstring("<dt>Quarantined</dt><dd>ISSUE-123</dd>"))
+		Ω(page).Should(ContainSubstring("Test Panicked\nboom"))
+	})
+
+	It("links failures and the slowest specs to their details", func() {
+		Ω(page).Should(ContainSubstring(`<a href="#suite-1-spec-2"><span class="badge failed">failed</span> Widget when resized fails</a>`))
+		Ω(page).Should(ContainSubstring(`<a href="#suite-1-AfterSuite">`))
+		Ω(page).Should(ContainSubstring(`<details class="setup" id="suite-1-AfterSuite" open>`))
+		Ω(page).Should(ContainSubstring(`<a href="#suite-2">`))
+		Ω(page).Should(ContainSubstring(`<tr><td><a href="#suite-1-spec-1">Widget renders &lt;b&gt;</a></td><td>Widget Suite</td><td class="time">3.000s</td></tr>`))
+		Ω(page).ShouldNot(ContainSubstring(`<a href="#suite-1-spec-2">Widget when resized fails</a></td>`))
+	})
+})
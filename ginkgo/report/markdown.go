@@ -0,0 +1,101 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hackrish007/ginkgo/types"
+)
+
+// markdownOutputLines is the number of lines of a failing spec's captured
+// output the Markdown summary holds.  Earlier lines are left out to keep the
+// summary short enough for a pull request comment.
+const markdownOutputLines = 20
+
+// Markdown returns a Markdown summary of reports, listing the slowest specs
+// of the run.
+func Markdown(reports []types.SuiteReport, slowest int) string {
+	s := newSummary(reports, slowest)
+
+	md := "## Ginkgo Report\n\n"
+	md += fmt.Sprintf("**%s** · %s · %s · %s\n\n", strings.ToUpper(resultText(s.Succeeded)), pluralize(len(reports), "suite"), tallyText(s.Tally), formatDuration(s.RunTime))
+
+	md += "| Suite | Result | Passed | Failed | Flaked | Quarantined | Pending | Skipped | Run time |\n"
+	md += "| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n"
+	for i, report := range reports {
+		t := newTally(s.Specs[i])
+		md += fmt.Sprintf("| %s | %s | %d | %d | %d | %d | %d | %d | %s |\n",
+			markdownText(report.SuiteDescription), resultText(suiteSucceeded(report)),
+			t[statePassed], t.Failed(), t[stateFlaked], t[stateQuarantined],
+			t[types.SpecStatePending.String()], t[types.SpecStateSkipped.String()],
+			formatDuration(report.RunTime))
+	}
+
+	if len(s.Failures) > 0 {
+		md += "\n### Failures\n"
+		for _, f := range s.Failures {
+			md += fmt.Sprintf("\n<details>\n<summary><b>[%s]</b> %s <i>%s</i></summary>\n\n", strings.ToUpper(f.State), htmlReplacer.Replace(f.Name), htmlReplacer.Replace(f.Suite))
+			message := f.Message
+			if f.Location != "" {
+				message += "\n" + f.Location
+			}
+			md += codeBlock(message)
+			if f.Output != "" {
+				md += "\nCaptured output"
+				output, omitted := lastLines(f.Output, markdownOutputLines)
+				if omitted > 0 {
+					md += fmt.Sprintf(", without the first %s", pluralize(omitted, "line"))
+				}
+				md += ":\n\n" + codeBlock(output)
+			}
+			md += "</details>\n"
+		}
+	}
+
+	if len(s.Slowest) > 0 {
+		md += "\n### Slowest specs\n\n"
+		md += "| Spec | Suite | Run time |\n"
+		md += "| --- | --- | ---: |\n"
+		for _, sp := range s.Slowest {
+			md += fmt.Sprintf("| %s | %s | %s |\n", markdownText(sp.Text()), markdownText(sp.Suite), formatDuration(sp.Report.RunTime))
+		}
+	}
+	return md
+}
+
+func resultText(succeeded bool) string {
+	if succeeded {
+		return "passed"
+	}
+	return "failed"
+}
+
+var htmlReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// markdownText escapes text so that it renders as is in a table cell.
+func markdownText(text string) string {
+	for _, special := range []string{"\\", "`", "*", "_", "[", "]", "|"} {
+		text = strings.Replace(text, special, "\\"+special, -1)
+	}
+	text = htmlReplacer.Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// codeBlock fences text with enough backticks that none of its own end the
+// block.
+func codeBlock(text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf("%s\n%s\n%s\n", fence, strings.TrimRight(text, "\n"), fence)
+}
+
+// lastLines returns the last n lines of text and the number of lines left out.
+func lastLines(text string, n int) (string, int) {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n"), 0
+	}
+	return strings.Join(lines[len(lines)-n:], "\n"), len(lines) - n
+}
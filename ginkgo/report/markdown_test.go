@@ -0,0 +1,76 @@
+package report_test
+
+import (
+	"strings"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/ginkgo/report"
+	"github.com/hackrish007/ginkgo/types"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("Markdown", func() {
+	It("tabulates the suites, lists failures and the slowest specs", func() {
+		Ω(report.Markdown(fixtureReports(), 3)).Should(Equal("## Ginkgo Report\n" +
+			"\n" +
+			"**FAILED** · 2 suites · 1 failed, 1 flaked, 1 quarantined, 1 passed, 1 pending, 1 skipped · 5.000s\n" +
+			"\n" +
+			"| Suite | Result | Passed | Failed | Flaked | Quarantined | Pending | Skipped | Run time |\n" +
+			"| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n" +
+			"| Widget Suite | failed | 1 | 1 | 1 | 1 | 1 | 1 | 5.000s |\n" +
+			"| broken | failed | 0 | 0 | 0 | 0 | 0 | 0 | 0.000s |\n" +
+			"\n" +
+			"### Failures\n" +
+			"\n" +
+			"<details>\n" +
+			"<summary><b>[FAILED]</b> Widget when resized fails <i>Widget Suite</i></summary>\n" +
+			"\n" +
+			"```\nExpected <int>: 1\nto equal 2\n/src/widget_test.go:32\n```\n" +
+			"\n" +
+			"Captured output:\n" +
+			"\n" +
+			"```\nresizing\n```\n" +
+			"</details>\n" +
+			"\n" +
+			"<details>\n" +
+			"<summary><b>[FAILED]</b> AfterSuite <i>Widget Suite</i></summary>\n" +
+			"\n" +
+			"```\ncleanup failed\n/src/suite_test.go:20\n```\n" +
+			"</details>\n" +
+			"\n" +
+			"<details>\n" +
+			"<summary><b>[FAILED]</b> Compilation <i>broken</i></summary>\n" +
+			"\n" +
+			"```\nFailed to compile broken:\n\nundefined: x\n```\n" +
+			"</details>\n" +
+			"\n" +
+			"### Slowest specs\n" +
+			"\n" +
+			"| Spec | Suite | Run time |\n" +
+			"| --- | --- | ---: |\n" +
+			"| Widget renders &lt;b&gt; | Widget Suite | 3.000s |\n" +
+			"| Widget flakes | Widget Suite | 2.000s |\n" +
+			"| Widget when resized fails | Widget Suite | 1.500s |\n"))
+	})
+
+	It("keeps only the last lines of long captured output, and fences code that holds backticks", func() {
+		reports := fixtureReports()[:1]
+		failed := &reports[0].Specs[1]
+		failed.Failure.Message = "```go\nx := 1\n```"
+		failed.CapturedOutput = ""
+		for i := 1; i <= 25; i++ {
+			failed.CapturedOutput += strings.Repeat("x", i) + "\n"
+		}
+
+		md := report.Markdown(reports, 0)
+		Ω(md).Should(ContainSubstring("````\n```go\nx := 1\n```\n/src/widget_test.go:32\n````\n"))
+		Ω(md).Should(ContainSubstring("Captured output, without the first 5 lines:\n\n```\nxxxxxx\n"))
+		Ω(md).ShouldNot(ContainSubstring("Slowest specs"))
+	})
+
+	It("escapes spec texts in tables", func() {
+		reports := []types.SuiteReport{{SuiteDescription: "A_B", SuiteSucceeded: true}}
+		Ω(report.Markdown(reports, 10)).Should(ContainSubstring("| A\\_B | passed |"))
+		Ω(report.Markdown(fixtureReports(), 10)).Should(ContainSubstring("| Widget is \\| quarantined | Widget Suite | 0.000s |"))
+	})
+})
@@ -0,0 +1,272 @@
+/*
+Package report renders the JSON reports Ginkgo writes with -jsonReport as a
+self-contained HTML page and as a Markdown summary.
+
+The HTML page needs no external assets: it holds a collapsible tree of each
+suite's containers and specs that can be filtered by state and text, the
+details of every failure along with the failing spec's captured output, and the
+slowest specs of the run.  The Markdown summary tabulates the suites, lists the
+failures and the slowest specs, and fits in a pull request comment.
+
+Specs retried with -flakeAttempts are described by their last attempt.  Specs
+that passed after failing are reported as flaked.
+*/
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hackrish007/ginkgo/types"
+)
+
+// The states a spec is reported with.  A spec that passed after failing has
+// flaked, and a failure of a quarantined spec is quarantined.
+const (
+	statePassed      = "passed"
+	stateFlaked      = "flaked"
+	stateQuarantined = "quarantined"
+)
+
+// stateOrder is the order the states of specs are listed in, most pressing
+// first.
+var stateOrder = []string{
+	types.SpecStateFailed.String(),
+	types.SpecStatePanicked.String(),
+	types.SpecStateTimedOut.String(),
+	stateFlaked,
+	stateQuarantined,
+	statePassed,
+	types.SpecStatePending.String(),
+	types.SpecStateSkipped.String(),
+}
+
+// spec is the last attempt at running a spec of a suite.  Anchor identifies it
+// in the HTML page.
+type spec struct {
+	Anchor   string
+	Suite    string
+	Report   types.SpecReport
+	State    string
+	Attempts int
+
+	FailedAttempts int
+}
+
+// Text returns the text of the spec's containers and of the spec, joined by
+// spaces.
+func (s spec) Text() string {
+	return strings.Join(s.Report.ComponentTexts[1:], " ")
+}
+
+// Failed returns true if the spec failed the suite.
+func (s spec) Failed() bool {
+	return s.Report.HasFailureState() && !s.Report.Quarantined
+}
+
+// Location returns where the spec is defined.
+func (s spec) Location() string {
+	locations := s.Report.ComponentCodeLocations
+	if len(locations) == 0 {
+		return ""
+	}
+	return locations[len(locations)-1].String()
+}
+
+// suiteSpecs returns the last attempt at running each spec of a suite, in the
+// order the specs first ran.
+func suiteSpecs(report types.SuiteReport) []spec {
+	specs := []spec{}
+	indices := map[string]int{}
+	for _, specReport := range report.Specs {
+		key := specReport.ID
+		if key == "" {
+			key = strings.Join(specReport.ComponentTexts, " ")
+		}
+		i, retried := indices[key]
+		if !retried {
+			i = len(specs)
+			indices[key] = i
+			specs = append(specs, spec{Suite: report.SuiteDescription})
+		}
+		specs[i].Report = specReport
+		specs[i].Attempts++
+		if specReport.HasFailureState() {
+			specs[i].FailedAttempts++
+		}
+	}
+	for i := range specs {
+		specs[i].State = specs[i].Report.State
+		switch {
+		case specs[i].Report.HasQuarantinedFailure():
+			specs[i].State = stateQuarantined
+		case specs[i].State == statePassed && specs[i].FailedAttempts > 0:
+			specs[i].State = stateFlaked
+		}
+	}
+	return specs
+}
+
+// tally counts specs by state.
+type tally map[string]int
+
+func newTally(specs []spec) tally {
+	t := tally{}
+	for _, s := range specs {
+		t[s.State]++
+	}
+	return t
+}
+
+// Failed returns the number of specs that failed, panicked or timed out.
+func (t tally) Failed() int {
+	return t[types.SpecStateFailed.String()] + t[types.SpecStatePanicked.String()] + t[types.SpecStateTimedOut.String()]
+}
+
+func (t tally) add(other tally) {
+	for state, count := range other {
+		t[state] += count
+	}
+}
+
+// failure describes a failed spec, BeforeSuite or AfterSuite, or a suite that
+// failed to compile.  Anchor identifies it in the HTML page.
+type failure struct {
+	Anchor   string
+	Suite    string
+	Name     string
+	State    string
+	Message  string
+	Location string
+	Output   string
+}
+
+// summary describes the reports of a run.
+type summary struct {
+	Reports   []types.SuiteReport
+	Specs     [][]spec
+	Tally     tally
+	Failures  []failure
+	Slowest   []spec
+	RunTime   time.Duration
+	Succeeded bool
+}
+
+func newSummary(reports []types.SuiteReport, slowest int) summary {
+	s := summary{Reports: reports, Tally: tally{}, Succeeded: true}
+	all := []spec{}
+	for i, report := range reports {
+		specs := suiteSpecs(report)
+		for j := range specs {
+			specs[j].Anchor = specAnchor(i, j)
+		}
+		s.Specs = append(s.Specs, specs)
+		s.Tally.add(newTally(specs))
+		s.RunTime += report.RunTime
+		s.Succeeded = s.Succeeded && suiteSucceeded(report)
+		s.Failures = append(s.Failures, suiteFailures(i, report, specs)...)
+		for _, sp := range specs {
+			if sp.State != types.SpecStatePending.String() && sp.State != types.SpecStateSkipped.String() {
+				all = append(all, sp)
+			}
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Report.RunTime > all[j].Report.RunTime
+	})
+	if len(all) > slowest {
+		all = all[:slowest]
+	}
+	s.Slowest = all
+	return s
+}
+
+func suiteSucceeded(report types.SuiteReport) bool {
+	return report.SuiteSucceeded && report.CompilationFailure == ""
+}
+
+// suiteFailures returns the failures of the i-th suite of a run, in the order
+// they happened.
+func suiteFailures(i int, report types.SuiteReport, specs []spec) []failure {
+	failures := []failure{}
+	if report.CompilationFailure != "" {
+		failures = append(failures, failure{
+			Anchor:  suiteAnchor(i),
+			Suite:   report.SuiteDescription,
+			Name:    "Compilation",
+			State:   "failed",
+			Message: report.CompilationFailure,
+		})
+	}
+	setupFailure := func(name string, setup *types.SetupReport) {
+		if setup == nil || !setup.HasFailureState() {
+			return
+		}
+		f := failure{Anchor: fmt.Sprintf("%s-%s", suiteAnchor(i), name), Suite: report.SuiteDescription, Name: name, State: setup.State, Output: setup.CapturedOutput}
+		if setup.Failure != nil {
+			f.Message, f.Location = failureText(setup.Failure), setup.Failure.Location.String()
+		}
+		failures = append(failures, f)
+	}
+	setupFailure("BeforeSuite", report.BeforeSuite)
+	for _, sp := range specs {
+		if !sp.Failed() {
+			continue
+		}
+		f := failure{Anchor: sp.Anchor, Suite: report.SuiteDescription, Name: sp.Text(), State: sp.State, Output: sp.Report.CapturedOutput}
+		if sp.Report.Failure != nil {
+			f.Message, f.Location = failureText(sp.Report.Failure), sp.Report.Failure.Location.String()
+		}
+		failures = append(failures, f)
+	}
+	setupFailure("AfterSuite", report.AfterSuite)
+	return failures
+}
+
+func suiteAnchor(i int) string {
+	return fmt.Sprintf("suite-%d", i+1)
+}
+
+func specAnchor(i int, j int) string {
+	return fmt.Sprintf("suite-%d-spec-%d", i+1, j+1)
+}
+
+// failureText returns a failure's message, followed by the panic that caused
+// it, if any.
+func failureText(failure *types.FailureReport) string {
+	if failure == nil {
+		return ""
+	}
+	if failure.ForwardedPanic != "" {
+		return failure.Message + "\n" + failure.ForwardedPanic
+	}
+	return failure.Message
+}
+
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
+
+func pluralize(count int, word string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, word)
+	}
+	return fmt.Sprintf("%d %ss", count, word)
+}
+
+// tallyText describes the number of specs in each state, e.g. "3 passed, 1
+// failed".
+func tallyText(t tally) string {
+	parts := []string{}
+	for _, state := range stateOrder {
+		if t[state] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", t[state], state))
+		}
+	}
+	if len(parts) == 0 {
+		return "no specs"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,13 @@
+package report_test
+
+import (
+	"testing"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/gomega"
+)
+
+func TestReport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Report Suite")
+}
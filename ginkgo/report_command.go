@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hackrish007/ginkgo/ginkgo/report"
+	"github.com/hackrish007/ginkgo/reporters"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+func BuildReportCommand() *Command {
+	var htmlFile, markdownFile string
+	var slowest int
+	flagSet := flag.NewFlagSet("report", flag.ExitOnError)
+	flagSet.StringVar(&htmlFile, "html", "", "If set, write a self-contained HTML report to the given path.")
+	flagSet.StringVar(&markdownFile, "markdown", "", "If set, write a Markdown summary to the given path.  Without -html or -markdown the summary is printed to stdout.")
+	flagSet.IntVar(&slowest, "slowest", 10, "The number of slowest specs to list.")
+	return &Command{
+		Name:         "report",
+		FlagSet:      flagSet,
+		UsageCommand: "ginkgo report <FLAGS> <JSON REPORTS>",
+		Usage: []string{
+			"Render the JSON reports written with -jsonReport as an HTML page and as a Markdown summary",
+			"Accepts the following flags:",
+		},
+		Command: func(args []string, additionalArgs []string) {
+			renderReports(args, htmlFile, markdownFile, slowest)
+		},
+	}
+}
+
+func renderReports(args []string, htmlFile string, markdownFile string, slowest int) {
+	if len(args) == 0 {
+		complainAndQuit("Please pass the JSON reports to render")
+	}
+	reports := []types.SuiteReport{}
+	for _, arg := range args {
+		fileReports, err := reporters.ReadJSONReport(arg)
+		if err != nil {
+			complainAndQuit(err.Error())
+		}
+		reports = append(reports, fileReports...)
+	}
+
+	if htmlFile == "" && markdownFile == "" {
+		fmt.Print(report.Markdown(reports, slowest))
+		return
+	}
+	if htmlFile != "" {
+		page, err := report.HTML(reports, slowest)
+		if err != nil {
+			complainAndQuit(fmt.Sprintf("Failed to render the HTML report: %s", err.Error()))
+		}
+		writeRenderedReport(htmlFile, page)
+	}
+	if markdownFile != "" {
+		writeRenderedReport(markdownFile, report.Markdown(reports, slowest))
+	}
+}
+
+func writeRenderedReport(filename string, content string) {
+	err := os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+	if err == nil {
+		err = ioutil.WriteFile(filename, []byte(content), 0644)
+	}
+	if err != nil {
+		complainAndQuit(fmt.Sprintf("Failed to write %s: %s", filename, err.Error()))
+	}
+	fmt.Printf("Report was created: %s\n", filename)
+}
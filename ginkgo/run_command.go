@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"regexp"
@@ -28,7 +29,6 @@ func BuildRunCommand() *Command {
 		commandFlags:     commandFlags,
 		notifier:         notifier,
 		interruptHandler: interruptHandler,
-		suiteRunner:      NewSuiteRunner(notifier, interruptHandler),
 	}
 
 	return &Command{
@@ -48,7 +48,9 @@ type SpecRunner struct {
 	commandFlags     *RunWatchAndBuildCommandFlags
 	notifier         *Notifier
 	interruptHandler *interrupthandler.InterruptHandler
-	suiteRunner      *SuiteRunner
+
+	//out is where the CLI prints everything but test2json events, which always go to stdout
+	out io.Writer
 }
 
 func (r *SpecRunner) RunSpecs(args []string, additionalArgs []string) {
@@ -77,16 +79,27 @@ func (r *SpecRunner) RunSpecs(args []string, additionalArgs []string) {
 		fmt.Fprintln(colorable.NewColorableStderr(), deprecationTracker.DeprecationsReport())
 	}
 
+	//streamed output is prefixed with the node that printed it, which would garble test2json events, so parallel suites report through the CLI's server instead.
+	//Everything else the CLI prints goes to stderr, leaving stdout to the events.
+	r.out = os.Stdout
+	if config.DefaultReporterConfig.Test2JSON {
+		r.commandFlags.ParallelStream = false
+		r.out = os.Stderr
+	}
+	suiteRunner := NewSuiteRunner(r.notifier, r.interruptHandler, r.out)
+
+	prepareQuarantine(r.out)
+
 	suites, skippedPackages := findSuites(args, r.commandFlags.Recurse, r.commandFlags.SkipPackage, true)
 	if len(skippedPackages) > 0 {
-		fmt.Println("Will skip:")
+		fmt.Fprintln(r.out, "Will skip:")
 		for _, skippedPackage := range skippedPackages {
-			fmt.Println("  " + skippedPackage)
+			fmt.Fprintln(r.out, "  " + skippedPackage)
 		}
 	}
 
 	if len(skippedPackages) > 0 && len(suites) == 0 {
-		fmt.Println("All tests skipped!  Exiting...")
+		fmt.Fprintln(r.out, "All tests skipped!  Exiting...")
 		os.Exit(0)
 	}
 
@@ -94,15 +107,25 @@ func (r *SpecRunner) RunSpecs(args []string, additionalArgs []string) {
 		complainAndQuit("Found no test suites")
 	}
 
-	r.ComputeSuccinctMode(len(suites))
-
-	t := time.Now()
-
 	runners := []*testrunner.TestRunner{}
 	for _, suite := range suites {
-		runners = append(runners, testrunner.New(suite, r.commandFlags.NumCPU, r.commandFlags.ParallelStream, r.commandFlags.Timeout, r.commandFlags.GoOpts, additionalArgs))
+		runner := testrunner.New(suite, r.commandFlags.NumCPU, r.commandFlags.ParallelStream, r.commandFlags.Timeout, r.commandFlags.GoOpts, additionalArgs)
+		runner.SetOutput(r.out, os.Stdout)
+		runners = append(runners, runner)
 	}
 
+	if r.commandFlags.RerunFailed {
+		runners = r.runnersOfFailedSuites(runners)
+		if len(runners) == 0 {
+			fmt.Fprintln(r.out, "No suite failed its last run.  Exiting...")
+			os.Exit(0)
+		}
+	}
+
+	r.ComputeSuccinctMode(len(runners))
+
+	t := time.Now()
+
 	numSuites := 0
 	runResult := testrunner.PassingRunResult()
 	if r.commandFlags.UntilItFails {
@@ -110,7 +133,7 @@ func (r *SpecRunner) RunSpecs(args []string, additionalArgs []string) {
 		for {
 			r.UpdateSeed()
 			randomizedRunners := r.randomizeOrder(runners)
-			runResult, numSuites = r.suiteRunner.RunSuites(randomizedRunners, r.commandFlags.NumCompilers, r.commandFlags.KeepGoing, nil)
+			runResult, numSuites = suiteRunner.RunSuites(randomizedRunners, r.commandFlags.NumCompilers, r.commandFlags.KeepGoing, nil)
 			iteration++
 
 			if r.interruptHandler.WasInterrupted() {
@@ -118,15 +141,15 @@ func (r *SpecRunner) RunSpecs(args []string, additionalArgs []string) {
 			}
 
 			if runResult.Passed {
-				fmt.Printf("\nAll tests passed...\nWill keep running them until they fail.\nThis was attempt #%d\n%s\n", iteration, orcMessage(iteration))
+				fmt.Fprintf(r.out, "\nAll tests passed...\nWill keep running them until they fail.\nThis was attempt #%d\n%s\n", iteration, orcMessage(iteration))
 			} else {
-				fmt.Printf("\nTests failed on attempt #%d\n\n", iteration)
+				fmt.Fprintf(r.out, "\nTests failed on attempt #%d\n\n", iteration)
 				break
 			}
 		}
 	} else {
 		randomizedRunners := r.randomizeOrder(runners)
-		runResult, numSuites = r.suiteRunner.RunSuites(randomizedRunners, r.commandFlags.NumCompilers, r.commandFlags.KeepGoing, nil)
+		runResult, numSuites = suiteRunner.RunSuites(randomizedRunners, r.commandFlags.NumCompilers, r.commandFlags.KeepGoing, nil)
 	}
 
 	for _, runner := range runners {
@@ -138,7 +161,7 @@ func (r *SpecRunner) RunSpecs(args []string, additionalArgs []string) {
 			// If coverprofile is set, combine coverages
 			if r.getCoverprofile() != "" {
 				if err := r.combineCoverprofiles(runners); err != nil {
-					fmt.Println(err.Error())
+					fmt.Fprintln(r.out, err.Error())
 					os.Exit(1)
 				}
 			} else {
@@ -148,23 +171,34 @@ func (r *SpecRunner) RunSpecs(args []string, additionalArgs []string) {
 		}
 	}
 
-	fmt.Printf("\nGinkgo ran %d %s in %s\n", numSuites, pluralizedWord("suite", "suites", numSuites), time.Since(t))
+	fmt.Fprintf(r.out, "\nGinkgo ran %d %s in %s\n", numSuites, pluralizedWord("suite", "suites", numSuites), time.Since(t))
 
 	if runResult.Passed {
 		if runResult.HasProgrammaticFocus && strings.TrimSpace(os.Getenv("GINKGO_EDITOR_INTEGRATION")) == "" {
-			fmt.Printf("Test Suite Passed\n")
-			fmt.Printf("Detected Programmatic Focus - setting exit status to %d\n", types.GINKGO_FOCUS_EXIT_CODE)
+			fmt.Fprintf(r.out, "Test Suite Passed\n")
+			fmt.Fprintf(r.out, "Detected Programmatic Focus - setting exit status to %d\n", types.GINKGO_FOCUS_EXIT_CODE)
 			os.Exit(types.GINKGO_FOCUS_EXIT_CODE)
 		} else {
-			fmt.Printf("Test Suite Passed\n")
+			fmt.Fprintf(r.out, "Test Suite Passed\n")
 			os.Exit(0)
 		}
 	} else {
-		fmt.Printf("Test Suite Failed\n")
+		fmt.Fprintf(r.out, "Test Suite Failed\n")
 		os.Exit(1)
 	}
 }
 
+//runnersOfFailedSuites returns the runners of the suites that failed their last run, set up to run only the specs that failed
+func (r *SpecRunner) runnersOfFailedSuites(runners []*testrunner.TestRunner) []*testrunner.TestRunner {
+	failedRunners := []*testrunner.TestRunner{}
+	for _, runner := range runners {
+		if runner.RerunFailed() {
+			failedRunners = append(failedRunners, runner)
+		}
+	}
+	return failedRunners
+}
+
 // Moves all generated profiles to specified directory
 func (r *SpecRunner) moveCoverprofiles(runners []*testrunner.TestRunner) {
 	for _, runner := range runners {
@@ -172,7 +206,7 @@ func (r *SpecRunner) moveCoverprofiles(runners []*testrunner.TestRunner) {
 		err := os.Rename(runner.CoverageFile, filepath.Join(r.getOutputDir(), filename))
 
 		if err != nil {
-			fmt.Printf("Unable to move coverprofile %s, %v\n", runner.CoverageFile, err)
+			fmt.Fprintf(r.out, "Unable to move coverprofile %s, %v\n", runner.CoverageFile, err)
 			return
 		}
 	}
@@ -186,7 +220,7 @@ func (r *SpecRunner) combineCoverprofiles(runners []*testrunner.TestRunner) erro
 		return fmt.Errorf("Unable to create combined profile, outputdir does not exist: %s", r.getOutputDir())
 	}
 
-	fmt.Println("path is " + path)
+	fmt.Fprintln(r.out, "path is " + path)
 
 	combined, err := os.OpenFile(
 		filepath.Join(path, r.getCoverprofile()),
@@ -195,7 +229,7 @@ func (r *SpecRunner) combineCoverprofiles(runners []*testrunner.TestRunner) erro
 	)
 
 	if err != nil {
-		fmt.Printf("Unable to create combined profile, %v\n", err)
+		fmt.Fprintf(r.out, "Unable to create combined profile, %v\n", err)
 		return nil // non-fatal error
 	}
 
@@ -204,7 +238,7 @@ func (r *SpecRunner) combineCoverprofiles(runners []*testrunner.TestRunner) erro
 		contents, err := ioutil.ReadFile(runner.CoverageFile)
 
 		if err != nil {
-			fmt.Printf("Unable to read coverage file %s to combine, %v\n", runner.CoverageFile, err)
+			fmt.Fprintf(r.out, "Unable to read coverage file %s to combine, %v\n", runner.CoverageFile, err)
 			return nil // non-fatal error
 		}
 
@@ -222,12 +256,12 @@ func (r *SpecRunner) combineCoverprofiles(runners []*testrunner.TestRunner) erro
 		}
 
 		if err != nil {
-			fmt.Printf("Unable to append to coverprofile, %v\n", err)
+			fmt.Fprintf(r.out, "Unable to append to coverprofile, %v\n", err)
 			return nil // non-fatal error
 		}
 	}
 
-	fmt.Println("All profiles combined")
+	fmt.Fprintln(r.out, "All profiles combined")
 	return nil
 }
 
@@ -27,6 +27,7 @@ type RunWatchAndBuildCommandFlags struct {
 	KeepGoing       bool
 	UntilItFails    bool
 	RandomizeSuites bool
+	RerunFailed     bool
 
 	//only for watch command
 	Depth       int
@@ -160,6 +161,7 @@ func (c *RunWatchAndBuildCommandFlags) flags(mode int) {
 		c.FlagSet.BoolVar(&(c.KeepGoing), "keepGoing", false, "When true, failures from earlier test suites do not prevent later test suites from running")
 		c.FlagSet.BoolVar(&(c.UntilItFails), "untilItFails", false, "When true, Ginkgo will keep rerunning tests until a failure occurs")
 		c.FlagSet.BoolVar(&(c.RandomizeSuites), "randomizeSuites", false, "When true, Ginkgo will randomize the order in which test suites run")
+		c.FlagSet.BoolVar(&(c.RerunFailed), "rerunFailed", false, "When true, Ginkgo will only run the suites that failed their last run, and only the specs that failed in them.  Suites whose failed specs no longer exist run in full")
 	}
 
 	if mode == watchMode {
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"runtime"
 	"sync"
 
@@ -9,7 +11,9 @@ import (
 	"github.com/hackrish007/ginkgo/ginkgo/interrupthandler"
 	"github.com/hackrish007/ginkgo/ginkgo/testrunner"
 	"github.com/hackrish007/ginkgo/ginkgo/testsuite"
+	"github.com/hackrish007/ginkgo/reporters"
 	colorable "github.com/hackrish007/ginkgo/reporters/stenographer/support/go-colorable"
+	"github.com/hackrish007/ginkgo/types"
 )
 
 type compilationInput struct {
@@ -25,15 +29,26 @@ type compilationOutput struct {
 type SuiteRunner struct {
 	notifier         *Notifier
 	interruptHandler *interrupthandler.InterruptHandler
+	out              io.Writer
 }
 
-func NewSuiteRunner(notifier *Notifier, interruptHandler *interrupthandler.InterruptHandler) *SuiteRunner {
+//NewSuiteRunner returns a runner that prints what it has to say about the suites it runs to out
+func NewSuiteRunner(notifier *Notifier, interruptHandler *interrupthandler.InterruptHandler, out io.Writer) *SuiteRunner {
 	return &SuiteRunner{
 		notifier:         notifier,
 		interruptHandler: interruptHandler,
+		out:              out,
 	}
 }
 
+//colorableOutput lets colors printed to out show on Windows consoles
+func colorableOutput(out io.Writer) io.Writer {
+	if file, ok := out.(*os.File); ok {
+		return colorable.NewColorable(file)
+	}
+	return out
+}
+
 func (r *SuiteRunner) compileInParallel(runners []*testrunner.TestRunner, numCompilers int, willCompile func(suite testsuite.TestSuite)) chan compilationOutput {
 	//we return this to the consumer, it will return each runner in order as it compiles
 	compilationOutputs := make(chan compilationOutput, len(runners))
@@ -121,16 +136,27 @@ func (r *SuiteRunner) RunSuites(runners []*testrunner.TestRunner, numCompilers i
 	numSuitesThatRan := 0
 	suitesThatFailed := []testsuite.TestSuite{}
 	for compilationOutput := range compilationOutputs {
+		if config.DefaultReporterConfig.GitHubActions {
+			reporters.BeginGitHubActionsGroup(r.out, compilationOutput.runner.Suite.PackageName)
+		}
 		if compilationOutput.err != nil {
-			fmt.Print(compilationOutput.err.Error())
+			fmt.Fprint(r.out, compilationOutput.err.Error())
 		}
 		numSuitesThatRan++
-		suiteRunResult := testrunner.FailingRunResult()
+		var suiteRunResult testrunner.RunResult
 		if compilationOutput.err == nil {
 			suiteRunResult = compilationOutput.runner.Run()
+		} else {
+			suiteRunResult = compilationOutput.runner.CompilationFailed(compilationOutput.err)
+		}
+		if config.DefaultReporterConfig.GitHubActions {
+			reporters.EndGitHubActionsGroup(r.out)
+			for _, report := range suiteRunResult.Reports {
+				reporters.WriteGitHubActionsAnnotations(r.out, report)
+			}
 		}
 		r.notifier.SendSuiteCompletionNotification(compilationOutput.runner.Suite, suiteRunResult.Passed)
-		r.notifier.RunCommand(compilationOutput.runner.Suite, suiteRunResult.Passed)
+		r.notifier.RunCommand(compilationOutput.runner.Suite, suiteRunResult.Passed, r.out)
 		runResult = runResult.Merge(suiteRunResult)
 		if !suiteRunResult.Passed {
 			suitesThatFailed = append(suitesThatFailed, compilationOutput.runner.Suite)
@@ -139,7 +165,7 @@ func (r *SuiteRunner) RunSuites(runners []*testrunner.TestRunner, numCompilers i
 			}
 		}
 		if numSuitesThatRan < len(runners) && !config.DefaultReporterConfig.Succinct {
-			fmt.Println("")
+			fmt.Fprintln(r.out, "")
 		}
 	}
 
@@ -147,12 +173,49 @@ func (r *SuiteRunner) RunSuites(runners []*testrunner.TestRunner, numCompilers i
 		r.listFailedSuites(suitesThatFailed)
 	}
 
+	r.writeReports(runResult.Reports)
+
 	return runResult, numSuitesThatRan
 }
 
+//writeReports writes the JUnit, JSON and TAP reports of the run, with a test suite for each suite that ran or failed to compile
+func (r *SuiteRunner) writeReports(reports []types.SuiteReport) {
+	if reports == nil {
+		reports = []types.SuiteReport{}
+	}
+
+	if config.DefaultReporterConfig.ReportFile != "" {
+		suites := reporters.NewJUnitTestSuites(reports, config.DefaultReporterConfig)
+		path, err := reporters.WriteJUnitReport(config.DefaultReporterConfig.ReportFile, suites)
+		if err != nil {
+			fmt.Fprintf(r.out, "\nUnable to write JUnit report:\n\t%s\n", err.Error())
+		} else {
+			fmt.Fprintf(r.out, "\nJUnit report was created: %s\n", path)
+		}
+	}
+
+	if config.DefaultReporterConfig.JSONReport != "" {
+		path, err := reporters.WriteJSONReport(config.DefaultReporterConfig.JSONReport, reports)
+		if err != nil {
+			fmt.Fprintf(r.out, "\nUnable to write JSON report:\n\t%s\n", err.Error())
+		} else {
+			fmt.Fprintf(r.out, "\nJSON report was created: %s\n", path)
+		}
+	}
+
+	if config.DefaultReporterConfig.TAPReport != "" {
+		path, err := reporters.WriteTAPReport(config.DefaultReporterConfig.TAPReport, reports)
+		if err != nil {
+			fmt.Fprintf(r.out, "\nUnable to write TAP report:\n\t%s\n", err.Error())
+		} else {
+			fmt.Fprintf(r.out, "\nTAP report was created: %s\n", path)
+		}
+	}
+}
+
 func (r *SuiteRunner) listFailedSuites(suitesThatFailed []testsuite.TestSuite) {
-	fmt.Println("")
-	fmt.Println("There were failures detected in the following suites:")
+	fmt.Fprintln(r.out, "")
+	fmt.Fprintln(r.out, "There were failures detected in the following suites:")
 
 	maxPackageNameLength := 0
 	for _, suite := range suitesThatFailed {
@@ -165,9 +228,9 @@ func (r *SuiteRunner) listFailedSuites(suitesThatFailed []testsuite.TestSuite) {
 
 	for _, suite := range suitesThatFailed {
 		if config.DefaultReporterConfig.NoColor {
-			fmt.Printf("\t"+packageNameFormatter+" %s\n", suite.PackageName, suite.Path)
+			fmt.Fprintf(r.out, "\t"+packageNameFormatter+" %s\n", suite.PackageName, suite.Path)
 		} else {
-			fmt.Fprintf(colorable.NewColorableStdout(), "\t%s"+packageNameFormatter+"%s %s%s%s\n", redColor, suite.PackageName, defaultStyle, lightGrayColor, suite.Path, defaultStyle)
+			fmt.Fprintf(colorableOutput(r.out), "\t%s"+packageNameFormatter+"%s %s%s%s\n", redColor, suite.PackageName, defaultStyle, lightGrayColor, suite.Path, defaultStyle)
 		}
 	}
 }
@@ -0,0 +1,138 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/reporters"
+)
+
+//FailedSpecsPath returns the file in which the Ginkgo CLI records the specs that failed in the last run of the suite in suitePath.
+//Records are kept in the user's cache directory, rather than in the suite's source, and are named after the suite's absolute path.
+func FailedSpecsPath(suitePath string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(suitePath)
+	if err != nil {
+		return "", err
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(absPath))
+	return filepath.Join(cacheDir, "ginkgo", "failed-specs", fmt.Sprintf("%s-%016x.json", filepath.Base(absPath), hash.Sum64())), nil
+}
+
+/*
+FailedSpecs lists the specs that failed in a suite's last run.  The Ginkgo CLI records them after every run, and runs only them with -rerunFailed.
+
+The file exists only for suites whose last run failed.  A suite that failed without any of its specs failing, e.g. because it failed to compile, its BeforeSuite failed
+or its nodes crashed, is recorded without specs, as is a failed package that isn't a Ginkgo suite.
+*/
+type FailedSpecs []reporters.FailedSpec
+
+//LoadFailedSpecs reads previously recorded failed specs
+func LoadFailedSpecs(path string) (FailedSpecs, error) {
+	specs := FailedSpecs{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return specs, err
+	}
+	err = json.Unmarshal(data, &specs)
+	return specs, err
+}
+
+//Save records the specs in path, creating its directory if necessary
+func (specs FailedSpecs) Save(path string) error {
+	data, err := json.MarshalIndent(specs, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0666)
+}
+
+func (specs FailedSpecs) IDs() []string {
+	ids := []string{}
+	for _, spec := range specs {
+		ids = append(ids, spec.ID)
+	}
+	return ids
+}
+
+//RerunFailed has the runner run only the specs that failed in the suite's last run.  It returns false if the suite's last run passed, or the suite never ran.
+//Suites that failed without any of their specs failing, and suites whose record can't be read, run in full.
+//The IDs are handed to the suite's nodes in a file, as there may be more of them than fit on a command line.
+func (t *TestRunner) RerunFailed() bool {
+	path, err := FailedSpecsPath(t.Suite.Path)
+	var specs FailedSpecs
+	if err == nil {
+		specs, err = LoadFailedSpecs(path)
+	}
+	if os.IsNotExist(err) {
+		return false
+	}
+	if err == nil && len(specs) > 0 {
+		t.focusIDFile, err = writeFocusIDFile(specs.IDs())
+	}
+	if err != nil {
+		fmt.Fprintf(t.out, "Unable to rerun only the specs that failed in %s's last run, running all of its specs:\n\t%s\n", t.Suite.PackageName, err.Error())
+	}
+	return true
+}
+
+//writeFocusIDFile writes ids, one per line, to a temporary file for -focusIDFile
+func writeFocusIDFile(ids []string) (string, error) {
+	file, err := ioutil.TempFile("", "ginkgo-failed-spec-ids-")
+	if err != nil {
+		return "", err
+	}
+	_, err = file.WriteString(strings.Join(ids, "\n") + "\n")
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+//ginkgoConfig returns the configuration passed to the suite's nodes
+func (t *TestRunner) ginkgoConfig() config.GinkgoConfigType {
+	ginkgoConfig := config.GinkgoConfig
+	if t.focusIDFile != "" {
+		ginkgoConfig.FocusIDFiles = append(append([]string{}, ginkgoConfig.FocusIDFiles...), t.focusIDFile)
+	}
+	return ginkgoConfig
+}
+
+//recordFailedSpecs records the specs that failed in the suite's run for -rerunFailed, or removes the record of an earlier run if the suite passed
+func (t *TestRunner) recordFailedSpecs(res RunResult) {
+	path, err := FailedSpecsPath(t.Suite.Path)
+	if err != nil {
+		fmt.Fprintf(t.out, "Unable to record the specs that failed:\n\t%s\n", err.Error())
+		return
+	}
+	if res.Passed {
+		os.Remove(path)
+		return
+	}
+	specs := res.FailedSpecs
+	if specs == nil {
+		specs = FailedSpecs{}
+	}
+	err = specs.Save(path)
+	if err != nil {
+		fmt.Fprintf(t.out, "Unable to record the specs that failed:\n\t%s\n", err.Error())
+	}
+}
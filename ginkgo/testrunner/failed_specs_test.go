@@ -0,0 +1,84 @@
+package testrunner_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/ginkgo/testrunner"
+	"github.com/hackrish007/ginkgo/ginkgo/testsuite"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("FailedSpecs", func() {
+	Describe("rerunning them", func() {
+		var dir, suiteDir, cacheDir string
+		var originalEnv map[string]string
+		var runner *testrunner.TestRunner
+		var path string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "ginkgo-failed-specs")
+			Ω(err).ShouldNot(HaveOccurred())
+			suiteDir = filepath.Join(dir, "widget")
+			cacheDir = filepath.Join(dir, "cache")
+
+			//point the user's cache directory into dir, wherever the platform looks for it
+			originalEnv = map[string]string{}
+			for _, key := range []string{"XDG_CACHE_HOME", "HOME", "LocalAppData"} {
+				originalEnv[key] = os.Getenv(key)
+			}
+			os.Setenv("XDG_CACHE_HOME", cacheDir)
+			os.Setenv("HOME", cacheDir)
+			os.Setenv("LocalAppData", cacheDir)
+
+			runner = testrunner.New(testsuite.TestSuite{Path: suiteDir, PackageName: "widget"}, 1, false, 0, map[string]interface{}{}, []string{})
+			path, err = testrunner.FailedSpecsPath(suiteDir)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			runner.CleanUp()
+			for key, value := range originalEnv {
+				os.Setenv(key, value)
+			}
+			os.RemoveAll(dir)
+		})
+
+		It("keeps the record in the user's cache directory, out of the suite's source", func() {
+			Ω(path).Should(HavePrefix(cacheDir))
+			otherPath, err := testrunner.FailedSpecsPath(filepath.Join(dir, "other", "widget"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(otherPath).ShouldNot(Equal(path))
+		})
+
+		It("reruns suites that recorded failed specs", func() {
+			specs := testrunner.FailedSpecs{{ID: "a", Text: "Widget fails"}}
+			Ω(specs.Save(path)).Should(Succeed())
+
+			loaded, err := testrunner.LoadFailedSpecs(path)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(loaded).Should(Equal(specs))
+			Ω(loaded.IDs()).Should(Equal([]string{"a"}))
+			Ω(runner.RerunFailed()).Should(BeTrue())
+		})
+
+		It("doesn't rerun suites that passed their last run", func() {
+			Ω(runner.RerunFailed()).Should(BeFalse())
+		})
+
+		It("reruns suites that failed to compile in full", func() {
+			runner.CompilationFailed(errors.New("undefined: x"))
+
+			loaded, err := testrunner.LoadFailedSpecs(path)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(loaded).Should(BeEmpty())
+			_, err = os.Stat(suiteDir)
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+			Ω(runner.RerunFailed()).Should(BeTrue())
+		})
+	})
+})
@@ -1,8 +1,16 @@
 package testrunner
 
+import "github.com/hackrish007/ginkgo/types"
+
 type RunResult struct {
 	Passed               bool
 	HasProgrammaticFocus bool
+
+	//Reports holds a report of each Ginkgo suite that ran or failed to compile, if the CLI writes reports
+	Reports []types.SuiteReport
+
+	//FailedSpecs lists the specs that failed in the Ginkgo suites that ran
+	FailedSpecs FailedSpecs
 }
 
 func PassingRunResult() RunResult {
@@ -23,5 +31,7 @@ func (r RunResult) Merge(o RunResult) RunResult {
 	return RunResult{
 		Passed:               r.Passed && o.Passed,
 		HasProgrammaticFocus: r.HasProgrammaticFocus || o.HasProgrammaticFocus,
+		Reports:              append(append([]types.SuiteReport{}, r.Reports...), o.Reports...),
+		FailedSpecs:          append(append(FailedSpecs{}, r.FailedSpecs...), o.FailedSpecs...),
 	}
 }
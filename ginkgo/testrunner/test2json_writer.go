@@ -0,0 +1,60 @@
+package testrunner
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+//test2JSONWriter passes the test2json events a serial node prints on to events, and everything else the node prints, e.g. the testing package's closing PASS, on to other
+type test2JSONWriter struct {
+	buffer *bytes.Buffer
+	lock   *sync.Mutex
+	events io.Writer
+	other  io.Writer
+}
+
+func newTest2JSONWriter(events io.Writer, other io.Writer) *test2JSONWriter {
+	return &test2JSONWriter{
+		buffer: &bytes.Buffer{},
+		lock:   &sync.Mutex{},
+		events: events,
+		other:  other,
+	}
+}
+
+func (w *test2JSONWriter) Write(data []byte) (n int, err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buffer.Write(data)
+	for {
+		i := bytes.IndexByte(w.buffer.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buffer.Next(i + 1))
+	}
+	return len(data), nil
+}
+
+func (w *test2JSONWriter) writeLine(line []byte) {
+	if bytes.HasPrefix(line, []byte("{")) && json.Valid(line) {
+		w.events.Write(line)
+	} else {
+		w.other.Write(line)
+	}
+}
+
+func (w *test2JSONWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.buffer.Len() > 0 {
+		w.other.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+	}
+
+	return nil
+}
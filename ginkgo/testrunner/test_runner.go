@@ -2,6 +2,7 @@ package testrunner
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -16,6 +17,7 @@ import (
 	"github.com/hackrish007/ginkgo/config"
 	"github.com/hackrish007/ginkgo/ginkgo/testsuite"
 	"github.com/hackrish007/ginkgo/internal/remote"
+	"github.com/hackrish007/ginkgo/reporters"
 	"github.com/hackrish007/ginkgo/reporters/stenographer"
 	colorable "github.com/hackrish007/ginkgo/reporters/stenographer/support/go-colorable"
 	"github.com/hackrish007/ginkgo/types"
@@ -33,6 +35,11 @@ type TestRunner struct {
 	goOpts         map[string]interface{}
 	additionalArgs []string
 	stderr         *bytes.Buffer
+	focusIDFile    string
+
+	//out is where the runner and the suite print, and events is where the test2json events of -test2json runs go
+	out    io.Writer
+	events io.Writer
 
 	CoverageFile string
 }
@@ -46,6 +53,8 @@ func New(suite testsuite.TestSuite, numCPU int, parallelStream bool, timeout tim
 		additionalArgs: additionalArgs,
 		timeout:        timeout,
 		stderr:         new(bytes.Buffer),
+		out:            os.Stdout,
+		events:         os.Stdout,
 	}
 
 	if !suite.Precompiled {
@@ -55,6 +64,19 @@ func New(suite testsuite.TestSuite, numCPU int, parallelStream bool, timeout tim
 	return runner
 }
 
+//SetOutput points what the runner and the suite print at out, and the test2json events of -test2json runs at events
+func (t *TestRunner) SetOutput(out io.Writer, events io.Writer) {
+	t.out = out
+	t.events = events
+}
+
+//syncOut flushes what has been printed to out, should out be a file
+func (t *TestRunner) syncOut() {
+	if syncer, ok := t.out.(interface{ Sync() error }); ok {
+		syncer.Sync()
+	}
+}
+
 func (t *TestRunner) Compile() error {
 	return t.CompileTo(t.compilationTargetPath)
 }
@@ -149,7 +171,7 @@ func (t *TestRunner) CompileTo(path string) error {
 	}
 
 	if len(output) > 0 {
-		fmt.Println(string(output))
+		fmt.Fprintln(t.out, string(output))
 	}
 
 	if !fileExists(path) {
@@ -225,22 +247,26 @@ func copyFile(src, dst string) error {
 }
 
 func (t *TestRunner) Run() RunResult {
-	if t.Suite.IsGinkgo {
-		if t.numCPU > 1 {
-			if t.parallelStream {
-				return t.runAndStreamParallelGinkgoSuite()
-			} else {
-				return t.runParallelGinkgoSuite()
-			}
+	var res RunResult
+	if !t.Suite.IsGinkgo {
+		res = t.runGoTestSuite()
+	} else if t.numCPU > 1 {
+		if t.parallelStream {
+			res = t.runAndStreamParallelGinkgoSuite()
 		} else {
-			return t.runSerialGinkgoSuite()
+			res = t.runParallelGinkgoSuite()
 		}
 	} else {
-		return t.runGoTestSuite()
+		res = t.runSerialGinkgoSuite()
 	}
+	t.recordFailedSpecs(res)
+	return res
 }
 
 func (t *TestRunner) CleanUp() {
+	if t.focusIDFile != "" {
+		os.Remove(t.focusIDFile)
+	}
 	if t.Suite.Precompiled {
 		return
 	}
@@ -248,23 +274,42 @@ func (t *TestRunner) CleanUp() {
 }
 
 func (t *TestRunner) runSerialGinkgoSuite() RunResult {
-	ginkgoArgs := config.BuildFlagArgs("ginkgo", config.GinkgoConfig, config.DefaultReporterConfig)
-	return t.run(t.cmd(ginkgoArgs, os.Stdout, 1), nil)
+	reportDir := t.makeNodeReportDir()
+	defer os.RemoveAll(reportDir)
+
+	ginkgoArgs := config.BuildFlagArgs("ginkgo", t.ginkgoConfig(), nodeReporterConfig(reportDir))
+	if !config.DefaultReporterConfig.Test2JSON {
+		res := t.run(t.cmd(ginkgoArgs, t.out, 1), nil)
+		return t.withNodeReports(res, reportDir)
+	}
+
+	writer := newTest2JSONWriter(t.events, t.out)
+	res := t.run(t.cmd(ginkgoArgs, writer, 1), nil)
+	writer.Close()
+	return t.withNodeReports(res, reportDir)
 }
 
 func (t *TestRunner) runGoTestSuite() RunResult {
-	return t.run(t.cmd([]string{"-test.v"}, os.Stdout, 1), nil)
+	return t.run(t.cmd([]string{"-test.v"}, t.out, 1), nil)
 }
 
 func (t *TestRunner) runAndStreamParallelGinkgoSuite() RunResult {
 	completions := make(chan RunResult)
 	writers := make([]*logWriter, t.numCPU)
 
-	server, err := remote.NewServer(t.numCPU)
+	reportDir := t.makeNodeReportDir()
+	defer os.RemoveAll(reportDir)
+
+	server, err := remote.NewUnixSocketServer(t.numCPU)
 	if err != nil {
 		panic("Failed to start parallel spec server")
 	}
 
+	//streaming nodes don't report their specs to the server, so their durations can't be recorded, only used
+	if config.GinkgoConfig.ScheduleByDuration {
+		server.ScheduleByDuration(t.loadSpecDurations())
+	}
+
 	server.Start()
 	defer server.Close()
 
@@ -273,20 +318,11 @@ func (t *TestRunner) runAndStreamParallelGinkgoSuite() RunResult {
 		config.GinkgoConfig.ParallelTotal = t.numCPU
 		config.GinkgoConfig.SyncHost = server.Address()
 
-		ginkgoArgs := config.BuildFlagArgs("ginkgo", config.GinkgoConfig, config.DefaultReporterConfig)
+		ginkgoArgs := config.BuildFlagArgs("ginkgo", t.ginkgoConfig(), nodeReporterConfig(reportDir))
 
-		writers[cpu] = newLogWriter(os.Stdout, cpu+1)
+		writers[cpu] = newLogWriter(t.out, cpu+1)
 
-		cmd := t.cmd(ginkgoArgs, writers[cpu], cpu+1)
-
-		server.RegisterAlive(cpu+1, func() bool {
-			if cmd.ProcessState == nil {
-				return true
-			}
-			return !cmd.ProcessState.Exited()
-		})
-
-		go t.run(cmd, completions)
+		go t.runParallelNode(server, cpu+1, ginkgoArgs, writers[cpu], completions)
 	}
 
 	res := PassingRunResult()
@@ -299,13 +335,13 @@ func (t *TestRunner) runAndStreamParallelGinkgoSuite() RunResult {
 		writer.Close()
 	}
 
-	os.Stdout.Sync()
+	t.syncOut()
 
 	if t.shouldCombineCoverprofiles() {
 		t.combineCoverprofiles()
 	}
 
-	return res
+	return t.withNodeReports(res, reportDir)
 }
 
 func (t *TestRunner) runParallelGinkgoSuite() RunResult {
@@ -314,14 +350,50 @@ func (t *TestRunner) runParallelGinkgoSuite() RunResult {
 	writers := make([]*logWriter, t.numCPU)
 	reports := make([]*bytes.Buffer, t.numCPU)
 
-	stenographer := stenographer.New(!config.DefaultReporterConfig.NoColor, config.GinkgoConfig.FlakeAttempts > 1, colorable.NewColorableStdout())
+	out := t.out
+	if file, ok := out.(*os.File); ok {
+		out = colorable.NewColorable(file)
+	}
+	stenographer := stenographer.New(!config.DefaultReporterConfig.NoColor, config.GinkgoConfig.FlakeAttempts > 1, out)
 	aggregator := remote.NewAggregator(t.numCPU, result, config.DefaultReporterConfig, stenographer)
 
-	server, err := remote.NewServer(t.numCPU)
+	server, err := remote.NewUnixSocketServer(t.numCPU)
 	if err != nil {
 		panic("Failed to start parallel spec server")
 	}
-	server.RegisterReporters(aggregator)
+	serverReporters := []reporters.Reporter{aggregator}
+	var durationsRecorder *remote.SpecDurationsRecorder
+	if config.GinkgoConfig.ScheduleByDuration {
+		durations := t.loadSpecDurations()
+		durationsRecorder = remote.NewSpecDurationsRecorder(durations, t.numCPU)
+		server.ScheduleByDuration(durations)
+		serverReporters = append(serverReporters, durationsRecorder)
+	}
+	var timelineRecorder *remote.TimelineRecorder
+	if config.GinkgoConfig.TimelineFile != "" {
+		timelineRecorder = remote.NewTimelineRecorder()
+		serverReporters = append(serverReporters, timelineRecorder)
+	}
+	var reportRecorder *reporters.SuiteReportRecorder
+	if recordsReports() {
+		reportRecorder = t.newReportRecorder()
+		serverReporters = append(serverReporters, reportRecorder)
+	}
+	failedSpecsReporter := reporters.NewFailedSpecsReporter("")
+	serverReporters = append(serverReporters, failedSpecsReporter)
+	if config.DefaultReporterConfig.TeamCity {
+		teamCityReporter := reporters.NewParallelTeamCityReporter(t.out, t.numCPU)
+		teamCityReporter.ReporterConfig = config.DefaultReporterConfig
+		serverReporters = append(serverReporters, teamCityReporter)
+	}
+	if config.DefaultReporterConfig.Test2JSON {
+		test2JSONReporter := reporters.NewParallelTest2JSONReporter(t.events, t.numCPU)
+		suitePath, _ := filepath.Abs(t.Suite.Path)
+		test2JSONReporter.SetSuitePath(suitePath)
+		serverReporters = append(serverReporters, test2JSONReporter)
+	}
+	server.RegisterReporters(serverReporters...)
+	server.SetOutputMaxBytes(config.GinkgoConfig.OutputMaxBytes)
 	server.Start()
 	defer server.Close()
 
@@ -331,21 +403,12 @@ func (t *TestRunner) runParallelGinkgoSuite() RunResult {
 		config.GinkgoConfig.SyncHost = server.Address()
 		config.GinkgoConfig.StreamHost = server.Address()
 
-		ginkgoArgs := config.BuildFlagArgs("ginkgo", config.GinkgoConfig, config.DefaultReporterConfig)
+		ginkgoArgs := config.BuildFlagArgs("ginkgo", t.ginkgoConfig(), nodeReporterConfig(""))
 
 		reports[cpu] = &bytes.Buffer{}
 		writers[cpu] = newLogWriter(reports[cpu], cpu+1)
 
-		cmd := t.cmd(ginkgoArgs, writers[cpu], cpu+1)
-
-		server.RegisterAlive(cpu+1, func() bool {
-			if cmd.ProcessState == nil {
-				return true
-			}
-			return !cmd.ProcessState.Exited()
-		})
-
-		go t.run(cmd, completions)
+		go t.runParallelNode(server, cpu+1, ginkgoArgs, writers[cpu], completions)
 	}
 
 	res := PassingRunResult()
@@ -359,33 +422,209 @@ func (t *TestRunner) runParallelGinkgoSuite() RunResult {
 
 	select {
 	case <-result:
-		fmt.Println("")
+		fmt.Fprintln(t.out, "")
+		if durationsRecorder != nil {
+			t.saveSpecDurations(durationsRecorder)
+		}
+		if timelineRecorder != nil {
+			t.saveTimeline(timelineRecorder)
+		}
 	case <-time.After(time.Second):
 		//the aggregator never got back to us!  something must have gone wrong
-		fmt.Println(`
+		fmt.Fprintln(t.out, `
 	 -------------------------------------------------------------------
 	|                                                                   |
 	|  Ginkgo timed out waiting for all parallel nodes to report back!  |
 	|                                                                   |
 	 -------------------------------------------------------------------`)
-		fmt.Println("\n", t.Suite.PackageName, "timed out. path:", t.Suite.Path)
-		os.Stdout.Sync()
+		fmt.Fprintln(t.out, "\n", t.Suite.PackageName, "timed out. path:", t.Suite.Path)
+		t.syncOut()
 
 		for _, writer := range writers {
 			writer.Close()
 		}
 
 		for _, report := range reports {
-			fmt.Print(report.String())
+			fmt.Fprint(t.out, report.String())
 		}
 
-		os.Stdout.Sync()
+		t.syncOut()
 	}
 
 	if t.shouldCombineCoverprofiles() {
 		t.combineCoverprofiles()
 	}
 
+	if reportRecorder != nil {
+		res.Reports = append(res.Reports, t.suiteReport(reportRecorder, res))
+	}
+	res.FailedSpecs = failedSpecsReporter.FailedSpecs()
+
+	return res
+}
+
+//runParallelNode runs a parallel node.  Should the node crash, the server reports the crash and, with -restartCrashedNodes, a new node takes over the remaining specs.
+func (t *TestRunner) runParallelNode(server *remote.Server, node int, ginkgoArgs []string, stream io.Writer, completions chan RunResult) {
+	res := PassingRunResult()
+
+	for {
+		cmd := t.cmd(ginkgoArgs, stream, node)
+
+		server.RegisterAlive(node, func() bool {
+			if cmd.ProcessState == nil {
+				return true
+			}
+			return !cmd.ProcessState.Exited()
+		})
+
+		res = res.Merge(t.run(cmd, nil))
+
+		crashed, description, restart := server.NodeExited(node, config.GinkgoConfig.RestartCrashedNodes)
+		if !crashed {
+			break
+		}
+
+		if restart {
+			fmt.Fprintf(t.out, "\n%s.  Starting a new node to run the remaining specs.\n", description)
+		} else {
+			fmt.Fprintf(t.out, "\n%s.\n", description)
+			break
+		}
+	}
+
+	completions <- res
+}
+
+func (t *TestRunner) loadSpecDurations() remote.SpecDurations {
+	durations, err := remote.LoadSpecDurations(filepath.Join(t.Suite.Path, remote.SpecDurationsFile))
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(t.out, "Unable to read spec durations, scheduling specs as if they were all equally long:\n\t%s\n", err.Error())
+	}
+	return durations
+}
+
+func (t *TestRunner) saveSpecDurations(recorder *remote.SpecDurationsRecorder) {
+	err := recorder.Durations().Save(filepath.Join(t.Suite.Path, remote.SpecDurationsFile))
+	if err != nil {
+		fmt.Fprintf(t.out, "Unable to save spec durations:\n\t%s\n", err.Error())
+	}
+
+	wallTime, utilization := recorder.Utilization()
+	fmt.Fprintf(t.out, "Parallel node utilization over %s:\n", wallTime)
+	for i, u := range utilization {
+		fmt.Fprintf(t.out, "  Node %d: %5.1f%%\n", i+1, u*100)
+	}
+}
+
+func (t *TestRunner) saveTimeline(recorder *remote.TimelineRecorder) {
+	path := config.GinkgoConfig.TimelineFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.Suite.Path, path)
+	}
+	err := recorder.Save(path)
+	if err != nil {
+		fmt.Fprintf(t.out, "Unable to save timeline:\n\t%s\n", err.Error())
+	}
+}
+
+//recordsReports returns true if the CLI writes JUnit, JSON or TAP reports of the run, or annotates it for GitHub Actions.  It then collects a report of each suite from the suite's nodes.
+func recordsReports() bool {
+	reporterConfig := config.DefaultReporterConfig
+	return reporterConfig.ReportFile != "" || reporterConfig.JSONReport != "" || reporterConfig.TAPReport != "" || reporterConfig.GitHubActions
+}
+
+//nodeReporterConfig returns the reporter configuration passed to a suite's nodes.  The CLI writes the reports of the run, and GitHub Actions workflow commands, itself,
+//so nodes only write the specs that failed, and JSON reports of their runs if the CLI needs them, to reportDir, if set, for the CLI to read.
+func nodeReporterConfig(reportDir string) config.DefaultReporterConfigType {
+	reporterConfig := config.DefaultReporterConfig
+	reporterConfig.ReportFile = ""
+	reporterConfig.JSONReport = ""
+	reporterConfig.TAPReport = ""
+	reporterConfig.FailedSpecsFile = ""
+	reporterConfig.GitHubActions = false
+	if reportDir != "" {
+		reporterConfig.FailedSpecsFile = filepath.Join(reportDir, "failed-specs.json")
+		if recordsReports() {
+			reporterConfig.JSONReport = filepath.Join(reportDir, "report.json")
+		}
+	}
+	return reporterConfig
+}
+
+//makeNodeReportDir creates a directory for nodes that don't report to the CLI's server to write the specs that failed, and their reports, to
+func (t *TestRunner) makeNodeReportDir() string {
+	dir, err := ioutil.TempDir("", "ginkgo-reports")
+	if err != nil {
+		fmt.Fprintf(t.out, "Unable to create a directory for the suite's reports:\n\t%s\n", err.Error())
+		return ""
+	}
+	return dir
+}
+
+func (t *TestRunner) newReportRecorder() *reporters.SuiteReportRecorder {
+	recorder := reporters.NewSuiteReportRecorder()
+	suitePath, _ := filepath.Abs(t.Suite.Path)
+	recorder.SetSuitePath(suitePath)
+	return recorder
+}
+
+//withNodeReports adds the specs that failed and, if the CLI writes reports, a report of the suite, combining what the nodes wrote to reportDir, to res
+func (t *TestRunner) withNodeReports(res RunResult, reportDir string) RunResult {
+	if reportDir == "" {
+		return res
+	}
+	files, _ := filepath.Glob(filepath.Join(reportDir, "failed-specs*.json"))
+	for _, file := range files {
+		specs, err := LoadFailedSpecs(file)
+		if err == nil {
+			res.FailedSpecs = append(res.FailedSpecs, specs...)
+		}
+	}
+
+	if !recordsReports() {
+		return res
+	}
+	recorder := t.newReportRecorder()
+	files, _ = filepath.Glob(filepath.Join(reportDir, "report*.json"))
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var report types.SuiteReport
+		if json.Unmarshal(data, &report) == nil {
+			recorder.AddReport(report)
+		}
+	}
+	res.Reports = append(res.Reports, t.suiteReport(recorder, res))
+	return res
+}
+
+//suiteReport returns the report of the suite.  Should no node have reported on the suite, e.g. because they all crashed, the report only records that the suite failed.
+func (t *TestRunner) suiteReport(recorder *reporters.SuiteReportRecorder, res RunResult) types.SuiteReport {
+	report := recorder.Report()
+	if report.Version == 0 {
+		report.Version = types.JSONReportVersion
+		report.SuiteDescription = t.Suite.PackageName
+	}
+	report.SuiteSucceeded = report.SuiteSucceeded && res.Passed
+	return report
+}
+
+//CompilationFailed returns the result of a suite that failed to compile, and records the failure for -rerunFailed.  If the CLI writes reports, the suite is reported with the compiler's output.
+func (t *TestRunner) CompilationFailed(err error) RunResult {
+	res := FailingRunResult()
+	if recordsReports() {
+		suitePath, _ := filepath.Abs(t.Suite.Path)
+		res.Reports = []types.SuiteReport{{
+			Version:            types.JSONReportVersion,
+			SuiteDescription:   t.Suite.PackageName,
+			SuitePath:          suitePath,
+			CompilationFailure: err.Error(),
+			Specs:              []types.SpecReport{},
+		}}
+	}
+	t.recordFailedSpecs(res)
 	return res
 }
 
@@ -429,7 +668,11 @@ func (t *TestRunner) cmd(ginkgoArgs []string, stream io.Writer, node int) *exec.
 	cmd := exec.Command(path, args...)
 
 	cmd.Dir = t.Suite.Path
-	cmd.Stderr = io.MultiWriter(stream, t.stderr)
+	errStream := stream
+	if _, ok := stream.(*test2JSONWriter); ok {
+		errStream = t.out
+	}
+	cmd.Stderr = io.MultiWriter(errStream, t.stderr)
 	cmd.Stdout = stream
 
 	return cmd
@@ -470,7 +713,7 @@ func (t *TestRunner) run(cmd *exec.Cmd, completions chan RunResult) RunResult {
 
 	err := cmd.Start()
 	if err != nil {
-		fmt.Printf("Failed to run test suite!\n\t%s", err.Error())
+		fmt.Fprintf(t.out, "Failed to run test suite!\n\t%s", err.Error())
 		return res
 	}
 
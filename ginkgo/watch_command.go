@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 	"regexp"
 	"time"
 
@@ -22,7 +23,7 @@ func BuildWatchCommand() *Command {
 		commandFlags:     commandFlags,
 		notifier:         notifier,
 		interruptHandler: interruptHandler,
-		suiteRunner:      NewSuiteRunner(notifier, interruptHandler),
+		suiteRunner:      NewSuiteRunner(notifier, interruptHandler, os.Stdout),
 	}
 
 	return &Command{
@@ -51,6 +52,7 @@ type SpecWatcher struct {
 func (w *SpecWatcher) WatchSpecs(args []string, additionalArgs []string) {
 	w.commandFlags.computeNodes()
 	w.notifier.VerifyNotificationsAreAvailable()
+	prepareQuarantine(os.Stdout)
 
 	w.WatchSuites(args, additionalArgs)
 }
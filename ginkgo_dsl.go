@@ -15,9 +15,11 @@ import (
 	"flag"
 	"fmt"
 	"io"
-	"net/http"
+	"log"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
@@ -26,6 +28,7 @@ import (
 	"github.com/hackrish007/ginkgo/internal/global"
 	"github.com/hackrish007/ginkgo/internal/remote"
 	"github.com/hackrish007/ginkgo/internal/testingtproxy"
+	"github.com/hackrish007/ginkgo/internal/transport"
 	"github.com/hackrish007/ginkgo/internal/writer"
 	"github.com/hackrish007/ginkgo/reporters"
 	"github.com/hackrish007/ginkgo/reporters/stenographer"
@@ -58,8 +61,33 @@ func init() {
 //When running in verbose mode any writes to GinkgoWriter will be immediately printed
 //to stdout.  Otherwise, GinkgoWriter will buffer any writes produced during the current test and flush them to screen
 //only if the current test fails.
+//
+//GinkgoWriter can also timestamp each line (see the -ginkgo.writerTimestamps flag), copy everything written to it to other writers
+//(e.g. a log file) with TeeTo, and print with Print, Printf and Println.  These are reached with a type assertion:
+//
+//	GinkgoWriter.(GinkgoWriterInterface).TeeTo(logFile)
+//
+//Use GinkgoLogger to route a library's logging into it.
 var GinkgoWriter io.Writer
 
+//GinkgoWriterInterface is the interface implemented by Ginkgo's GinkgoWriter
+type GinkgoWriterInterface interface {
+	io.Writer
+
+	Print(a ...interface{})
+	Printf(format string, a ...interface{})
+	Println(a ...interface{})
+
+	TeeTo(writer io.Writer)
+	ClearTeeWriters()
+}
+
+//GinkgoLogger returns a standard library logger that writes to GinkgoWriter, and so into the output of the running spec.
+//prefix and flag are as for log.New.
+func GinkgoLogger(prefix string, flag int) *log.Logger {
+	return log.New(GinkgoWriter, prefix, flag)
+}
+
 //The interface by which Ginkgo receives *testing.T
 type GinkgoTestingT interface {
 	Fail()
@@ -139,6 +167,7 @@ type Reporter reporters.Reporter
 type Done chan<- interface{}
 
 //GinkgoTestDescription represents the information about the current running test returned by CurrentGinkgoTestDescription
+//	ID: a stable identifier for the test, unique within the suite
 //	FullTestText: a concatenation of ComponentTexts and the TestText
 //	ComponentTexts: a list of all texts for the Describes & Contexts leading up to the current test
 //	TestText: the text in the actual It or Measure node
@@ -147,6 +176,7 @@ type Done chan<- interface{}
 //	LineNumber: the line number for the current test
 //	Failed: if the current test has failed, this will be true (useful in an AfterEach)
 type GinkgoTestDescription struct {
+	ID             string
 	FullTestText   string
 	ComponentTexts []string
 	TestText       string
@@ -170,6 +200,7 @@ func CurrentGinkgoTestDescription() GinkgoTestDescription {
 	subjectCodeLocation := summary.ComponentCodeLocations[len(summary.ComponentCodeLocations)-1]
 
 	return GinkgoTestDescription{
+		ID:             summary.ID,
 		ComponentTexts: summary.ComponentTexts[1:],
 		FullTestText:   strings.Join(summary.ComponentTexts[1:], " "),
 		TestText:       summary.ComponentTexts[len(summary.ComponentTexts)-1],
@@ -210,9 +241,39 @@ func RunSpecs(t GinkgoTestingT, description string) bool {
 		specReporters[0] = reporters.NewJUnitReporter(reportFile)
 		specReporters = append(specReporters, buildDefaultReporter())
 	}
+	if config.DefaultReporterConfig.JSONReport != "" {
+		specReporters = append(specReporters, reporters.NewJSONReporter(nodeReportFile(config.DefaultReporterConfig.JSONReport)))
+	}
+	if config.DefaultReporterConfig.TAPReport != "" {
+		specReporters = append(specReporters, reporters.NewTAPReporter(nodeReportFile(config.DefaultReporterConfig.TAPReport)))
+	}
+	if config.DefaultReporterConfig.FailedSpecsFile != "" {
+		specReporters = append(specReporters, reporters.NewFailedSpecsReporter(nodeReportFile(config.DefaultReporterConfig.FailedSpecsFile)))
+	}
+	//parallel nodes that report to the CLI leave reporting to TeamCity to the CLI
+	if config.DefaultReporterConfig.TeamCity && config.GinkgoConfig.StreamHost == "" {
+		teamCityReporter := reporters.NewTeamCityReporter(os.Stdout)
+		teamCityReporter.ReporterConfig = config.DefaultReporterConfig
+		specReporters = append(specReporters, teamCityReporter)
+	}
+	if config.DefaultReporterConfig.GitHubActions {
+		specReporters = append(specReporters, reporters.NewGitHubActionsReporter(os.Stdout))
+	}
+	if config.DefaultReporterConfig.Test2JSON && config.GinkgoConfig.StreamHost == "" {
+		specReporters = append(specReporters, reporters.NewTest2JSONReporter(os.Stdout))
+	}
 	return runSpecsWithCustomReporters(t, description, specReporters)
 }
 
+//nodeReportFile returns the file a JSON or TAP report, or the list of failed specs, is written to.  Parallel nodes each write their own report, named after the node.
+func nodeReportFile(reportFile string) string {
+	if config.GinkgoConfig.ParallelTotal <= 1 {
+		return reportFile
+	}
+	extension := filepath.Ext(reportFile)
+	return fmt.Sprintf("%s.node-%d%s", strings.TrimSuffix(reportFile, extension), config.GinkgoConfig.ParallelNode, extension)
+}
+
 //To run your tests with Ginkgo's default reporter and your custom reporter(s), replace
 //RunSpecs() with this method.
 func RunSpecsWithDefaultAndCustomReporters(t GinkgoTestingT, description string, specReporters []Reporter) bool {
@@ -231,6 +292,18 @@ func RunSpecsWithCustomReporters(t GinkgoTestingT, description string, specRepor
 func runSpecsWithCustomReporters(t GinkgoTestingT, description string, specReporters []Reporter) bool {
 	writer := GinkgoWriter.(*writer.Writer)
 	writer.SetStream(config.DefaultReporterConfig.Verbose)
+	writer.SetRetentionPolicy(outputRetentionPolicy())
+	writer.SetTimestamps(ginkgoWriterTimestamps())
+	//parallel nodes that report to the CLI already capture their output.  test2json events must be all that specs print to stdout.
+	if (config.GinkgoConfig.CaptureOutput || config.DefaultReporterConfig.Test2JSON) && config.GinkgoConfig.StreamHost == "" {
+		if remote.OutputInterceptionSupported {
+			//GinkgoWriter's output would otherwise be captured twice
+			writer.SetStream(false)
+			global.Suite.InterceptOutput(remote.NewOutputInterceptorWithPolicy(outputRetentionPolicy()))
+		} else {
+			fmt.Fprintln(colorable.NewColorableStderr(), "Warning: stdout and stderr can't be captured on this platform, so -captureOutput and -test2json leave specs' output uncaptured.")
+		}
+	}
 	reporters := make([]reporters.Reporter, len(specReporters))
 	for i, reporter := range specReporters {
 		reporters[i] = reporter
@@ -248,17 +321,42 @@ func runSpecsWithCustomReporters(t GinkgoTestingT, description string, specRepor
 	return passed
 }
 
+func outputRetentionPolicy() writer.RetentionPolicy {
+	return writer.RetentionPolicy{
+		MaxBytes:  config.GinkgoConfig.OutputMaxBytes,
+		HeadLines: config.GinkgoConfig.OutputHeadLines,
+		TailLines: config.GinkgoConfig.OutputTailLines,
+		OutputDir: config.GinkgoConfig.OutputDir,
+	}
+}
+
+func ginkgoWriterTimestamps() writer.TimestampMode {
+	return writer.TimestampMode(config.GinkgoConfig.WriterTimestamps)
+}
+
 func buildDefaultReporter() Reporter {
 	remoteReportingServer := config.GinkgoConfig.StreamHost
 	if remoteReportingServer == "" {
-		stenographer := stenographer.New(!config.DefaultReporterConfig.NoColor, config.GinkgoConfig.FlakeAttempts > 1, colorable.NewColorableStdout())
+		//stdout is left to test2json events
+		out := colorable.NewColorableStdout()
+		if config.DefaultReporterConfig.Test2JSON {
+			out = colorable.NewColorableStderr()
+		}
+		stenographer := stenographer.New(!config.DefaultReporterConfig.NoColor, config.GinkgoConfig.FlakeAttempts > 1, out)
 		return reporters.NewDefaultReporter(config.DefaultReporterConfig, stenographer)
 	} else {
 		debugFile := ""
 		if config.GinkgoConfig.DebugParallel {
 			debugFile = fmt.Sprintf("ginkgo-node-%d.log", config.GinkgoConfig.ParallelNode)
 		}
-		return remote.NewForwardingReporter(config.DefaultReporterConfig, remoteReportingServer, &http.Client{}, remote.NewOutputInterceptor(), GinkgoWriter.(*writer.Writer), debugFile)
+		client, baseURL := transport.Client(remoteReportingServer)
+		reporter := remote.NewForwardingReporter(config.DefaultReporterConfig, baseURL, client, remote.NewOutputInterceptorWithPolicy(outputRetentionPolicy()), GinkgoWriter.(*writer.Writer), debugFile)
+		var streamSpecPattern *regexp.Regexp
+		if config.GinkgoConfig.StreamSpec != "" {
+			streamSpecPattern = regexp.MustCompile(config.GinkgoConfig.StreamSpec)
+		}
+		reporter.StreamLiveOutput(streamSpecPattern, time.Duration(config.GinkgoConfig.StreamSlowSpecs*float64(time.Second)))
+		return reporter
 	}
 }
 
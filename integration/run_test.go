@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -353,6 +354,40 @@ var _ = Describe("Running Specs", func() {
 		})
 	})
 
+	Context("when printing test2json events", func() {
+		BeforeEach(func() {
+			pathToTest = tmpPath("ginkgo")
+			copyIn(fixturePath("passing_ginkgo_tests"), pathToTest, false)
+			copyIn(fixturePath("does_not_compile"), tmpPath("does_not_compile"), false)
+		})
+
+		expectOnlyEventsOnStdout := func(session *gexec.Session) {
+			events := strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")
+			Ω(events).ShouldNot(BeEmpty())
+			for _, event := range events {
+				var decoded map[string]interface{}
+				Ω(json.Unmarshal([]byte(event), &decoded)).Should(Succeed(), event)
+			}
+
+			errOutput := string(session.Err.Contents())
+			Ω(errOutput).Should(ContainSubstring("Failed to compile does_not_compile"))
+			Ω(errOutput).Should(ContainSubstring("Test Suite Failed"))
+		}
+
+		It("should print nothing but the events to stdout", func() {
+			session := startGinkgo(tmpDir, "--noColor", "-test2json", "-keepGoing", "-r")
+			Eventually(session).Should(gexec.Exit(1))
+			expectOnlyEventsOnStdout(session)
+			Ω(string(session.Err.Contents())).Should(ContainSubstring("PASS"))
+		})
+
+		It("should print nothing but the events to stdout when running in parallel", func() {
+			session := startGinkgo(tmpDir, "--noColor", "-test2json", "-keepGoing", "-r", "-nodes=2")
+			Eventually(session).Should(gexec.Exit(1))
+			expectOnlyEventsOnStdout(session)
+		})
+	})
+
 	Context("when running recursively", func() {
 		BeforeEach(func() {
 			passingTest := tmpPath("A")
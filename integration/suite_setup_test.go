@@ -172,7 +172,9 @@ var _ = Describe("SuiteSetup", func() {
 			output := string(session.Out.Contents())
 
 			Ω(output).Should(ContainSubstring("Node 1 disappeared before completing BeforeSuite"))
+			Ω(output).Should(ContainSubstring("Node 1 exited unexpectedly."))
 			Ω(output).Should(ContainSubstring("Ginkgo timed out waiting for all parallel nodes to report back!"))
+			Ω(output).ShouldNot(ContainSubstring("[AfterSuite]"))
 		})
 	})
 })
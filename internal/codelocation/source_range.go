@@ -0,0 +1,66 @@
+package codelocation
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sync"
+
+	"github.com/hackrish007/ginkgo/types"
+)
+
+var endLinesLock = &sync.Mutex{}
+var endLinesByFile = map[string]map[int]int{}
+
+// EndLine returns the line on which the call expression that starts at the
+// passed-in code location ends.  For a container or subject node this is the
+// line holding the closing parenthesis of the call to Describe, It, etc.
+//
+// Source files are parsed on demand and cached.  If the file cannot be parsed,
+// or if no call starts at the code location, the code location's own line is
+// returned.
+func EndLine(codeLocation types.CodeLocation) int {
+	endLinesLock.Lock()
+	defer endLinesLock.Unlock()
+
+	endLines, ok := endLinesByFile[codeLocation.FileName]
+	if !ok {
+		endLines = parseEndLines(codeLocation.FileName)
+		endLinesByFile[codeLocation.FileName] = endLines
+	}
+
+	if endLine, ok := endLines[codeLocation.LineNumber]; ok {
+		return endLine
+	}
+	return codeLocation.LineNumber
+}
+
+func parseEndLines(fileName string) map[int]int {
+	endLines := map[int]int{}
+	if fileName == "" {
+		return endLines
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fileName, nil, 0)
+	if err != nil {
+		return endLines
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		endLine := fset.Position(call.End()).Line
+		for _, pos := range []token.Pos{call.Pos(), call.Lparen} {
+			startLine := fset.Position(pos).Line
+			if endLine > endLines[startLine] {
+				endLines[startLine] = endLine
+			}
+		}
+		return true
+	})
+
+	return endLines
+}
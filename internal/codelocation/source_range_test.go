@@ -0,0 +1,52 @@
+package codelocation_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/internal/codelocation"
+	"github.com/hackrish007/ginkgo/types"
+	. "github.com/hackrish007/gomega"
+)
+
+const sourceRangeFixture = `package fixture
+
+var _ = Describe("outer", func() {
+	It("spans", func() {
+		Ω(true).Should(BeTrue())
+	})
+
+	It("fits on one line", func() {})
+})
+`
+
+var _ = Describe("EndLine", func() {
+	var fileName string
+
+	BeforeEach(func() {
+		dir, err := ioutil.TempDir("", "ginkgo-source-range")
+		Ω(err).ShouldNot(HaveOccurred())
+		fileName = filepath.Join(dir, "fixture_test.go")
+		Ω(ioutil.WriteFile(fileName, []byte(sourceRangeFixture), 0666)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(fileName))
+	})
+
+	It("should return the line on which the call starting at the code location ends", func() {
+		Ω(codelocation.EndLine(types.CodeLocation{FileName: fileName, LineNumber: 3})).Should(Equal(9))
+		Ω(codelocation.EndLine(types.CodeLocation{FileName: fileName, LineNumber: 4})).Should(Equal(6))
+		Ω(codelocation.EndLine(types.CodeLocation{FileName: fileName, LineNumber: 8})).Should(Equal(8))
+	})
+
+	It("should return the code location's own line when no call starts there", func() {
+		Ω(codelocation.EndLine(types.CodeLocation{FileName: fileName, LineNumber: 7})).Should(Equal(7))
+	})
+
+	It("should return the code location's own line when the file cannot be parsed", func() {
+		Ω(codelocation.EndLine(types.CodeLocation{FileName: fileName + ".missing", LineNumber: 12})).Should(Equal(12))
+	})
+})
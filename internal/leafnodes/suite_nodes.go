@@ -14,16 +14,17 @@ type SuiteNode interface {
 }
 
 type simpleSuiteNode struct {
-	runner  *runner
-	outcome types.SpecState
-	failure types.SpecFailure
-	runTime time.Duration
+	runner    *runner
+	outcome   types.SpecState
+	failure   types.SpecFailure
+	startTime time.Time
+	runTime   time.Duration
 }
 
 func (node *simpleSuiteNode) Run(parallelNode int, parallelTotal int, syncHost string) bool {
-	t := time.Now()
+	node.startTime = time.Now()
 	node.outcome, node.failure = node.runner.run()
-	node.runTime = time.Since(t)
+	node.runTime = time.Since(node.startTime)
 
 	return node.outcome == types.SpecStatePassed
 }
@@ -37,6 +38,7 @@ func (node *simpleSuiteNode) Summary() *types.SetupSummary {
 		ComponentType: node.runner.nodeType,
 		CodeLocation:  node.runner.codeLocation,
 		State:         node.outcome,
+		StartTime:     node.startTime,
 		RunTime:       node.runTime,
 		Failure:       node.failure,
 	}
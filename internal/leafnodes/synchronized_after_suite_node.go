@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hackrish007/ginkgo/internal/failer"
+	"github.com/hackrish007/ginkgo/internal/transport"
 	"github.com/hackrish007/ginkgo/types"
 )
 
@@ -14,9 +15,10 @@ type synchronizedAfterSuiteNode struct {
 	runnerA *runner
 	runnerB *runner
 
-	outcome types.SpecState
-	failure types.SpecFailure
-	runTime time.Duration
+	outcome   types.SpecState
+	failure   types.SpecFailure
+	startTime time.Time
+	runTime   time.Duration
 }
 
 func NewSynchronizedAfterSuiteNode(bodyA interface{}, bodyB interface{}, codeLocation types.CodeLocation, timeout time.Duration, failer *failer.Failer) SuiteNode {
@@ -27,6 +29,11 @@ func NewSynchronizedAfterSuiteNode(bodyA interface{}, bodyB interface{}, codeLoc
 }
 
 func (node *synchronizedAfterSuiteNode) Run(parallelNode int, parallelTotal int, syncHost string) bool {
+	node.startTime = time.Now()
+	defer func() {
+		node.runTime = time.Since(node.startTime)
+	}()
+
 	node.outcome, node.failure = node.runnerA.run()
 
 	if parallelNode == 1 {
@@ -53,6 +60,7 @@ func (node *synchronizedAfterSuiteNode) Summary() *types.SetupSummary {
 		ComponentType: node.runnerA.nodeType,
 		CodeLocation:  node.runnerA.codeLocation,
 		State:         node.outcome,
+		StartTime:     node.startTime,
 		RunTime:       node.runTime,
 		Failure:       node.failure,
 	}
@@ -69,7 +77,8 @@ func (node *synchronizedAfterSuiteNode) waitUntilOtherNodesAreDone(syncHost stri
 }
 
 func (node *synchronizedAfterSuiteNode) canRun(syncHost string) bool {
-	resp, err := http.Get(syncHost + "/RemoteAfterSuiteData")
+	client, baseURL := transport.Client(syncHost)
+	resp, err := client.Get(baseURL + "/RemoteAfterSuiteData")
 	if err != nil || resp.StatusCode != http.StatusOK {
 		return false
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/hackrish007/ginkgo/internal/failer"
+	"github.com/hackrish007/ginkgo/internal/transport"
 	"github.com/hackrish007/ginkgo/types"
 )
 
@@ -18,9 +19,10 @@ type synchronizedBeforeSuiteNode struct {
 
 	data []byte
 
-	outcome types.SpecState
-	failure types.SpecFailure
-	runTime time.Duration
+	outcome   types.SpecState
+	failure   types.SpecFailure
+	startTime time.Time
+	runTime   time.Duration
 }
 
 func NewSynchronizedBeforeSuiteNode(bodyA interface{}, bodyB interface{}, codeLocation types.CodeLocation, timeout time.Duration, failer *failer.Failer) SuiteNode {
@@ -33,9 +35,9 @@ func NewSynchronizedBeforeSuiteNode(bodyA interface{}, bodyB interface{}, codeLo
 }
 
 func (node *synchronizedBeforeSuiteNode) Run(parallelNode int, parallelTotal int, syncHost string) bool {
-	t := time.Now()
+	node.startTime = time.Now()
 	defer func() {
-		node.runTime = time.Since(t)
+		node.runTime = time.Since(node.startTime)
 	}()
 
 	if parallelNode == 1 {
@@ -64,7 +66,8 @@ func (node *synchronizedBeforeSuiteNode) runA(parallelTotal int, syncHost string
 			Data:  node.data,
 			State: state,
 		}).ToJSON()
-		http.Post(syncHost+"/BeforeSuiteState", "application/json", bytes.NewBuffer(json))
+		client, baseURL := transport.Client(syncHost)
+		client.Post(baseURL+"/BeforeSuiteState", "application/json", bytes.NewBuffer(json))
 	}
 
 	return outcome, failure
@@ -80,8 +83,9 @@ func (node *synchronizedBeforeSuiteNode) waitForA(syncHost string) (types.SpecSt
 			ComponentCodeLocation: node.runnerA.codeLocation,
 		}
 	}
+	client, baseURL := transport.Client(syncHost)
 	for {
-		resp, err := http.Get(syncHost + "/BeforeSuiteState")
+		resp, err := client.Get(baseURL + "/BeforeSuiteState")
 		if err != nil || resp.StatusCode != http.StatusOK {
 			return types.SpecStateFailed, failure("Failed to fetch BeforeSuite state")
 		}
@@ -121,6 +125,7 @@ func (node *synchronizedBeforeSuiteNode) Summary() *types.SetupSummary {
 		ComponentType: node.runnerA.nodeType,
 		CodeLocation:  node.runnerA.codeLocation,
 		State:         node.outcome,
+		StartTime:     node.startTime,
 		RunTime:       node.runTime,
 		Failure:       node.failure,
 	}
@@ -10,6 +10,7 @@ where N is the number of nodes you desire.
 package remote
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/hackrish007/ginkgo/config"
@@ -40,6 +41,10 @@ type Aggregator struct {
 	specCompletions chan *types.SpecSummary
 	completedSpecs  []*types.SpecSummary
 
+	specOutputs chan *types.SpecOutputChunk
+	//the node and spec whose live output was announced last, so that the spec is only announced again once other output intervenes
+	lastLiveOutput string
+
 	suiteEndings           chan *types.SuiteSummary
 	aggregatedSuiteEndings []*types.SuiteSummary
 	specs                  []*types.SpecSummary
@@ -58,6 +63,7 @@ func NewAggregator(nodeCount int, result chan bool, config config.DefaultReporte
 		beforeSuites:    make(chan *types.SetupSummary),
 		afterSuites:     make(chan *types.SetupSummary),
 		specCompletions: make(chan *types.SpecSummary),
+		specOutputs:     make(chan *types.SpecOutputChunk),
 		suiteEndings:    make(chan *types.SuiteSummary),
 	}
 
@@ -86,6 +92,10 @@ func (aggregator *Aggregator) SpecDidComplete(specSummary *types.SpecSummary) {
 	aggregator.specCompletions <- specSummary
 }
 
+func (aggregator *Aggregator) SpecOutputDidArrive(chunk *types.SpecOutputChunk) {
+	aggregator.specOutputs <- chunk
+}
+
 func (aggregator *Aggregator) SpecSuiteDidEnd(summary *types.SuiteSummary) {
 	aggregator.suiteEndings <- summary
 }
@@ -102,6 +112,8 @@ loop:
 			aggregator.registerAfterSuite(setupSummary)
 		case specSummary := <-aggregator.specCompletions:
 			aggregator.registerSpecCompletion(specSummary)
+		case chunk := <-aggregator.specOutputs:
+			aggregator.announceLiveOutput(chunk)
 		case suite := <-aggregator.suiteEndings:
 			finished, passed := aggregator.registerSuiteEnding(suite)
 			if finished {
@@ -124,6 +136,9 @@ func (aggregator *Aggregator) registerSuiteBeginning(configAndSuite configAndSui
 	}
 
 	aggregator.stenographer.AnnounceSuite(configAndSuite.summary.SuiteDescription, configAndSuite.config.RandomSeed, configAndSuite.config.RandomizeAllSpecs, aggregator.config.Succinct)
+	if shardStenographer, ok := aggregator.stenographer.(stenographer.ShardStenographer); ok && configAndSuite.config.ShardTotal > 1 {
+		shardStenographer.AnnounceShard(configAndSuite.config.ShardIndex, configAndSuite.config.ShardTotal, aggregator.config.Succinct)
+	}
 
 	totalNumberOfSpecs := 0
 	if len(aggregator.aggregatedSuiteBeginnings) > 0 {
@@ -156,6 +171,10 @@ func (aggregator *Aggregator) flushCompletedSpecs() {
 		return
 	}
 
+	if len(aggregator.aggregatedBeforeSuites) > 0 || len(aggregator.completedSpecs) > 0 || len(aggregator.aggregatedAfterSuites) > 0 {
+		aggregator.lastLiveOutput = ""
+	}
+
 	for _, setupSummary := range aggregator.aggregatedBeforeSuites {
 		aggregator.announceBeforeSuite(setupSummary)
 	}
@@ -173,6 +192,16 @@ func (aggregator *Aggregator) flushCompletedSpecs() {
 	aggregator.aggregatedAfterSuites = []*types.SetupSummary{}
 }
 
+func (aggregator *Aggregator) announceLiveOutput(chunk *types.SpecOutputChunk) {
+	liveOutputStenographer, ok := aggregator.stenographer.(stenographer.LiveOutputStenographer)
+	if !ok {
+		return
+	}
+	spec := fmt.Sprintf("%d:%s", chunk.ParallelNode, chunk.SpecID)
+	liveOutputStenographer.AnnounceLiveOutput(chunk, spec != aggregator.lastLiveOutput)
+	aggregator.lastLiveOutput = spec
+}
+
 func (aggregator *Aggregator) announceBeforeSuite(setupSummary *types.SetupSummary) {
 	aggregator.stenographer.AnnounceCapturedOutput(setupSummary.CapturedOutput)
 	if setupSummary.State != types.SpecStatePassed {
@@ -238,6 +267,7 @@ func (aggregator *Aggregator) registerSuiteEnding(suite *types.SuiteSummary) (fi
 		aggregatedSuiteSummary.NumberOfPendingSpecs += suiteSummary.NumberOfPendingSpecs
 		aggregatedSuiteSummary.NumberOfSkippedSpecs += suiteSummary.NumberOfSkippedSpecs
 		aggregatedSuiteSummary.NumberOfFlakedSpecs += suiteSummary.NumberOfFlakedSpecs
+		aggregatedSuiteSummary.NumberOfQuarantinedFailures += suiteSummary.NumberOfQuarantinedFailures
 	}
 
 	aggregatedSuiteSummary.RunTime = time.Since(aggregator.startTime)
@@ -207,6 +207,43 @@ var _ = Describe("Aggregator", func() {
 		})
 	})
 
+	Describe("Announcing live output", func() {
+		var chunk1, chunk2, otherChunk *types.SpecOutputChunk
+
+		BeforeEach(func() {
+			beginSuite()
+			stenographer.Reset()
+
+			chunk1 = &types.SpecOutputChunk{ParallelNode: 1, SpecID: "A", Output: "a1\n"}
+			chunk2 = &types.SpecOutputChunk{ParallelNode: 1, SpecID: "A", Output: "a2\n"}
+			otherChunk = &types.SpecOutputChunk{ParallelNode: 2, SpecID: "B", Output: "b1\n"}
+		})
+
+		It("should announce output as it arrives, and the spec it comes from whenever that changes", func() {
+			aggregator.SpecOutputDidArrive(chunk1)
+			aggregator.SpecOutputDidArrive(chunk2)
+			aggregator.SpecOutputDidArrive(otherChunk)
+			aggregator.SpecOutputDidArrive(chunk1)
+			Eventually(func() interface{} {
+				return stenographer.Calls()
+			}).Should(Equal([]st.FakeStenographerCall{
+				call("AnnounceLiveOutput", chunk1, true),
+				call("AnnounceLiveOutput", chunk2, false),
+				call("AnnounceLiveOutput", otherChunk, true),
+				call("AnnounceLiveOutput", chunk1, true),
+			}))
+		})
+
+		It("should announce the spec again once other specs have been announced", func() {
+			aggregator.SpecOutputDidArrive(chunk1)
+			aggregator.SpecDidComplete(specSummary)
+			aggregator.SpecOutputDidArrive(chunk2)
+			Eventually(func() interface{} {
+				return stenographer.Calls()
+			}).Should(ContainElement(call("AnnounceLiveOutput", chunk2, true)))
+		})
+	})
+
 	Describe("Announcing the end of the suite", func() {
 		BeforeEach(func() {
 			beginSuite()
@@ -0,0 +1,308 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/internal/writer"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+//nodeState is what the server knows about a node, so that it can report on the node's behalf should the node crash
+type nodeState struct {
+	began bool
+	ended bool
+
+	//the indices of the specs handed to the node that it has yet to report completing, in the order the node runs them
+	leased       []int
+	running      *types.SpecSummary
+	runningSince time.Time
+
+	//what the node wrote to stdout and stderr since it last reported a spec, reported as the output of the spec it was running should it crash.
+	//Like the nodes' own output interceptors, it keeps no more than -outputMaxBytes: the beginning and the end of the output.
+	output *writer.RetainingBuffer
+
+	//the specs completed by this node, and by any crashed nodes it replaced
+	completed   types.SuiteSummary
+	carriedOver types.SuiteSummary
+}
+
+//node must be called with the server's lock held
+func (server *Server) node(node int) *nodeState {
+	state, ok := server.nodes[node]
+	if !ok {
+		state = &nodeState{output: writer.RetentionPolicy{MaxBytes: server.outputMaxBytes}.NewBuffer()}
+		server.nodes[node] = state
+	}
+	return state
+}
+
+//registerSuiteBeginning returns the number of times the beginning of the suite should be forwarded to the reporters:
+//none for a node replacing a crashed node that had already begun the suite, and once more for every crashed node that never began it
+func (server *Server) registerSuiteBeginning(node int, body []byte) int {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	if server.suiteBeginning == nil {
+		server.suiteBeginning = body
+	}
+	if node > 0 {
+		state := server.node(node)
+		if state.began {
+			return 0
+		}
+		state.began = true
+	}
+
+	repetitions := 1 + server.missingBeginnings
+	server.missingBeginnings = 0
+	return repetitions
+}
+
+func (server *Server) registerSpecWillRun(summary *types.SpecSummary) {
+	if summary == nil || summary.ParallelNode == 0 {
+		return
+	}
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	state := server.node(summary.ParallelNode)
+	state.running = summary
+	state.runningSince = time.Now()
+	state.output.Reset()
+}
+
+func (server *Server) registerSpecDidComplete(summary *types.SpecSummary) {
+	if summary == nil || summary.ParallelNode == 0 {
+		return
+	}
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	state := server.node(summary.ParallelNode)
+	state.running = nil
+	state.output.Reset()
+	state.leased = server.withoutSpec(state.leased, summary.ID)
+	tallySpec(&state.completed, summary)
+}
+
+func (server *Server) registerInterceptedOutput(chunk *types.SpecOutputChunk) {
+	if chunk.ParallelNode == 0 {
+		return
+	}
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	server.node(chunk.ParallelNode).output.Write([]byte(chunk.Output))
+}
+
+//withoutSpec removes a spec from a node's lease.  Nodes run their leases in order, so a spec without an ID is the first one.
+//Specs that are retried with -flakeAttempts complete more than once and are only in the lease the first time.
+//It must be called with the server's lock held.
+func (server *Server) withoutSpec(leased []int, id string) []int {
+	if len(leased) == 0 {
+		return leased
+	}
+	if id == "" {
+		return leased[1:]
+	}
+	for i, index := range leased {
+		if index < len(server.registeredSpecs) && server.registeredSpecs[index].ID == id {
+			return append(leased[:i:i], leased[i+1:]...)
+		}
+	}
+	return leased
+}
+
+//registerSuiteEnding adds the specs completed by any crashed nodes this node replaced to the node's summary
+func (server *Server) registerSuiteEnding(node int, summary *types.SuiteSummary) {
+	if summary == nil || node == 0 {
+		return
+	}
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	state := server.node(node)
+	state.ended = true
+	carriedOver := state.carriedOver
+
+	addTally(summary, carriedOver)
+	if carriedOver.NumberOfFailedSpecs > 0 {
+		summary.SuiteSucceeded = false
+	}
+}
+
+/*
+NodeExited must be called whenever a node's process exits, before any replacement node is started.  It returns the resources leased to the node to their pools,
+and returns false if the node had reported the end of its suite.
+
+Otherwise the node crashed and NodeExited returns a description of what the node was doing.  If the node crashed while running a spec, the server reports the spec as failed,
+along with what the node wrote to stdout and stderr after it last reported on a spec.
+If allowRestart is also true, the server expects a replacement node, started with the same node number, to finish the suite and returns restart=true.  Otherwise the server reports the end of the crashed node's suite on the node's behalf.
+
+Crashes outside of specs, e.g. in a BeforeSuite or AfterSuite, aren't reported: the other nodes report the setup nodes they were waiting for, and the CLI stops waiting for the crashed node's report.
+*/
+func (server *Server) NodeExited(node int, allowRestart bool) (crashed bool, description string, restart bool) {
+	server.sharedState.ReleaseNode(node)
+
+	server.lock.Lock()
+	state := server.node(node)
+	//without reporters, nodes stream their output instead of reporting to the server, so all we can tell is whether a node exited while holding a spec
+	holdingSpec := len(state.leased) > 0 && state.leased[0] < len(server.registeredSpecs)
+	if state.ended || (len(server.reporters) == 0 && !holdingSpec) {
+		server.lock.Unlock()
+		return false, "", false
+	}
+
+	culprit, description := server.culprit(node, state)
+	if culprit == nil {
+		server.lock.Unlock()
+		return true, fmt.Sprintf("Node %d exited unexpectedly", node), false
+	}
+	culprit.CapturedOutput = string(state.output.Bytes())
+	state.output.Reset()
+	tallySpec(&state.completed, culprit)
+	restart = allowRestart
+
+	replayBeginning := false
+	if !state.began && !restart {
+		state.began = true
+		if server.suiteBeginning != nil {
+			replayBeginning = true
+		} else {
+			server.missingBeginnings++
+		}
+	}
+
+	//the specs the node leased but never got to run go back to the other nodes
+	if state.running != nil {
+		state.leased = server.withoutSpec(state.leased, state.running.ID)
+	} else if len(state.leased) > 0 {
+		state.leased = state.leased[1:]
+	}
+	server.requeued = append(server.requeued, state.leased...)
+	state.leased = nil
+
+	addTally(&state.carriedOver, state.completed)
+	state.completed = types.SuiteSummary{}
+	tally := state.carriedOver
+	if restart {
+		state.running = nil
+	} else {
+		state.ended = true
+	}
+	suiteBeginning := server.suiteBeginning
+	numberOfSpecs := len(server.registeredSpecs)
+	server.lock.Unlock()
+
+	description = fmt.Sprintf("Node %d exited while running %s", node, description)
+
+	if replayBeginning {
+		var data struct {
+			Config  config.GinkgoConfigType `json:"config"`
+			Summary *types.SuiteSummary     `json:"suite-summary"`
+		}
+		json.Unmarshal(suiteBeginning, &data)
+		for _, reporter := range server.reporters {
+			reporter.SpecSuiteWillBegin(data.Config, data.Summary)
+		}
+	}
+
+	for _, reporter := range server.reporters {
+		reporter.SpecDidComplete(culprit)
+	}
+
+	if !restart {
+		tally.SuiteSucceeded = false
+		tally.NumberOfSpecsBeforeParallelization = numberOfSpecs
+		for _, reporter := range server.reporters {
+			reporter.SpecSuiteDidEnd(&tally)
+		}
+	}
+
+	return true, description, restart
+}
+
+//culprit returns the spec a crashed node was running, if any, marked as failed, along with a description of the spec.
+//The node may have leased the spec from the server without reporting that it started running it, in which case all we know is the spec's name.
+//It must be called with the server's lock held.
+func (server *Server) culprit(node int, state *nodeState) (*types.SpecSummary, string) {
+	var culprit types.SpecSummary
+	switch {
+	case state.running != nil:
+		culprit = *state.running
+		culprit.RunTime = time.Since(state.runningSince)
+	case len(state.leased) > 0 && state.leased[0] < len(server.registeredSpecs):
+		culprit = types.SpecSummary{
+			ComponentTexts:         []string{"[Top Level]", strings.TrimPrefix(server.registeredSpecs[state.leased[0]].Name, "[Top Level] ")},
+			ComponentCodeLocations: []types.CodeLocation{{}, {}},
+			ParallelNode:           node,
+		}
+	default:
+		return nil, ""
+	}
+
+	location := types.CodeLocation{}
+	if len(culprit.ComponentCodeLocations) > 0 {
+		location = culprit.ComponentCodeLocations[len(culprit.ComponentCodeLocations)-1]
+	}
+	componentType := types.SpecComponentTypeIt
+	if culprit.IsMeasurement {
+		componentType = types.SpecComponentTypeMeasure
+	}
+	culprit.State = types.SpecStateFailed
+	culprit.Failure = types.SpecFailure{
+		Message:               fmt.Sprintf("Node %d exited while running this spec", node),
+		Location:              location,
+		ComponentType:         componentType,
+		ComponentIndex:        len(culprit.ComponentTexts) - 1,
+		ComponentCodeLocation: location,
+	}
+	return &culprit, fmt.Sprintf("%q", culpritText(culprit.ComponentTexts))
+}
+
+func culpritText(componentTexts []string) string {
+	text := ""
+	for i, componentText := range componentTexts {
+		if i == 0 && len(componentTexts) > 1 {
+			continue
+		}
+		if text != "" {
+			text += " "
+		}
+		text += componentText
+	}
+	return text
+}
+
+func tallySpec(tally *types.SuiteSummary, summary *types.SpecSummary) {
+	tally.NumberOfTotalSpecs++
+	switch {
+	case summary.State == types.SpecStatePending:
+		tally.NumberOfPendingSpecs++
+	case summary.State == types.SpecStateSkipped:
+		tally.NumberOfSkippedSpecs++
+	case summary.State == types.SpecStatePassed:
+		tally.NumberOfSpecsThatWillBeRun++
+		tally.NumberOfPassedSpecs++
+	case summary.HasQuarantinedFailure():
+		tally.NumberOfSpecsThatWillBeRun++
+		tally.NumberOfQuarantinedFailures++
+	case summary.State.IsFailure():
+		tally.NumberOfSpecsThatWillBeRun++
+		tally.NumberOfFailedSpecs++
+	}
+}
+
+func addTally(summary *types.SuiteSummary, tally types.SuiteSummary) {
+	summary.NumberOfTotalSpecs += tally.NumberOfTotalSpecs
+	summary.NumberOfSpecsThatWillBeRun += tally.NumberOfSpecsThatWillBeRun
+	summary.NumberOfPassedSpecs += tally.NumberOfPassedSpecs
+	summary.NumberOfFailedSpecs += tally.NumberOfFailedSpecs
+	summary.NumberOfPendingSpecs += tally.NumberOfPendingSpecs
+	summary.NumberOfSkippedSpecs += tally.NumberOfSkippedSpecs
+	summary.NumberOfQuarantinedFailures += tally.NumberOfQuarantinedFailures
+}
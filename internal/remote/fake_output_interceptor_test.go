@@ -1,11 +1,17 @@
 package remote_test
 
-import "os"
+import (
+	"io"
+
+	"github.com/hackrish007/ginkgo/internal/writer"
+)
 
 type fakeOutputInterceptor struct {
 	DidStartInterceptingOutput bool
 	DidStopInterceptingOutput  bool
 	InterceptedOutput          string
+	StreamTarget               io.Writer
+	Policy                     writer.RetentionPolicy
 }
 
 func (interceptor *fakeOutputInterceptor) StartInterceptingOutput() error {
@@ -18,5 +24,11 @@ func (interceptor *fakeOutputInterceptor) StopInterceptingAndReturnOutput() (str
 	return interceptor.InterceptedOutput, nil
 }
 
-func (interceptor *fakeOutputInterceptor) StreamTo(*os.File) {
+func (interceptor *fakeOutputInterceptor) StopInterceptingAndRetainOutput(name string) (string, error) {
+	interceptor.DidStopInterceptingOutput = true
+	return string(interceptor.Policy.Retain([]byte(interceptor.InterceptedOutput), name)), nil
+}
+
+func (interceptor *fakeOutputInterceptor) StreamTo(out io.Writer) {
+	interceptor.StreamTarget = out
 }
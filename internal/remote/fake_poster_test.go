@@ -4,6 +4,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sync"
 )
 
 type post struct {
@@ -14,6 +15,7 @@ type post struct {
 
 type fakePoster struct {
 	posts []post
+	lock  sync.Mutex
 }
 
 func newFakePoster() *fakePoster {
@@ -24,6 +26,8 @@ func newFakePoster() *fakePoster {
 
 func (poster *fakePoster) Post(url string, bodyType string, body io.Reader) (resp *http.Response, err error) {
 	bodyContent, _ := ioutil.ReadAll(body)
+	poster.lock.Lock()
+	defer poster.lock.Unlock()
 	poster.posts = append(poster.posts, post{
 		url:         url,
 		bodyType:    bodyType,
@@ -31,3 +35,16 @@ func (poster *fakePoster) Post(url string, bodyType string, body io.Reader) (res
 	})
 	return nil, nil
 }
+
+//postsTo returns the posts made to url so far.  It is safe to call while another goroutine posts.
+func (poster *fakePoster) postsTo(url string) []post {
+	poster.lock.Lock()
+	defer poster.lock.Unlock()
+	posts := []post{}
+	for _, post := range poster.posts {
+		if post.url == url {
+			posts = append(posts, post)
+		}
+	}
+	return posts
+}
@@ -7,6 +7,9 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/hackrish007/ginkgo/internal/writer"
 	"github.com/hackrish007/ginkgo/reporters"
@@ -39,6 +42,14 @@ type ForwardingReporter struct {
 	debugMode         bool
 	debugFile         *os.File
 	nestedReporter    *reporters.DefaultReporter
+	ginkgoWriter      *writer.Writer
+	parallelNode      int
+	attempts          map[string]int
+
+	streamSpecPattern *regexp.Regexp
+	streamSlowSpecs   time.Duration
+	liveOutput        *liveOutputStreamer
+	interceptedOutput *interceptedOutputStreamer
 }
 
 func NewForwardingReporter(config config.DefaultReporterConfigType, serverHost string, poster Poster, outputInterceptor OutputInterceptor, ginkgoWriter *writer.Writer, debugFile string) *ForwardingReporter {
@@ -46,6 +57,8 @@ func NewForwardingReporter(config config.DefaultReporterConfigType, serverHost s
 		serverHost:        serverHost,
 		poster:            poster,
 		outputInterceptor: outputInterceptor,
+		ginkgoWriter:      ginkgoWriter,
+		attempts:          map[string]int{},
 	}
 
 	if debugFile != "" {
@@ -61,7 +74,6 @@ func NewForwardingReporter(config config.DefaultReporterConfigType, serverHost s
 			//if verbose is true then the GinkgoWriter emits to stdout.  Don't _also_ redirect GinkgoWriter output as that will result in duplication.
 			ginkgoWriter.AndRedirectTo(reporter.debugFile)
 		}
-		outputInterceptor.StreamTo(reporter.debugFile) //This is not working
 
 		stenographer := stenographer.New(false, true, reporter.debugFile)
 		config.Succinct = false
@@ -79,6 +91,38 @@ func (reporter *ForwardingReporter) post(path string, data interface{}) {
 	reporter.poster.Post(reporter.serverHost+path, "application/json", buffer)
 }
 
+//StreamLiveOutput makes the reporter send the server what specs write to the GinkgoWriter while they run: from the start for specs whose full text matches specPattern,
+//and once they have run for slowSpecThreshold for all other specs.  A nil pattern or a zero threshold turns the corresponding streaming off.
+func (reporter *ForwardingReporter) StreamLiveOutput(specPattern *regexp.Regexp, slowSpecThreshold time.Duration) {
+	reporter.streamSpecPattern = specPattern
+	reporter.streamSlowSpecs = slowSpecThreshold
+}
+
+func (reporter *ForwardingReporter) startLiveOutput(specSummary *types.SpecSummary) {
+	if reporter.ginkgoWriter == nil || len(specSummary.ComponentTexts) == 0 {
+		return
+	}
+	if reporter.streamSpecPattern != nil && reporter.streamSpecPattern.MatchString(strings.Join(specSummary.ComponentTexts[1:], " ")) {
+		reporter.liveOutput = newLiveOutputStreamer(reporter, specSummary, 0)
+	} else if reporter.streamSlowSpecs > 0 {
+		reporter.liveOutput = newLiveOutputStreamer(reporter, specSummary, reporter.streamSlowSpecs)
+	}
+}
+
+func (reporter *ForwardingReporter) stopLiveOutput() {
+	if reporter.liveOutput != nil {
+		reporter.liveOutput.Stop()
+		reporter.liveOutput = nil
+	}
+}
+
+//flushInterceptedOutput makes sure the server has received what the node wrote to stdout and stderr before the report that is about to be sent
+func (reporter *ForwardingReporter) flushInterceptedOutput() {
+	if reporter.interceptedOutput != nil {
+		reporter.interceptedOutput.Flush()
+	}
+}
+
 func (reporter *ForwardingReporter) SpecSuiteWillBegin(conf config.GinkgoConfigType, summary *types.SuiteSummary) {
 	data := struct {
 		Config  config.GinkgoConfigType `json:"config"`
@@ -88,6 +132,14 @@ func (reporter *ForwardingReporter) SpecSuiteWillBegin(conf config.GinkgoConfigT
 		summary,
 	}
 
+	reporter.parallelNode = conf.ParallelNode
+	//the server holds on to the node's output should the node crash before reporting it
+	reporter.interceptedOutput = newInterceptedOutputStreamer(reporter, conf.ParallelNode, conf.OutputMaxBytes)
+	if reporter.debugMode {
+		reporter.outputInterceptor.StreamTo(io.MultiWriter(reporter.debugFile, reporter.interceptedOutput))
+	} else {
+		reporter.outputInterceptor.StreamTo(reporter.interceptedOutput)
+	}
 	reporter.outputInterceptor.StartInterceptingOutput()
 	if reporter.debugMode {
 		reporter.nestedReporter.SpecSuiteWillBegin(conf, summary)
@@ -97,13 +149,13 @@ func (reporter *ForwardingReporter) SpecSuiteWillBegin(conf config.GinkgoConfigT
 }
 
 func (reporter *ForwardingReporter) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {
-	output, _ := reporter.outputInterceptor.StopInterceptingAndReturnOutput()
+	setupSummary.CapturedOutput, _ = reporter.outputInterceptor.StopInterceptingAndRetainOutput(fmt.Sprintf("BeforeSuite-node-%d", reporter.parallelNode))
 	reporter.outputInterceptor.StartInterceptingOutput()
-	setupSummary.CapturedOutput = output
 	if reporter.debugMode {
 		reporter.nestedReporter.BeforeSuiteDidRun(setupSummary)
 		reporter.debugFile.Sync()
 	}
+	reporter.flushInterceptedOutput()
 	reporter.post("/BeforeSuiteDidRun", setupSummary)
 }
 
@@ -112,28 +164,34 @@ func (reporter *ForwardingReporter) SpecWillRun(specSummary *types.SpecSummary)
 		reporter.nestedReporter.SpecWillRun(specSummary)
 		reporter.debugFile.Sync()
 	}
+	reporter.flushInterceptedOutput()
 	reporter.post("/SpecWillRun", specSummary)
+	reporter.startLiveOutput(specSummary)
 }
 
 func (reporter *ForwardingReporter) SpecDidComplete(specSummary *types.SpecSummary) {
-	output, _ := reporter.outputInterceptor.StopInterceptingAndReturnOutput()
+	//the last of the live output must reach the server before the spec's report does
+	reporter.stopLiveOutput()
+	//specs retried with -flakeAttempts complete once per attempt
+	reporter.attempts[specSummary.ID]++
+	specSummary.CapturedOutput, _ = reporter.outputInterceptor.StopInterceptingAndRetainOutput(writer.SpecOutputName(specSummary.ID, reporter.parallelNode, reporter.attempts[specSummary.ID]))
 	reporter.outputInterceptor.StartInterceptingOutput()
-	specSummary.CapturedOutput = output
 	if reporter.debugMode {
 		reporter.nestedReporter.SpecDidComplete(specSummary)
 		reporter.debugFile.Sync()
 	}
+	reporter.flushInterceptedOutput()
 	reporter.post("/SpecDidComplete", specSummary)
 }
 
 func (reporter *ForwardingReporter) AfterSuiteDidRun(setupSummary *types.SetupSummary) {
-	output, _ := reporter.outputInterceptor.StopInterceptingAndReturnOutput()
+	setupSummary.CapturedOutput, _ = reporter.outputInterceptor.StopInterceptingAndRetainOutput(fmt.Sprintf("AfterSuite-node-%d", reporter.parallelNode))
 	reporter.outputInterceptor.StartInterceptingOutput()
-	setupSummary.CapturedOutput = output
 	if reporter.debugMode {
 		reporter.nestedReporter.AfterSuiteDidRun(setupSummary)
 		reporter.debugFile.Sync()
 	}
+	reporter.flushInterceptedOutput()
 	reporter.post("/AfterSuiteDidRun", setupSummary)
 }
 
@@ -143,5 +201,11 @@ func (reporter *ForwardingReporter) SpecSuiteDidEnd(summary *types.SuiteSummary)
 		reporter.nestedReporter.SpecSuiteDidEnd(summary)
 		reporter.debugFile.Sync()
 	}
-	reporter.post("/SpecSuiteDidEnd", summary)
+	reporter.flushInterceptedOutput()
+	//the server needs to know which node ended its suite in order to tell crashed nodes apart
+	if reporter.parallelNode > 0 {
+		reporter.post(fmt.Sprintf("/SpecSuiteDidEnd?node=%d", reporter.parallelNode), summary)
+	} else {
+		reporter.post("/SpecSuiteDidEnd", summary)
+	}
 }
@@ -2,10 +2,15 @@ package remote_test
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"time"
 
 	. "github.com/hackrish007/ginkgo"
 	"github.com/hackrish007/ginkgo/config"
 	. "github.com/hackrish007/ginkgo/internal/remote"
+	"github.com/hackrish007/ginkgo/internal/writer"
 	"github.com/hackrish007/ginkgo/types"
 	. "github.com/hackrish007/gomega"
 )
@@ -71,6 +76,23 @@ var _ = Describe("ForwardingReporter", func() {
 			Ω(sentData.SentConfig).Should(Equal(config.GinkgoConfig))
 			Ω(sentData.SentSuiteSummary).Should(Equal(suiteSummary))
 		})
+
+		It("should POST intercepted output as it arrives, before the reports that follow it", func() {
+			interceptor.StreamTarget.Write([]byte("a\n"))
+			interceptor.StreamTarget.Write([]byte("b"))
+			reporter.SpecWillRun(specSummary)
+
+			Ω(poster.posts[len(poster.posts)-1].url).Should(Equal("http://127.0.0.1:7788/SpecWillRun"))
+			output := ""
+			for _, post := range poster.postsTo("http://127.0.0.1:7788/SpecOutput") {
+				var chunk types.SpecOutputChunk
+				Ω(json.Unmarshal(post.bodyContent, &chunk)).Should(Succeed())
+				Ω(chunk.Intercepted).Should(BeTrue())
+				Ω(chunk.ParallelNode).Should(Equal(config.GinkgoConfig.ParallelNode))
+				output += chunk.Output
+			}
+			Ω(output).Should(Equal("a\nb"))
+		})
 	})
 
 	Context("when a BeforeSuite completes", func() {
@@ -160,6 +182,142 @@ var _ = Describe("ForwardingReporter", func() {
 		})
 	})
 
+	Context("when the output interceptor has a retention policy", func() {
+		BeforeEach(func() {
+			interceptor.Policy = writer.RetentionPolicy{MaxBytes: 10}
+			reporter.SpecDidComplete(specSummary)
+		})
+
+		It("should truncate the intercepted output before POSTing it", func() {
+			var summary *types.SpecSummary
+			err := json.Unmarshal(poster.posts[0].bodyContent, &summary)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(summary.CapturedOutput).Should(Equal("The i\n...[13 bytes of output truncated]...\ntput!"))
+		})
+	})
+
+	Context("when the output interceptor's retention policy saves full output", func() {
+		var outputDir string
+
+		BeforeEach(func() {
+			var err error
+			outputDir, err = ioutil.TempDir("", "ginkgo-output")
+			Ω(err).ShouldNot(HaveOccurred())
+			interceptor.Policy = writer.RetentionPolicy{MaxBytes: 10, OutputDir: outputDir}
+			reporter.SpecSuiteWillBegin(config.GinkgoConfigType{ParallelNode: 2}, suiteSummary)
+			specSummary.ID = "my_test.go:My/Spec"
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(outputDir)
+		})
+
+		It("should save the full output of each attempt at a spec to its own file, named after the node", func() {
+			reporter.SpecDidComplete(specSummary)
+			reporter.SpecDidComplete(specSummary)
+
+			files, err := ioutil.ReadDir(outputDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(files).Should(HaveLen(2))
+			Ω(files[0].Name()).Should(HavePrefix("my_test.go_My_Spec-node-2-attempt-1-"))
+			Ω(files[1].Name()).Should(HavePrefix("my_test.go_My_Spec-node-2-attempt-2-"))
+		})
+	})
+
+	Context("when streaming live output", func() {
+		var ginkgoWriter *writer.Writer
+		var originalInterval time.Duration
+
+		BeforeEach(func() {
+			originalInterval = LiveOutputInterval
+			LiveOutputInterval = 10 * time.Millisecond
+			ginkgoWriter = writer.New(ioutil.Discard)
+			ginkgoWriter.SetStream(false)
+			reporter = NewForwardingReporter(config.DefaultReporterConfigType{}, serverHost, poster, interceptor, ginkgoWriter, "")
+			reporter.SpecSuiteWillBegin(config.GinkgoConfigType{ParallelNode: 2}, suiteSummary)
+			specSummary.ID = "my_test.go:My/Spec"
+			specSummary.ComponentTexts = []string{"[Top Level]", "My", "Spec"}
+		})
+
+		AfterEach(func() {
+			LiveOutputInterval = originalInterval
+		})
+
+		chunks := func() []types.SpecOutputChunk {
+			chunks := []types.SpecOutputChunk{}
+			for _, post := range poster.postsTo("http://127.0.0.1:7788/SpecOutput") {
+				var chunk types.SpecOutputChunk
+				json.Unmarshal(post.bodyContent, &chunk)
+				chunks = append(chunks, chunk)
+			}
+			return chunks
+		}
+
+		Context("for specs that match the pattern", func() {
+			BeforeEach(func() {
+				reporter.StreamLiveOutput(regexp.MustCompile("^My Spec$"), 0)
+				reporter.SpecWillRun(specSummary)
+			})
+
+			It("should POST whole lines while the spec runs, and the rest before the spec completes", func() {
+				ginkgoWriter.Write([]byte("line 1\npart"))
+				Eventually(chunks).Should(HaveLen(1))
+				Ω(chunks()[0]).Should(Equal(types.SpecOutputChunk{
+					ParallelNode:   2,
+					SpecID:         "my_test.go:My/Spec",
+					ComponentTexts: []string{"[Top Level]", "My", "Spec"},
+					Output:         "line 1\n",
+				}))
+
+				ginkgoWriter.Write([]byte("ial"))
+				Consistently(chunks, 50*time.Millisecond).Should(HaveLen(1))
+
+				reporter.SpecDidComplete(specSummary)
+				Ω(chunks()).Should(HaveLen(2))
+				Ω(chunks()[1].Output).Should(Equal("partial"))
+				Ω(poster.posts[len(poster.posts)-1].url).Should(Equal("http://127.0.0.1:7788/SpecDidComplete"))
+
+				var summary *types.SpecSummary
+				json.Unmarshal(poster.posts[len(poster.posts)-1].bodyContent, &summary)
+				Ω(summary.CapturedOutput).Should(Equal(interceptor.InterceptedOutput))
+			})
+		})
+
+		Context("for specs that don't match the pattern", func() {
+			BeforeEach(func() {
+				reporter.StreamLiveOutput(regexp.MustCompile("Other"), 0)
+				reporter.SpecWillRun(specSummary)
+			})
+
+			It("should not POST their output", func() {
+				ginkgoWriter.Write([]byte("line 1\n"))
+				reporter.SpecDidComplete(specSummary)
+				Ω(chunks()).Should(BeEmpty())
+			})
+		})
+
+		Context("for slow specs", func() {
+			BeforeEach(func() {
+				reporter.StreamLiveOutput(nil, 50*time.Millisecond)
+				reporter.SpecWillRun(specSummary)
+			})
+
+			It("should start POSTing their output, from the beginning, once they turn out to be slow", func() {
+				ginkgoWriter.Write([]byte("line 1\n"))
+				Consistently(chunks, 30*time.Millisecond).Should(BeEmpty())
+				Eventually(chunks).Should(HaveLen(1))
+				Ω(chunks()[0].Output).Should(Equal("line 1\n"))
+				reporter.SpecDidComplete(specSummary)
+			})
+
+			It("should not POST the output of specs that complete in time", func() {
+				ginkgoWriter.Write([]byte("line 1\n"))
+				reporter.SpecDidComplete(specSummary)
+				Consistently(chunks, 100*time.Millisecond).Should(BeEmpty())
+			})
+		})
+	})
+
 	Context("When a suite ends", func() {
 		BeforeEach(func() {
 			reporter.SpecSuiteDidEnd(suiteSummary)
@@ -177,5 +335,16 @@ var _ = Describe("ForwardingReporter", func() {
 
 			Ω(summary).Should(Equal(suiteSummary))
 		})
+
+		Context("when the reporter knows its parallel node", func() {
+			BeforeEach(func() {
+				reporter.SpecSuiteWillBegin(config.GinkgoConfigType{ParallelNode: 2}, suiteSummary)
+				reporter.SpecSuiteDidEnd(suiteSummary)
+			})
+
+			It("should identify the node to the Ginkgo server", func() {
+				Ω(poster.posts[len(poster.posts)-1].url).Should(Equal("http://127.0.0.1:7788/SpecSuiteDidEnd?node=2"))
+			})
+		})
 	})
 })
@@ -0,0 +1,166 @@
+package remote
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/hackrish007/ginkgo/internal/writer"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+//LiveOutputInterval is how often a node sends the server what a streamed spec has written to the GinkgoWriter since it last did so
+var LiveOutputInterval = 100 * time.Millisecond
+
+//LiveOutputReporter is implemented by reporters that show what specs running on parallel nodes write to the GinkgoWriter while the specs are still running.
+//The server hands such reporters the output it receives, in addition to the usual reports.
+type LiveOutputReporter interface {
+	SpecOutputDidArrive(chunk *types.SpecOutputChunk)
+}
+
+/*
+liveOutputStreamer sends a running spec's GinkgoWriter output to the server as the spec writes it.
+It polls the GinkgoWriter's buffer, starting after a delay for specs streamed only once they turn out to be slow,
+and sends whole lines so that the CLI can interleave output from several nodes.
+*/
+type liveOutputStreamer struct {
+	reporter *ForwardingReporter
+	chunk    types.SpecOutputChunk
+	sent     int
+	started  bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newLiveOutputStreamer(reporter *ForwardingReporter, specSummary *types.SpecSummary, delay time.Duration) *liveOutputStreamer {
+	streamer := &liveOutputStreamer{
+		reporter: reporter,
+		chunk: types.SpecOutputChunk{
+			ParallelNode:   reporter.parallelNode,
+			SpecID:         specSummary.ID,
+			ComponentTexts: specSummary.ComponentTexts,
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go streamer.run(delay)
+	return streamer
+}
+
+func (streamer *liveOutputStreamer) run(delay time.Duration) {
+	defer close(streamer.done)
+	select {
+	case <-time.After(delay):
+	case <-streamer.stop:
+		return
+	}
+
+	streamer.started = true
+	ticker := time.NewTicker(LiveOutputInterval)
+	defer ticker.Stop()
+	for {
+		streamer.send(false)
+		select {
+		case <-ticker.C:
+		case <-streamer.stop:
+			return
+		}
+	}
+}
+
+//send sends the output written since the last chunk.  Unless final is true it holds back a trailing partial line.
+func (streamer *liveOutputStreamer) send(final bool) {
+	//the GinkgoWriter's retention policy may have dropped some of what was written since the last chunk, in which case a marker takes its place
+	output, written := streamer.reporter.ginkgoWriter.BytesSince(streamer.sent)
+	if !final {
+		held := len(output) - bytes.LastIndexByte(output, '\n') - 1
+		output = output[:len(output)-held]
+		written -= held
+	}
+	if len(output) == 0 {
+		return
+	}
+
+	chunk := streamer.chunk
+	chunk.Output = string(output)
+	streamer.reporter.post("/SpecOutput", chunk)
+	streamer.sent = written
+}
+
+//Stop stops polling and, if the spec was being streamed, sends whatever output is left
+func (streamer *liveOutputStreamer) Stop() {
+	close(streamer.stop)
+	<-streamer.done
+	if streamer.started {
+		streamer.send(true)
+	}
+}
+
+/*
+interceptedOutputStreamer sends what a node writes to stdout and stderr to the server as the node's output interceptor drains it,
+so that the server can report the output of a node that crashes before reporting the spec it was running.
+Writes never wait on the server: a goroutine sends whatever has accumulated since the last chunk.
+Should the server fall behind, what accumulates is bounded by -outputMaxBytes: the beginning and the end of it.
+*/
+type interceptedOutputStreamer struct {
+	reporter *ForwardingReporter
+	node     int
+
+	lock    *sync.Mutex
+	idle    *sync.Cond
+	pending *writer.RetainingBuffer
+	sending bool
+}
+
+func newInterceptedOutputStreamer(reporter *ForwardingReporter, node int, maxBytes int) *interceptedOutputStreamer {
+	lock := &sync.Mutex{}
+	return &interceptedOutputStreamer{
+		reporter: reporter,
+		node:     node,
+		lock:     lock,
+		idle:     sync.NewCond(lock),
+		pending:  writer.RetentionPolicy{MaxBytes: maxBytes}.NewBuffer(),
+	}
+}
+
+func (streamer *interceptedOutputStreamer) Write(p []byte) (int, error) {
+	streamer.lock.Lock()
+	defer streamer.lock.Unlock()
+	streamer.pending.Write(p)
+	if !streamer.sending {
+		streamer.sending = true
+		go streamer.send()
+	}
+	return len(p), nil
+}
+
+func (streamer *interceptedOutputStreamer) send() {
+	for {
+		streamer.lock.Lock()
+		if streamer.pending.Len() == 0 {
+			streamer.sending = false
+			streamer.idle.Broadcast()
+			streamer.lock.Unlock()
+			return
+		}
+		output := string(streamer.pending.Bytes())
+		streamer.pending.Reset()
+		streamer.lock.Unlock()
+
+		streamer.reporter.post("/SpecOutput", types.SpecOutputChunk{
+			ParallelNode: streamer.node,
+			Output:       output,
+			Intercepted:  true,
+		})
+	}
+}
+
+//Flush waits until the server has received everything written so far.  Reports must not overtake the output that preceded them.
+func (streamer *interceptedOutputStreamer) Flush() {
+	streamer.lock.Lock()
+	defer streamer.lock.Unlock()
+	for streamer.sending {
+		streamer.idle.Wait()
+	}
+}
@@ -1,6 +1,9 @@
 package remote
 
-import "os"
+import (
+	"io"
+	"time"
+)
 
 /*
 The OutputInterceptor is used by the ForwardingReporter to
@@ -9,5 +12,11 @@ intercept and capture all stdin and stderr output during a test run.
 type OutputInterceptor interface {
 	StartInterceptingOutput() error
 	StopInterceptingAndReturnOutput() (string, error)
-	StreamTo(*os.File)
+	//StopInterceptingAndRetainOutput applies the interceptor's retention policy to the output, saving the full output under name should the policy call for it
+	StopInterceptingAndRetainOutput(name string) (string, error)
+	StreamTo(io.Writer)
 }
+
+//ChildProcessGracePeriod bounds how long StopInterceptingAndReturnOutput waits for subprocesses that inherited stdout and stderr,
+//and still hold them open, before giving up on the rest of their output
+var ChildProcessGracePeriod = time.Second
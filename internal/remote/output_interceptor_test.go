@@ -0,0 +1,157 @@
+package remote_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/ginkgo/internal/remote"
+	"github.com/hackrish007/ginkgo/internal/writer"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("OutputInterceptor", func() {
+	BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			Skip("output is not intercepted on windows")
+		}
+	})
+
+	It("should capture stdout and stderr, and restore them when it stops", func() {
+		interceptor := NewOutputInterceptor()
+		originalStdout, err := os.Stdout.Stat()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(interceptor.StartInterceptingOutput()).Should(Succeed())
+		fmt.Fprint(os.Stdout, "to stdout ")
+		fmt.Fprint(os.Stderr, "to stderr")
+		output, err := interceptor.StopInterceptingAndReturnOutput()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(output).Should(Equal("to stdout to stderr"))
+
+		stdout, err := os.Stdout.Stat()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(os.SameFile(stdout, originalStdout)).Should(BeTrue())
+
+		Ω(interceptor.StartInterceptingOutput()).Should(Succeed())
+		fmt.Fprint(os.Stdout, "again")
+		output, err = interceptor.StopInterceptingAndReturnOutput()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(output).Should(Equal("again"))
+	})
+
+	It("should refuse to start twice or stop when it isn't intercepting", func() {
+		interceptor := NewOutputInterceptor()
+		_, err := interceptor.StopInterceptingAndReturnOutput()
+		Ω(err).Should(HaveOccurred())
+
+		Ω(interceptor.StartInterceptingOutput()).Should(Succeed())
+		Ω(interceptor.StartInterceptingOutput()).ShouldNot(Succeed())
+		_, err = interceptor.StopInterceptingAndReturnOutput()
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should keep the beginning and the end of the output, within the retention policy's limit, when it exceeds the limit", func() {
+		interceptor := NewOutputInterceptorWithPolicy(writer.RetentionPolicy{MaxBytes: 100})
+
+		Ω(interceptor.StartInterceptingOutput()).Should(Succeed())
+		fmt.Fprint(os.Stdout, strings.Repeat("a", 100))
+		fmt.Fprint(os.Stdout, strings.Repeat("b", 100))
+		output, err := interceptor.StopInterceptingAndReturnOutput()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(output).Should(Equal(strings.Repeat("a", 50) + "\n...[100 bytes of output truncated]...\n" + strings.Repeat("b", 50)))
+
+		Ω(interceptor.StartInterceptingOutput()).Should(Succeed())
+		fmt.Fprint(os.Stdout, strings.Repeat("c", 100))
+		output, err = interceptor.StopInterceptingAndReturnOutput()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(output).Should(Equal(strings.Repeat("c", 100)))
+	})
+
+	It("should save the full output when the retention policy calls for it", func() {
+		outputDir, err := ioutil.TempDir("", "ginkgo-output")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(outputDir)
+		interceptor := NewOutputInterceptorWithPolicy(writer.RetentionPolicy{MaxBytes: 100, OutputDir: outputDir})
+
+		Ω(interceptor.StartInterceptingOutput()).Should(Succeed())
+		fmt.Fprint(os.Stdout, strings.Repeat("a", 200))
+		output, err := interceptor.StopInterceptingAndRetainOutput("spec")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(output).Should(HavePrefix(strings.Repeat("a", 50) + "\n...[100 bytes of output truncated]...\n" + strings.Repeat("a", 50)))
+
+		files, err := ioutil.ReadDir(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(files).Should(HaveLen(1))
+		path := filepath.Join(outputDir, files[0].Name())
+		Ω(output).Should(HaveSuffix("\n...[full output saved to " + path + "]...\n"))
+		Ω(ioutil.ReadFile(path)).Should(Equal([]byte(strings.Repeat("a", 200))))
+	})
+
+	It("should stream the output as it arrives", func() {
+		streamFile, err := ioutil.TempFile("", "ginkgo-stream")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.Remove(streamFile.Name())
+		defer streamFile.Close()
+
+		interceptor := NewOutputInterceptor()
+		interceptor.StreamTo(streamFile)
+		Ω(interceptor.StartInterceptingOutput()).Should(Succeed())
+		fmt.Fprint(os.Stdout, "streamed")
+		Eventually(func() string {
+			content, _ := ioutil.ReadFile(streamFile.Name())
+			return string(content)
+		}).Should(Equal("streamed"))
+
+		output, err := interceptor.StopInterceptingAndReturnOutput()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(output).Should(Equal("streamed"))
+	})
+
+	Context("when a subprocess holds on to stdout after the interceptor stops", func() {
+		var originalGracePeriod time.Duration
+
+		BeforeEach(func() {
+			originalGracePeriod = ChildProcessGracePeriod
+			ChildProcessGracePeriod = 100 * time.Millisecond
+		})
+
+		AfterEach(func() {
+			ChildProcessGracePeriod = originalGracePeriod
+		})
+
+		It("should only wait for the subprocess for the grace period", func() {
+			streamFile, err := ioutil.TempFile("", "ginkgo-stream")
+			Ω(err).ShouldNot(HaveOccurred())
+			defer os.Remove(streamFile.Name())
+			defer streamFile.Close()
+
+			interceptor := NewOutputInterceptor()
+			interceptor.StreamTo(streamFile)
+			Ω(interceptor.StartInterceptingOutput()).Should(Succeed())
+
+			cmd := exec.Command("sh", "-c", "echo from the subprocess; exec sleep 5")
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			Ω(cmd.Start()).Should(Succeed())
+			defer cmd.Process.Kill()
+
+			Eventually(func() string {
+				content, _ := ioutil.ReadFile(streamFile.Name())
+				return string(content)
+			}).Should(Equal("from the subprocess\n"))
+
+			start := time.Now()
+			output, err := interceptor.StopInterceptingAndReturnOutput()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(time.Since(start)).Should(BeNumerically("<", time.Second))
+			Ω(output).Should(Equal("from the subprocess\n"))
+		})
+	})
+})
@@ -4,79 +4,168 @@ package remote
 
 import (
 	"errors"
-	"io/ioutil"
+	"io"
 	"os"
+	"sync"
+	"time"
 
-	"github.com/nxadm/tail"
+	"github.com/hackrish007/ginkgo/internal/writer"
 	"golang.org/x/sys/unix"
 )
 
+//OutputInterceptionSupported is true where OutputInterceptors actually intercept stdout and stderr
+const OutputInterceptionSupported = true
+
+//NewOutputInterceptor returns an interceptor that keeps all of the output it intercepts
 func NewOutputInterceptor() OutputInterceptor {
-	return &outputInterceptor{}
+	return NewOutputInterceptorWithPolicy(writer.RetentionPolicy{})
 }
 
+//NewOutputInterceptorWithPolicy returns an interceptor that keeps no more of the output it intercepts than the retention policy retains.
+//Policies that save the full output to an OutputDir have it spooled to a file there, rather than kept in memory.
+func NewOutputInterceptorWithPolicy(policy writer.RetentionPolicy) OutputInterceptor {
+	return &outputInterceptor{
+		policy: policy,
+		lock:   &sync.Mutex{},
+	}
+}
+
+/*
+outputInterceptor points stdout and stderr at a pipe while it is intercepting.  A goroutine drains the pipe into memory as output arrives,
+copying it to the stream target, if any, along the way.
+*/
 type outputInterceptor struct {
-	redirectFile *os.File
-	streamTarget *os.File
+	policy       writer.RetentionPolicy
+	streamTarget io.Writer
 	intercepting bool
-	tailer       *tail.Tail
-	doneTailing  chan bool
+
+	pipeReader   *os.File
+	buffer       *writer.RetainingBuffer
+	doneDraining chan struct{}
+	lock         *sync.Mutex
+
+	//the original stdout and stderr, restored when we stop intercepting
+	stdoutClone int
+	stderrClone int
 }
 
 func (interceptor *outputInterceptor) StartInterceptingOutput() error {
 	if interceptor.intercepting {
 		return errors.New("Already intercepting output!")
 	}
-	interceptor.intercepting = true
 
-	var err error
+	pipeReader, pipeWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
 
-	interceptor.redirectFile, err = ioutil.TempFile("", "ginkgo-output")
+	interceptor.stdoutClone, err = dupCloseOnExec(1)
 	if err != nil {
+		pipeReader.Close()
+		pipeWriter.Close()
+		return err
+	}
+	interceptor.stderrClone, err = dupCloseOnExec(2)
+	if err != nil {
+		unix.Close(interceptor.stdoutClone)
+		pipeReader.Close()
+		pipeWriter.Close()
 		return err
 	}
 
 	// This might call Dup3 if the dup2 syscall is not available, e.g. on
 	// linux/arm64 or linux/riscv64
-	unix.Dup2(int(interceptor.redirectFile.Fd()), 1)
-	unix.Dup2(int(interceptor.redirectFile.Fd()), 2)
+	unix.Dup2(int(pipeWriter.Fd()), 1)
+	unix.Dup2(int(pipeWriter.Fd()), 2)
 
-	if interceptor.streamTarget != nil {
-		interceptor.tailer, _ = tail.TailFile(interceptor.redirectFile.Name(), tail.Config{Follow: true})
-		interceptor.doneTailing = make(chan bool)
+	//stdout and stderr now hold the write end of the pipe, so the pipe reaches EOF once they are restored
+	//and any subprocesses that inherited them have exited
+	pipeWriter.Close()
 
-		go func() {
-			for line := range interceptor.tailer.Lines {
-				interceptor.streamTarget.Write([]byte(line.Text + "\n"))
-			}
-			close(interceptor.doneTailing)
-		}()
-	}
+	interceptor.intercepting = true
+	interceptor.pipeReader = pipeReader
+	interceptor.buffer = interceptor.policy.NewBuffer()
+	interceptor.doneDraining = make(chan struct{})
+	go interceptor.drain(pipeReader, interceptor.buffer, interceptor.doneDraining)
 
 	return nil
 }
 
+//dupCloseOnExec keeps subprocesses started while intercepting from inheriting the original stdout and stderr
+func dupCloseOnExec(fd int) (int, error) {
+	return unix.FcntlInt(uintptr(fd), unix.F_DUPFD_CLOEXEC, 0)
+}
+
+func (interceptor *outputInterceptor) drain(pipeReader *os.File, buffer *writer.RetainingBuffer, doneDraining chan struct{}) {
+	defer close(doneDraining)
+	chunk := make([]byte, 4096)
+	for {
+		n, err := pipeReader.Read(chunk)
+		if n > 0 {
+			interceptor.lock.Lock()
+			buffer.Write(chunk[:n])
+			if interceptor.streamTarget != nil {
+				interceptor.streamTarget.Write(chunk[:n])
+			}
+			interceptor.lock.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
 func (interceptor *outputInterceptor) StopInterceptingAndReturnOutput() (string, error) {
-	if !interceptor.intercepting {
-		return "", errors.New("Not intercepting output!")
+	err := interceptor.stopIntercepting()
+	if err != nil {
+		return "", err
 	}
 
-	interceptor.redirectFile.Close()
-	output, err := ioutil.ReadFile(interceptor.redirectFile.Name())
-	os.Remove(interceptor.redirectFile.Name())
+	defer interceptor.buffer.Reset()
+	return string(interceptor.buffer.Bytes()), nil
+}
 
-	interceptor.intercepting = false
+func (interceptor *outputInterceptor) StopInterceptingAndRetainOutput(name string) (string, error) {
+	err := interceptor.stopIntercepting()
+	if err != nil {
+		return "", err
+	}
+
+	defer interceptor.buffer.Reset()
+	return string(interceptor.buffer.Retain(name)), nil
+}
 
-	if interceptor.streamTarget != nil {
-		interceptor.tailer.Stop()
-		interceptor.tailer.Cleanup()
-		<-interceptor.doneTailing
-		interceptor.streamTarget.Sync()
+//stopIntercepting restores stdout and stderr and waits for the rest of the intercepted output to be drained into the buffer
+func (interceptor *outputInterceptor) stopIntercepting() error {
+	if !interceptor.intercepting {
+		return errors.New("Not intercepting output!")
 	}
 
-	return string(output), err
+	unix.Dup2(interceptor.stdoutClone, 1)
+	unix.Dup2(interceptor.stderrClone, 2)
+	unix.Close(interceptor.stdoutClone)
+	unix.Close(interceptor.stderrClone)
+
+	//subprocesses that inherited stdout and stderr keep the pipe open; don't wait on them forever
+	select {
+	case <-interceptor.doneDraining:
+	case <-time.After(ChildProcessGracePeriod):
+		interceptor.pipeReader.Close()
+		<-interceptor.doneDraining
+	}
+	interceptor.pipeReader.Close()
+	interceptor.intercepting = false
+
+	interceptor.lock.Lock()
+	defer interceptor.lock.Unlock()
+	if syncer, ok := interceptor.streamTarget.(interface{ Sync() error }); ok {
+		syncer.Sync()
+	}
+	return nil
 }
 
-func (interceptor *outputInterceptor) StreamTo(out *os.File) {
+func (interceptor *outputInterceptor) StreamTo(out io.Writer) {
+	interceptor.lock.Lock()
+	defer interceptor.lock.Unlock()
 	interceptor.streamTarget = out
 }
@@ -4,13 +4,22 @@ package remote
 
 import (
 	"errors"
-	"os"
+	"io"
+
+	"github.com/hackrish007/ginkgo/internal/writer"
 )
 
+//OutputInterceptionSupported is false on Windows: the OutputInterceptors returned here leave stdout and stderr alone and intercept nothing
+const OutputInterceptionSupported = false
+
 func NewOutputInterceptor() OutputInterceptor {
 	return &outputInterceptor{}
 }
 
+func NewOutputInterceptorWithPolicy(policy writer.RetentionPolicy) OutputInterceptor {
+	return &outputInterceptor{}
+}
+
 type outputInterceptor struct {
 	intercepting bool
 }
@@ -33,4 +42,9 @@ func (interceptor *outputInterceptor) StopInterceptingAndReturnOutput() (string,
 	return "", nil
 }
 
-func (interceptor *outputInterceptor) StreamTo(*os.File) {}
+func (interceptor *outputInterceptor) StopInterceptingAndRetainOutput(name string) (string, error) {
+	return interceptor.StopInterceptingAndReturnOutput()
+}
+
+func (interceptor *outputInterceptor) StreamTo(io.Writer) {}
+
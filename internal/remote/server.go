@@ -12,9 +12,14 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/hackrish007/ginkgo/internal/spec_iterator"
+	"github.com/hackrish007/ginkgo/internal/transport"
 
 	"github.com/hackrish007/ginkgo/config"
 	"github.com/hackrish007/ginkgo/reporters"
@@ -22,17 +27,29 @@ import (
 )
 
 /*
-Server spins up on an automatically selected port and listens for communication from the forwarding reporter.
+Server spins up on an automatically selected port, or on a Unix domain socket, and listens for communication from the forwarding reporter.
 It then forwards that communication to attached reporters.
 */
 type Server struct {
 	listener        net.Listener
+	socketDir       string
 	reporters       []reporters.Reporter
 	alives          []func() bool
 	lock            *sync.Mutex
 	beforeSuiteData types.RemoteBeforeSuiteData
 	parallelTotal   int
 	counter         int
+	specDurations   SpecDurations
+	schedule        []int
+	estimates       []time.Duration
+	sharedState     *SharedState
+	outputMaxBytes  int
+
+	registeredSpecs   []spec_iterator.ScheduledSpec
+	requeued          []int
+	nodes             map[int]*nodeState
+	suiteBeginning    []byte
+	missingBeginnings int
 }
 
 //Create a new server, automatically selecting a port
@@ -41,13 +58,37 @@ func NewServer(parallelTotal int) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newServer(listener, parallelTotal), nil
+}
+
+//Create a new server listening on a Unix domain socket in a private temporary directory.
+//This works where loopback TCP is restricted and keeps other local users from reaching the server.
+//Where Unix domain sockets are unavailable the server falls back to TCP, as with NewServer.
+func NewUnixSocketServer(parallelTotal int) (*Server, error) {
+	dir, err := ioutil.TempDir("", "ginkgo")
+	if err != nil {
+		return NewServer(parallelTotal)
+	}
+	listener, err := net.Listen("unix", filepath.Join(dir, "ginkgo.sock"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return NewServer(parallelTotal)
+	}
+	server := newServer(listener, parallelTotal)
+	server.socketDir = dir
+	return server, nil
+}
+
+func newServer(listener net.Listener, parallelTotal int) *Server {
 	return &Server{
 		listener:        listener,
 		lock:            &sync.Mutex{},
 		alives:          make([]func() bool, parallelTotal),
 		beforeSuiteData: types.RemoteBeforeSuiteData{Data: nil, State: types.RemoteBeforeSuiteStatePending},
 		parallelTotal:   parallelTotal,
-	}, nil
+		sharedState:     NewSharedState(),
+		nodes:           map[int]*nodeState{},
+	}
 }
 
 //Start the server.  You don't need to `go s.Start()`, just `s.Start()`
@@ -63,24 +104,38 @@ func (server *Server) Start() {
 	mux.HandleFunc("/SpecWillRun", server.specWillRun)
 	mux.HandleFunc("/SpecDidComplete", server.specDidComplete)
 	mux.HandleFunc("/SpecSuiteDidEnd", server.specSuiteDidEnd)
+	mux.HandleFunc("/SpecOutput", server.specOutput)
 
 	//synchronization endpoints
 	mux.HandleFunc("/BeforeSuiteState", server.handleBeforeSuiteState)
 	mux.HandleFunc("/RemoteAfterSuiteData", server.handleRemoteAfterSuiteData)
 	mux.HandleFunc("/counter", server.handleCounter)
+	mux.HandleFunc("/batch", server.handleBatch)
+	mux.HandleFunc("/schedule", server.handleSchedule)
 	mux.HandleFunc("/has-counter", server.handleHasCounter) //for backward compatibility
 
+	//shared state endpoints
+	mux.HandleFunc("/shared/value", server.handleSharedValue)
+	mux.HandleFunc("/shared/compare-and-swap", server.handleSharedCompareAndSwap)
+	mux.HandleFunc("/shared/acquire", server.handleSharedAcquire)
+	mux.HandleFunc("/shared/release", server.handleSharedRelease)
+
 	go httpServer.Serve(server.listener)
 }
 
-//Stop the server
+//Stop the server and clean up the resources it handed out
 func (server *Server) Close() {
 	server.listener.Close()
+	server.sharedState.Cleanup()
+	if server.socketDir != "" {
+		os.RemoveAll(server.socketDir)
+	}
 }
 
-//The address the server can be reached it.  Pass this into the `ForwardingReporter`.
+//The address the server can be reached it: http://<host>:<port>, or unix://<path> for servers listening on a Unix domain socket.
+//Pass this into the `ForwardingReporter`, via transport.Client.
 func (server *Server) Address() string {
-	return "http://" + server.listener.Addr().String()
+	return transport.Address(server.listener)
 }
 
 //
@@ -108,8 +163,11 @@ func (server *Server) specSuiteWillBegin(writer http.ResponseWriter, request *ht
 
 	json.Unmarshal(body, &data)
 
-	for _, reporter := range server.reporters {
-		reporter.SpecSuiteWillBegin(data.Config, data.Summary)
+	repetitions := server.registerSuiteBeginning(data.Config.ParallelNode, body)
+	for i := 0; i < repetitions; i++ {
+		for _, reporter := range server.reporters {
+			reporter.SpecSuiteWillBegin(data.Config, data.Summary)
+		}
 	}
 }
 
@@ -137,6 +195,7 @@ func (server *Server) specWillRun(writer http.ResponseWriter, request *http.Requ
 	body := server.readAll(request)
 	var specSummary *types.SpecSummary
 	json.Unmarshal(body, &specSummary)
+	server.registerSpecWillRun(specSummary)
 
 	for _, reporter := range server.reporters {
 		reporter.SpecWillRun(specSummary)
@@ -147,16 +206,35 @@ func (server *Server) specDidComplete(writer http.ResponseWriter, request *http.
 	body := server.readAll(request)
 	var specSummary *types.SpecSummary
 	json.Unmarshal(body, &specSummary)
+	server.registerSpecDidComplete(specSummary)
 
 	for _, reporter := range server.reporters {
 		reporter.SpecDidComplete(specSummary)
 	}
 }
 
+func (server *Server) specOutput(writer http.ResponseWriter, request *http.Request) {
+	body := server.readAll(request)
+	var chunk *types.SpecOutputChunk
+	json.Unmarshal(body, &chunk)
+	if chunk != nil && chunk.Intercepted {
+		server.registerInterceptedOutput(chunk)
+		return
+	}
+
+	for _, reporter := range server.reporters {
+		if liveOutputReporter, ok := reporter.(LiveOutputReporter); ok {
+			liveOutputReporter.SpecOutputDidArrive(chunk)
+		}
+	}
+}
+
 func (server *Server) specSuiteDidEnd(writer http.ResponseWriter, request *http.Request) {
 	body := server.readAll(request)
 	var suiteSummary *types.SuiteSummary
 	json.Unmarshal(body, &suiteSummary)
+	node, _ := strconv.Atoi(request.URL.Query().Get("node"))
+	server.registerSuiteEnding(node, suiteSummary)
 
 	for _, reporter := range server.reporters {
 		reporter.SpecSuiteDidEnd(suiteSummary)
@@ -209,16 +287,235 @@ func (server *Server) handleRemoteAfterSuiteData(writer http.ResponseWriter, req
 	enc.Encode(afterSuiteData)
 }
 
+//ScheduleByDuration makes the server hand out specs longest-first, using the passed-in durations to estimate how long each spec will take.
+//Nodes must register their specs with the server (see spec_iterator.ParallelIterator) for this to take effect.
+func (server *Server) ScheduleByDuration(durations SpecDurations) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	server.specDurations = durations
+}
+
+//SetOutputMaxBytes bounds how much of each node's intercepted output the server holds on to, in case the node crashes, as -outputMaxBytes bounds the nodes' own buffers.
+//Zero, the default, means no limit.  It must be called before the nodes start.
+func (server *Server) SetOutputMaxBytes(maxBytes int) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	server.outputMaxBytes = maxBytes
+}
+
+func (server *Server) handleSchedule(writer http.ResponseWriter, request *http.Request) {
+	var specs []spec_iterator.ScheduledSpec
+	err := json.NewDecoder(request.Body).Decode(&specs)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	//all nodes register the same specs in the same order, so the first registration wins
+	if server.registeredSpecs == nil {
+		server.registeredSpecs = specs
+	}
+	if server.specDurations != nil && server.schedule == nil {
+		server.schedule = server.specDurations.LongestFirst(specs)
+		server.estimates = server.specDurations.Estimates(specs)
+	}
+}
+
 func (server *Server) handleCounter(writer http.ResponseWriter, request *http.Request) {
 	c := spec_iterator.Counter{}
 	server.lock.Lock()
 	c.Index = server.counter
+	if c.Index < len(server.schedule) {
+		c.Index = server.schedule[c.Index]
+	}
 	server.counter++
+	if node, err := strconv.Atoi(request.URL.Query().Get("node")); err == nil {
+		server.node(node).leased = []int{c.Index}
+	}
 	server.lock.Unlock()
 
 	json.NewEncoder(writer).Encode(c)
 }
 
+func (server *Server) handleBatch(writer http.ResponseWriter, request *http.Request) {
+	server.lock.Lock()
+	batch := spec_iterator.Batch{Indices: server.nextBatch()}
+	if node, err := strconv.Atoi(request.URL.Query().Get("node")); err == nil {
+		server.node(node).leased = append([]int{}, batch.Indices...)
+	}
+	server.lock.Unlock()
+
+	json.NewEncoder(writer).Encode(batch)
+}
+
+/*
+nextBatch hands out specs in batches that shrink as the run progresses: large batches early on save round-trips, while small batches towards the end keep the nodes evenly loaded.
+Each batch holds about 1/(2N) of the remaining specs, where N is the number of nodes.  When scheduling by duration each batch instead holds about 1/(2N) of the remaining
+estimated run time, so the longest specs, which are handed out first, are leased one at a time rather than piling up on the first nodes to ask.
+Batches are consecutive runs of the dispatch order (the suite's order, or longest-first when scheduling by duration) and nodes run them in order, so specs start in the same order as they would one at a time.
+Specs left over by crashed nodes are handed out first.
+
+Until the nodes register their specs the server doesn't know how many there are and hands out one index at a time, as /counter does.
+Without reporters (as when the CLI runs with -stream), the server can't tell how far a node got through its batch should it crash, so it also hands out one spec at a time.
+
+nextBatch must be called with the server's lock held.
+*/
+func (server *Server) nextBatch() []int {
+	total := len(server.registeredSpecs)
+	if total == 0 || len(server.reporters) == 0 {
+		index := server.counter
+		if index < len(server.schedule) {
+			index = server.schedule[index]
+		}
+		server.counter++
+		return []int{index}
+	}
+
+	remaining := total - server.counter
+	if remaining < 0 {
+		remaining = 0
+	}
+	size := (remaining + len(server.requeued)) / (2 * server.parallelTotal)
+	budget := server.remainingEstimate() / time.Duration(2*server.parallelTotal)
+
+	indices := []int{}
+	leased := time.Duration(0)
+	for {
+		index, ok := server.nextSpec()
+		if !ok {
+			break
+		}
+		if len(indices) > 0 {
+			if server.estimates == nil && len(indices) >= size {
+				break
+			}
+			if server.estimates != nil && leased+server.estimates[index] > budget {
+				break
+			}
+		}
+
+		if len(server.requeued) > 0 {
+			server.requeued = server.requeued[1:]
+		} else {
+			server.counter++
+		}
+		indices = append(indices, index)
+		if server.estimates != nil {
+			leased += server.estimates[index]
+		}
+	}
+	return indices
+}
+
+//nextSpec returns the next spec to hand out, without handing it out.  It must be called with the server's lock held.
+func (server *Server) nextSpec() (int, bool) {
+	if len(server.requeued) > 0 {
+		return server.requeued[0], true
+	}
+	if server.counter >= len(server.registeredSpecs) {
+		return 0, false
+	}
+	index := server.counter
+	if index < len(server.schedule) {
+		index = server.schedule[index]
+	}
+	return index, true
+}
+
+//remainingEstimate returns the estimated run time of the specs yet to be handed out, or zero when not scheduling by duration.
+//It must be called with the server's lock held.
+func (server *Server) remainingEstimate() time.Duration {
+	if server.estimates == nil {
+		return 0
+	}
+	remaining := time.Duration(0)
+	for _, index := range server.requeued {
+		remaining += server.estimates[index]
+	}
+	for i := server.counter; i < len(server.schedule); i++ {
+		remaining += server.estimates[server.schedule[i]]
+	}
+	return remaining
+}
+
 func (server *Server) handleHasCounter(writer http.ResponseWriter, request *http.Request) {
 	writer.Write([]byte(""))
 }
+
+//
+// Shared State Endpoints
+//
+
+//MaxSharedValueWait caps how long a single GET of /shared/value?key=<key>&wait=<duration> waits for the key to be set.
+//Clients waiting for longer repeat the request.
+const MaxSharedValueWait = 30 * time.Second
+
+func (server *Server) handleSharedValue(writer http.ResponseWriter, request *http.Request) {
+	if request.Method == "POST" {
+		var value SharedValue
+		err := json.NewDecoder(request.Body).Decode(&value)
+		if err != nil {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		server.sharedState.Set(value.Key, value.Value)
+		return
+	}
+
+	key := request.URL.Query().Get("key")
+	value, ok := server.sharedState.Get(key)
+	if wait, err := time.ParseDuration(request.URL.Query().Get("wait")); !ok && err == nil {
+		if wait > MaxSharedValueWait {
+			wait = MaxSharedValueWait
+		}
+		value, ok = server.sharedState.Wait(key, wait)
+	}
+	if !ok {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(writer).Encode(SharedValue{Key: key, Value: value})
+}
+
+func (server *Server) handleSharedCompareAndSwap(writer http.ResponseWriter, request *http.Request) {
+	var cas SharedCompareAndSwap
+	err := json.NewDecoder(request.Body).Decode(&cas)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	cas.Swapped = server.sharedState.CompareAndSwap(cas.Key, cas.Old, cas.New)
+	json.NewEncoder(writer).Encode(cas)
+}
+
+func (server *Server) handleSharedAcquire(writer http.ResponseWriter, request *http.Request) {
+	var lease SharedResourceLease
+	err := json.NewDecoder(request.Body).Decode(&lease)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	lease.Resource, err = server.sharedState.Acquire(lease.Node, lease.Pool, lease.Kind)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusConflict)
+		return
+	}
+	json.NewEncoder(writer).Encode(lease)
+}
+
+func (server *Server) handleSharedRelease(writer http.ResponseWriter, request *http.Request) {
+	var lease SharedResourceLease
+	err := json.NewDecoder(request.Body).Decode(&lease)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = server.sharedState.Release(lease.Node, lease.Pool, lease.Resource)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusConflict)
+	}
+}
@@ -6,12 +6,19 @@ import (
 	. "github.com/hackrish007/gomega"
 
 	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/internal/spec_iterator"
+	"github.com/hackrish007/ginkgo/internal/transport"
 	"github.com/hackrish007/ginkgo/reporters"
 	"github.com/hackrish007/ginkgo/types"
 
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 var _ = Describe("Server", func() {
@@ -31,6 +38,31 @@ var _ = Describe("Server", func() {
 		server.Close()
 	})
 
+	Describe("listening on a Unix domain socket", func() {
+		It("should be reachable through its unix:// address, and remove its socket when closed", func() {
+			unixServer, err := NewUnixSocketServer(3)
+			Ω(err).ShouldNot(HaveOccurred())
+			unixServer.Start()
+			defer unixServer.Close()
+
+			address := unixServer.Address()
+			if !strings.HasPrefix(address, "unix://") {
+				Skip("Unix domain sockets are unavailable")
+			}
+
+			client, baseURL := transport.Client(address)
+			resp, err := client.Get(baseURL + "/counter")
+			Ω(err).ShouldNot(HaveOccurred())
+			c := spec_iterator.Counter{}
+			Ω(json.NewDecoder(resp.Body).Decode(&c)).Should(Succeed())
+			Ω(c.Index).Should(Equal(0))
+
+			unixServer.Close()
+			_, err = os.Stat(filepath.Dir(strings.TrimPrefix(address, "unix://")))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+	})
+
 	Describe("Streaming endpoints", func() {
 		var (
 			reporterA, reporterB *reporters.FakeReporter
@@ -112,6 +144,33 @@ var _ = Describe("Server", func() {
 			})
 		})
 
+		Describe("/SpecOutput", func() {
+			It("should decode and forward the chunk to reporters that show live output", func() {
+				liveReporter := &fakeLiveOutputReporter{FakeReporter: reporters.NewFakeReporter()}
+				server.RegisterReporters(reporterA, liveReporter)
+
+				chunk := &types.SpecOutputChunk{ParallelNode: 2, SpecID: "A", ComponentTexts: []string{"[Top Level]", "A"}, Output: "a\n"}
+				encoded, _ := json.Marshal(chunk)
+				resp, err := http.Post(server.Address()+"/SpecOutput", "application/json", bytes.NewReader(encoded))
+				Ω(err).ShouldNot(HaveOccurred())
+				resp.Body.Close()
+
+				Ω(liveReporter.chunks).Should(Equal([]*types.SpecOutputChunk{chunk}))
+			})
+
+			It("should not forward intercepted output", func() {
+				liveReporter := &fakeLiveOutputReporter{FakeReporter: reporters.NewFakeReporter()}
+				server.RegisterReporters(reporterA, liveReporter)
+
+				encoded, _ := json.Marshal(&types.SpecOutputChunk{ParallelNode: 2, Output: "a\n", Intercepted: true})
+				resp, err := http.Post(server.Address()+"/SpecOutput", "application/json", bytes.NewReader(encoded))
+				Ω(err).ShouldNot(HaveOccurred())
+				resp.Body.Close()
+
+				Ω(liveReporter.chunks).Should(BeEmpty())
+			})
+		})
+
 		Describe("/SpecSuiteDidEnd", func() {
 			It("should decode and forward the suite summary", func(done Done) {
 				forwardingReporter.SpecSuiteDidEnd(suiteSummary)
@@ -265,5 +324,424 @@ var _ = Describe("Server", func() {
 
 			})
 		})
+
+		Describe("GETting the counter", func() {
+			getCounter := func() int {
+				resp, err := http.Get(server.Address() + "/counter")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(resp.StatusCode).Should(Equal(http.StatusOK))
+
+				c := spec_iterator.Counter{}
+				err = json.NewDecoder(resp.Body).Decode(&c)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				return c.Index
+			}
+
+			postSchedule := func(specs []spec_iterator.ScheduledSpec) {
+				encoded, _ := json.Marshal(specs)
+				resp, err := http.Post(server.Address()+"/schedule", "application/json", bytes.NewReader(encoded))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(resp.StatusCode).Should(Equal(http.StatusOK))
+			}
+
+			specs := []spec_iterator.ScheduledSpec{
+				{Name: "A", WillRun: true},
+				{Name: "B", WillRun: false},
+				{Name: "C", WillRun: true},
+				{Name: "D", WillRun: true},
+			}
+
+			It("should count up", func() {
+				Ω(getCounter()).Should(Equal(0))
+				Ω(getCounter()).Should(Equal(1))
+				Ω(getCounter()).Should(Equal(2))
+			})
+
+			Context("when scheduling by duration", func() {
+				BeforeEach(func() {
+					server.ScheduleByDuration(SpecDurations{
+						"A": time.Second,
+						"C": 3 * time.Second,
+						"D": 2 * time.Second,
+					})
+				})
+
+				It("should hand out the registered specs longest-first and then keep counting", func() {
+					postSchedule(specs)
+					postSchedule([]spec_iterator.ScheduledSpec{{Name: "A", WillRun: true}})
+
+					Ω(getCounter()).Should(Equal(2))
+					Ω(getCounter()).Should(Equal(3))
+					Ω(getCounter()).Should(Equal(0))
+					Ω(getCounter()).Should(Equal(1))
+					Ω(getCounter()).Should(Equal(4))
+				})
+
+				It("should count up when no specs have been registered", func() {
+					Ω(getCounter()).Should(Equal(0))
+					Ω(getCounter()).Should(Equal(1))
+				})
+			})
+
+			Context("when not scheduling by duration", func() {
+				It("should ignore registered specs", func() {
+					postSchedule(specs)
+
+					Ω(getCounter()).Should(Equal(0))
+					Ω(getCounter()).Should(Equal(1))
+				})
+			})
+		})
+
+		Describe("GETting batches", func() {
+			getBatch := func(node int) []int {
+				resp, err := http.Get(fmt.Sprintf("%s/batch?node=%d", server.Address(), node))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(resp.StatusCode).Should(Equal(http.StatusOK))
+
+				batch := spec_iterator.Batch{}
+				err = json.NewDecoder(resp.Body).Decode(&batch)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				return batch.Indices
+			}
+
+			postSchedule := func(n int) {
+				specs := make([]spec_iterator.ScheduledSpec, n)
+				for i := range specs {
+					specs[i] = spec_iterator.ScheduledSpec{ID: fmt.Sprintf("%d", i), Name: fmt.Sprintf("[Top Level] %d", i), WillRun: true}
+				}
+				encoded, _ := json.Marshal(specs)
+				resp, err := http.Post(server.Address()+"/schedule", "application/json", bytes.NewReader(encoded))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(resp.StatusCode).Should(Equal(http.StatusOK))
+			}
+
+			Context("when reporters are registered", func() {
+				BeforeEach(func() {
+					server.RegisterReporters(reporters.NewFakeReporter())
+				})
+
+				It("should hand out consecutive batches that shrink as the run progresses, and then empty batches", func() {
+					postSchedule(24)
+
+					Ω(getBatch(1)).Should(Equal([]int{0, 1, 2, 3}))
+					Ω(getBatch(2)).Should(Equal([]int{4, 5, 6}))
+					Ω(getBatch(3)).Should(Equal([]int{7, 8}))
+
+					handedOut := 9
+					for {
+						batch := getBatch(1)
+						if len(batch) == 0 {
+							break
+						}
+						Ω(batch[0]).Should(Equal(handedOut))
+						Ω(len(batch)).Should(BeNumerically("<=", 2))
+						handedOut += len(batch)
+					}
+					Ω(handedOut).Should(Equal(24))
+				})
+
+				It("should hand out one index at a time until the specs have been registered", func() {
+					Ω(getBatch(1)).Should(Equal([]int{0}))
+					Ω(getBatch(2)).Should(Equal([]int{1}))
+				})
+
+				It("should hand out the specs longest-first when scheduling by duration", func() {
+					server.ScheduleByDuration(SpecDurations{
+						"0": time.Millisecond,
+						"3": 2 * time.Second,
+						"5": 3 * time.Second,
+					})
+					postSchedule(12)
+
+					Ω(getBatch(1)).Should(Equal([]int{5}))
+					Ω(getBatch(1)).Should(Equal([]int{3}))
+					Ω(getBatch(1)).Should(Equal([]int{1}))
+				})
+
+				It("should size batches by estimated duration when scheduling by duration", func() {
+					durations := SpecDurations{"0": 10 * time.Second}
+					for i := 1; i < 24; i++ {
+						durations[fmt.Sprintf("%d", i)] = 100 * time.Millisecond
+					}
+					server.ScheduleByDuration(durations)
+					postSchedule(24)
+
+					Ω(getBatch(1)).Should(Equal([]int{0}))
+					Ω(getBatch(2)).Should(Equal([]int{1, 2, 3}))
+					Ω(getBatch(3)).Should(Equal([]int{4, 5, 6}))
+					Ω(getBatch(1)).Should(Equal([]int{7, 8}))
+				})
+			})
+
+			Context("when no reporters are registered", func() {
+				It("should hand out one spec at a time", func() {
+					postSchedule(24)
+
+					Ω(getBatch(1)).Should(Equal([]int{0}))
+					Ω(getBatch(2)).Should(Equal([]int{1}))
+				})
+			})
+		})
+
+		Describe("the shared state endpoints", func() {
+			postJSON := func(path string, request interface{}, response interface{}) int {
+				encoded, _ := json.Marshal(request)
+				resp, err := http.Post(server.Address()+path, "application/json", bytes.NewReader(encoded))
+				Ω(err).ShouldNot(HaveOccurred())
+				defer resp.Body.Close()
+				if response != nil && resp.StatusCode == http.StatusOK {
+					Ω(json.NewDecoder(resp.Body).Decode(response)).Should(Succeed())
+				}
+				return resp.StatusCode
+			}
+
+			acquire := func(node int) string {
+				lease := SharedResourceLease{Node: node, Pool: "db", Kind: ResourceKindName}
+				Ω(postJSON("/shared/acquire", lease, &lease)).Should(Equal(http.StatusOK))
+				return lease.Resource
+			}
+
+			It("should store, return and swap values", func() {
+				resp, err := http.Get(server.Address() + "/shared/value?key=a")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(resp.StatusCode).Should(Equal(http.StatusNotFound))
+
+				Ω(postJSON("/shared/value", SharedValue{Key: "a", Value: []byte("1")}, nil)).Should(Equal(http.StatusOK))
+
+				value := SharedValue{}
+				resp, err = http.Get(server.Address() + "/shared/value?key=a")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(json.NewDecoder(resp.Body).Decode(&value)).Should(Succeed())
+				Ω(value.Value).Should(Equal([]byte("1")))
+
+				cas := SharedCompareAndSwap{Key: "a", Old: []byte("1"), New: []byte("2")}
+				Ω(postJSON("/shared/compare-and-swap", cas, &cas)).Should(Equal(http.StatusOK))
+				Ω(cas.Swapped).Should(BeTrue())
+			})
+
+			It("should hold requests that wait for a value until one is stored", func() {
+				go func() {
+					defer GinkgoRecover()
+					time.Sleep(50 * time.Millisecond)
+					Ω(postJSON("/shared/value", SharedValue{Key: "a", Value: []byte("1")}, nil)).Should(Equal(http.StatusOK))
+				}()
+
+				value := SharedValue{}
+				resp, err := http.Get(server.Address() + "/shared/value?key=a&wait=1s")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(json.NewDecoder(resp.Body).Decode(&value)).Should(Succeed())
+				Ω(value.Value).Should(Equal([]byte("1")))
+
+				resp, err = http.Get(server.Address() + "/shared/value?key=b&wait=50ms")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(resp.StatusCode).Should(Equal(http.StatusNotFound))
+			})
+
+			It("should lease resources and refuse invalid releases", func() {
+				Ω(acquire(1)).Should(Equal("db_1"))
+				Ω(acquire(2)).Should(Equal("db_2"))
+				Ω(postJSON("/shared/release", SharedResourceLease{Node: 2, Pool: "db", Resource: "db_1"}, nil)).Should(Equal(http.StatusConflict))
+				Ω(postJSON("/shared/release", SharedResourceLease{Node: 1, Pool: "db", Resource: "db_1"}, nil)).Should(Equal(http.StatusOK))
+				Ω(acquire(3)).Should(Equal("db_1"))
+			})
+
+			It("should release the resources of nodes that have exited", func() {
+				Ω(acquire(1)).Should(Equal("db_1"))
+				Ω(acquire(2)).Should(Equal("db_2"))
+				server.NodeExited(1, true)
+				Ω(acquire(2)).Should(Equal("db_1"))
+			})
+		})
+
+		Describe("when nodes exit", func() {
+			var reporter *reporters.FakeReporter
+
+			post := func(path string, data interface{}) {
+				encoded, _ := json.Marshal(data)
+				resp, err := http.Post(server.Address()+path, "application/json", bytes.NewReader(encoded))
+				Ω(err).ShouldNot(HaveOccurred())
+				resp.Body.Close()
+			}
+
+			beginSuite := func(node int) {
+				post("/SpecSuiteWillBegin", map[string]interface{}{
+					"config":        config.GinkgoConfigType{ParallelNode: node},
+					"suite-summary": &types.SuiteSummary{SuiteDescription: "My Test Suite"},
+				})
+			}
+
+			runSpec := func(node int, text string) *types.SpecSummary {
+				summary := &types.SpecSummary{
+					ID:                     text,
+					ComponentTexts:         []string{"[Top Level]", text},
+					ComponentCodeLocations: []types.CodeLocation{{}, {FileName: "foo_test.go", LineNumber: 17}},
+					ParallelNode:           node,
+				}
+				post("/SpecWillRun", summary)
+				return summary
+			}
+
+			completeSpec := func(summary *types.SpecSummary) {
+				summary.State = types.SpecStatePassed
+				post("/SpecDidComplete", summary)
+			}
+
+			BeforeEach(func() {
+				reporter = reporters.NewFakeReporter()
+				server.RegisterReporters(reporter)
+			})
+
+			It("should not report nodes that ended their suite", func() {
+				beginSuite(1)
+				completeSpec(runSpec(1, "A"))
+				post("/SpecSuiteDidEnd?node=1", &types.SuiteSummary{SuiteSucceeded: true})
+
+				crashed, _, _ := server.NodeExited(1, true)
+				Ω(crashed).Should(BeFalse())
+				Ω(reporter.SpecSummaries).Should(HaveLen(1))
+			})
+
+			It("should report the spec a crashed node was running as failed, and end the node's suite", func() {
+				beginSuite(1)
+				completeSpec(runSpec(1, "A"))
+				runSpec(1, "B")
+
+				crashed, description, restart := server.NodeExited(1, false)
+				Ω(crashed).Should(BeTrue())
+				Ω(restart).Should(BeFalse())
+				Ω(description).Should(Equal(`Node 1 exited while running "B"`))
+
+				Ω(reporter.SpecSummaries).Should(HaveLen(2))
+				culprit := reporter.SpecSummaries[1]
+				Ω(culprit.ComponentTexts).Should(Equal([]string{"[Top Level]", "B"}))
+				Ω(culprit.State).Should(Equal(types.SpecStateFailed))
+				Ω(culprit.Failure.Message).Should(Equal("Node 1 exited while running this spec"))
+				Ω(culprit.Failure.Location).Should(Equal(types.CodeLocation{FileName: "foo_test.go", LineNumber: 17}))
+
+				Ω(reporter.EndSummary.SuiteSucceeded).Should(BeFalse())
+				Ω(reporter.EndSummary.NumberOfPassedSpecs).Should(Equal(1))
+				Ω(reporter.EndSummary.NumberOfFailedSpecs).Should(Equal(1))
+			})
+
+			It("should report what a crashed node wrote to stdout and stderr while running the spec", func() {
+				intercept := func(node int, output string) {
+					post("/SpecOutput", &types.SpecOutputChunk{ParallelNode: node, Output: output, Intercepted: true})
+				}
+
+				beginSuite(1)
+				runSpec(1, "A")
+				intercept(1, "output of A\n")
+				completeSpec(runSpec(1, "A"))
+				runSpec(1, "B")
+				intercept(1, "output of B\n")
+				intercept(2, "output of node 2\n")
+				intercept(1, "panic: boom\n")
+
+				server.NodeExited(1, false)
+				Ω(reporter.SpecSummaries).Should(HaveLen(2))
+				Ω(reporter.SpecSummaries[1].CapturedOutput).Should(Equal("output of B\npanic: boom\n"))
+			})
+
+			It("should keep no more of a node's output than -outputMaxBytes", func() {
+				server.SetOutputMaxBytes(100)
+
+				beginSuite(1)
+				runSpec(1, "A")
+				for i := 0; i < 20; i++ {
+					post("/SpecOutput", &types.SpecOutputChunk{ParallelNode: 1, Output: fmt.Sprintf("line %02d\n", i), Intercepted: true})
+				}
+
+				server.NodeExited(1, false)
+				output := reporter.SpecSummaries[0].CapturedOutput
+				Ω(output).Should(HavePrefix("line 00\nline 01\nline 02\nline 03\nline 04\nline 05\nli\n...[60 bytes of output truncated]...\n"))
+				Ω(output).Should(HaveSuffix("line 19\n"))
+			})
+
+			It("should describe crashes outside of specs without reporting them", func() {
+				beginSuite(1)
+
+				crashed, description, restart := server.NodeExited(1, true)
+				Ω(crashed).Should(BeTrue())
+				Ω(restart).Should(BeFalse())
+				Ω(description).Should(Equal("Node 1 exited unexpectedly"))
+				Ω(reporter.AfterSuiteSummary).Should(BeNil())
+				Ω(reporter.SpecSummaries).Should(BeEmpty())
+				Ω(reporter.EndSummary).Should(BeNil())
+			})
+
+			It("should let a replacement node finish a crashed node's suite", func() {
+				beginSuite(1)
+				completeSpec(runSpec(1, "A"))
+				runSpec(1, "B")
+
+				crashed, _, restart := server.NodeExited(1, true)
+				Ω(crashed).Should(BeTrue())
+				Ω(restart).Should(BeTrue())
+				Ω(reporter.EndSummary).Should(BeNil())
+
+				reporter.BeginSummary = nil
+				beginSuite(1)
+				Ω(reporter.BeginSummary).Should(BeNil())
+
+				completeSpec(runSpec(1, "C"))
+				post("/SpecSuiteDidEnd?node=1", &types.SuiteSummary{SuiteSucceeded: true, NumberOfTotalSpecs: 1, NumberOfSpecsThatWillBeRun: 1, NumberOfPassedSpecs: 1})
+
+				Ω(reporter.EndSummary.SuiteSucceeded).Should(BeFalse())
+				Ω(reporter.EndSummary.NumberOfTotalSpecs).Should(Equal(3))
+				Ω(reporter.EndSummary.NumberOfPassedSpecs).Should(Equal(2))
+				Ω(reporter.EndSummary.NumberOfFailedSpecs).Should(Equal(1))
+
+				crashed, _, _ = server.NodeExited(1, true)
+				Ω(crashed).Should(BeFalse())
+			})
+
+			It("should hand the specs a crashed node leased but never ran to the other nodes", func() {
+				specs := make([]spec_iterator.ScheduledSpec, 24)
+				for i := range specs {
+					specs[i] = spec_iterator.ScheduledSpec{ID: fmt.Sprintf("%d", i), Name: fmt.Sprintf("[Top Level] %d", i), WillRun: true}
+				}
+				post("/schedule", specs)
+
+				resp, err := http.Get(server.Address() + "/batch?node=1")
+				Ω(err).ShouldNot(HaveOccurred())
+				batch := spec_iterator.Batch{}
+				Ω(json.NewDecoder(resp.Body).Decode(&batch)).Should(Succeed())
+				Ω(batch.Indices).Should(Equal([]int{0, 1, 2, 3}))
+
+				beginSuite(1)
+				completeSpec(runSpec(1, "0"))
+				runSpec(1, "1")
+
+				_, description, _ := server.NodeExited(1, false)
+				Ω(description).Should(Equal(`Node 1 exited while running "1"`))
+
+				resp, err = http.Get(server.Address() + "/batch?node=2")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(json.NewDecoder(resp.Body).Decode(&batch)).Should(Succeed())
+				Ω(batch.Indices).Should(Equal([]int{2, 3, 4}))
+			})
+
+			It("should name the spec a node pulled if the node did not report running it", func() {
+				post("/schedule", []spec_iterator.ScheduledSpec{{Name: "[Top Level] A", WillRun: true}})
+				resp, err := http.Get(server.Address() + "/counter?node=2")
+				Ω(err).ShouldNot(HaveOccurred())
+				resp.Body.Close()
+
+				_, description, _ := server.NodeExited(2, false)
+				Ω(description).Should(Equal(`Node 2 exited while running "A"`))
+			})
+		})
 	})
 })
+
+type fakeLiveOutputReporter struct {
+	*reporters.FakeReporter
+	chunks []*types.SpecOutputChunk
+}
+
+func (reporter *fakeLiveOutputReporter) SpecOutputDidArrive(chunk *types.SpecOutputChunk) {
+	reporter.chunks = append(reporter.chunks, chunk)
+}
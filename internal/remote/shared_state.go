@@ -0,0 +1,277 @@
+package remote
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//ResourceKind determines how a resource pool creates new resources
+type ResourceKind string
+
+const (
+	//ResourceKindPort pools hand out free TCP ports on 127.0.0.1
+	ResourceKindPort ResourceKind = "port"
+	//ResourceKindName pools hand out unique names of the form <pool>_<n>, e.g. database names
+	ResourceKindName ResourceKind = "name"
+	//ResourceKindTempDir pools hand out empty temporary directories
+	ResourceKindTempDir ResourceKind = "tempdir"
+)
+
+/*
+SharedState holds the key/value store and the resource pools shared by the nodes of a parallel run.
+The server exposes it to the nodes over HTTP.  Serial suites use a SharedState directly.
+
+Resources are leased to a node until the node releases them or exits.  Released resources go back to their pool
+and are handed out again; temporary directories are emptied first.
+*/
+type SharedState struct {
+	lock   *sync.Mutex
+	values map[string][]byte
+	pools  map[string]*resourcePool
+
+	//closed, and replaced, whenever a value is stored
+	stored chan struct{}
+}
+
+type resourcePool struct {
+	kind    ResourceKind
+	created []string
+	free    []string
+	leases  map[string]int
+}
+
+func NewSharedState() *SharedState {
+	return &SharedState{
+		lock:   &sync.Mutex{},
+		values: map[string][]byte{},
+		pools:  map[string]*resourcePool{},
+		stored: make(chan struct{}),
+	}
+}
+
+//Get returns the value stored under key, and whether there is one
+func (state *SharedState) Get(key string) ([]byte, bool) {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	value, ok := state.values[key]
+	return value, ok
+}
+
+//Wait returns the value stored under key, waiting up to timeout for one to be stored.  It returns false if timeout elapses first.
+func (state *SharedState) Wait(key string, timeout time.Duration) ([]byte, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		state.lock.Lock()
+		value, ok := state.values[key]
+		stored := state.stored
+		state.lock.Unlock()
+		if ok {
+			return value, true
+		}
+
+		select {
+		case <-stored:
+		case <-timer.C:
+			return nil, false
+		}
+	}
+}
+
+//Set stores value under key
+func (state *SharedState) Set(key string, value []byte) {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	state.store(key, value)
+}
+
+//CompareAndSwap stores new under key if key currently holds old.  A nil old means that key must not hold any value.
+func (state *SharedState) CompareAndSwap(key string, old []byte, new []byte) bool {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	current, ok := state.values[key]
+	if old == nil {
+		if ok {
+			return false
+		}
+	} else if !ok || string(current) != string(old) {
+		return false
+	}
+	state.store(key, new)
+	return true
+}
+
+//store must be called with the lock held.  It wakes up everyone waiting for a value.
+func (state *SharedState) store(key string, value []byte) {
+	state.values[key] = value
+	close(state.stored)
+	state.stored = make(chan struct{})
+}
+
+//Acquire leases a resource from the named pool to node, creating the pool and the resource as necessary
+func (state *SharedState) Acquire(node int, pool string, kind ResourceKind) (string, error) {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	resources, ok := state.pools[pool]
+	if !ok {
+		resources = &resourcePool{kind: kind, leases: map[string]int{}}
+		state.pools[pool] = resources
+	} else if resources.kind != kind {
+		return "", fmt.Errorf("resource pool %q holds %s resources, not %s resources", pool, resources.kind, kind)
+	}
+
+	var resource string
+	if len(resources.free) > 0 {
+		resource = resources.free[0]
+		resources.free = resources.free[1:]
+	} else {
+		var err error
+		resource, err = resources.create(pool)
+		if err != nil {
+			return "", err
+		}
+		resources.created = append(resources.created, resource)
+	}
+
+	resources.leases[resource] = node
+	return resource, nil
+}
+
+//Release returns a resource leased to node to its pool
+func (state *SharedState) Release(node int, pool string, resource string) error {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	resources, ok := state.pools[pool]
+	if !ok {
+		return fmt.Errorf("unknown resource pool %q", pool)
+	}
+	if owner, ok := resources.leases[resource]; !ok || owner != node {
+		return fmt.Errorf("resource %q is not leased from pool %q by node %d", resource, pool, node)
+	}
+	return resources.release(resource)
+}
+
+//ReleaseNode returns every resource leased to node to its pool.  The server calls this once a node has exited.
+func (state *SharedState) ReleaseNode(node int) {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	for _, resources := range state.pools {
+		leased := []string{}
+		for resource, owner := range resources.leases {
+			if owner == node {
+				leased = append(leased, resource)
+			}
+		}
+		sort.Strings(leased)
+		for _, resource := range leased {
+			resources.release(resource)
+		}
+	}
+}
+
+//Cleanup removes the temporary directories created by the resource pools
+func (state *SharedState) Cleanup() {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	for _, resources := range state.pools {
+		if resources.kind == ResourceKindTempDir {
+			for _, dir := range resources.created {
+				os.RemoveAll(dir)
+			}
+		}
+	}
+	state.pools = map[string]*resourcePool{}
+}
+
+func (resources *resourcePool) create(pool string) (string, error) {
+	switch resources.kind {
+	case ResourceKindPort:
+		return resources.freePort()
+	case ResourceKindName:
+		return fmt.Sprintf("%s_%d", pool, len(resources.created)+1), nil
+	case ResourceKindTempDir:
+		return ioutil.TempDir("", "ginkgo-"+strings.Map(sanitizeDirRune, pool)+"-")
+	default:
+		return "", fmt.Errorf("unknown resource kind %q", resources.kind)
+	}
+}
+
+func (resources *resourcePool) freePort() (string, error) {
+	for {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return "", err
+		}
+		port := fmt.Sprintf("%d", listener.Addr().(*net.TCPAddr).Port)
+		listener.Close()
+
+		//the OS may hand out a port we've already leased if it isn't in use yet
+		if !resources.hasCreated(port) {
+			return port, nil
+		}
+	}
+}
+
+func (resources *resourcePool) hasCreated(resource string) bool {
+	for _, created := range resources.created {
+		if created == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func (resources *resourcePool) release(resource string) error {
+	delete(resources.leases, resource)
+	if resources.kind == ResourceKindTempDir {
+		err := os.RemoveAll(resource)
+		if err == nil {
+			err = os.Mkdir(resource, 0700)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	resources.free = append(resources.free, resource)
+	return nil
+}
+
+func sanitizeDirRune(r rune) rune {
+	if r == os.PathSeparator || r == '/' || r == '*' {
+		return '-'
+	}
+	return r
+}
+
+//SharedValue is exchanged with the server's /shared/value endpoint
+type SharedValue struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+//SharedCompareAndSwap is exchanged with the server's /shared/compare-and-swap endpoint
+type SharedCompareAndSwap struct {
+	Key     string `json:"key"`
+	Old     []byte `json:"old"`
+	New     []byte `json:"new"`
+	Swapped bool   `json:"swapped"`
+}
+
+//SharedResourceLease is exchanged with the server's /shared/acquire and /shared/release endpoints
+type SharedResourceLease struct {
+	Node     int          `json:"node"`
+	Pool     string       `json:"pool"`
+	Kind     ResourceKind `json:"kind,omitempty"`
+	Resource string       `json:"resource,omitempty"`
+}
@@ -0,0 +1,122 @@
+package remote_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/ginkgo/internal/remote"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("SharedState", func() {
+	var state *SharedState
+
+	BeforeEach(func() {
+		state = NewSharedState()
+	})
+
+	AfterEach(func() {
+		state.Cleanup()
+	})
+
+	Describe("the key/value store", func() {
+		It("stores and returns values", func() {
+			_, ok := state.Get("a")
+			Ω(ok).Should(BeFalse())
+
+			state.Set("a", []byte("1"))
+			value, ok := state.Get("a")
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal([]byte("1")))
+		})
+
+		It("only swaps when the current value matches", func() {
+			Ω(state.CompareAndSwap("a", []byte("1"), []byte("2"))).Should(BeFalse())
+			Ω(state.CompareAndSwap("a", nil, []byte("1"))).Should(BeTrue())
+			Ω(state.CompareAndSwap("a", nil, []byte("2"))).Should(BeFalse())
+			Ω(state.CompareAndSwap("a", []byte("2"), []byte("3"))).Should(BeFalse())
+			Ω(state.CompareAndSwap("a", []byte("1"), []byte("3"))).Should(BeTrue())
+
+			value, _ := state.Get("a")
+			Ω(value).Should(Equal([]byte("3")))
+		})
+
+		It("waits for values to be stored", func() {
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				state.Set("b", []byte("2"))
+				state.CompareAndSwap("a", nil, []byte("1"))
+			}()
+
+			value, ok := state.Wait("a", time.Second)
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal([]byte("1")))
+
+			_, ok = state.Wait("c", 50*time.Millisecond)
+			Ω(ok).Should(BeFalse())
+		})
+	})
+
+	Describe("resource pools", func() {
+		It("hands out unique names and reuses released ones", func() {
+			Ω(state.Acquire(1, "db", ResourceKindName)).Should(Equal("db_1"))
+			Ω(state.Acquire(2, "db", ResourceKindName)).Should(Equal("db_2"))
+			Ω(state.Release(1, "db", "db_1")).Should(Succeed())
+			Ω(state.Acquire(3, "db", ResourceKindName)).Should(Equal("db_1"))
+			Ω(state.Acquire(3, "db", ResourceKindName)).Should(Equal("db_3"))
+		})
+
+		It("hands out distinct free ports", func() {
+			a, err := state.Acquire(1, "ports", ResourceKindPort)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := state.Acquire(2, "ports", ResourceKindPort)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(a).ShouldNot(Equal(b))
+			Ω(strconv.Atoi(a)).Should(BeNumerically(">", 0))
+		})
+
+		It("hands out empty temporary directories and removes them on cleanup", func() {
+			dir, err := state.Acquire(1, "fixtures", ResourceKindTempDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(dir).Should(BeADirectory())
+			Ω(ioutil.WriteFile(filepath.Join(dir, "leftover"), []byte("x"), 0600)).Should(Succeed())
+
+			Ω(state.Release(1, "fixtures", dir)).Should(Succeed())
+			Ω(state.Acquire(2, "fixtures", ResourceKindTempDir)).Should(Equal(dir))
+			Ω(filepath.Join(dir, "leftover")).ShouldNot(BeAnExistingFile())
+
+			state.Cleanup()
+			_, err = os.Stat(dir)
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+
+		It("refuses to mix kinds of resources in a pool", func() {
+			state.Acquire(1, "db", ResourceKindName)
+			_, err := state.Acquire(1, "db", ResourceKindPort)
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("only lets the node holding a resource release it", func() {
+			state.Acquire(1, "db", ResourceKindName)
+			Ω(state.Release(2, "db", "db_1")).ShouldNot(Succeed())
+			Ω(state.Release(1, "db", "db_2")).ShouldNot(Succeed())
+			Ω(state.Release(1, "other", "db_1")).ShouldNot(Succeed())
+		})
+
+		It("releases everything leased to a node", func() {
+			state.Acquire(1, "db", ResourceKindName)
+			state.Acquire(2, "db", ResourceKindName)
+			state.Acquire(1, "db", ResourceKindName)
+
+			state.ReleaseNode(1)
+			Ω(state.Acquire(3, "db", ResourceKindName)).Should(Equal("db_1"))
+			Ω(state.Acquire(3, "db", ResourceKindName)).Should(Equal("db_3"))
+			Ω(state.Acquire(3, "db", ResourceKindName)).Should(Equal("db_4"))
+		})
+	})
+})
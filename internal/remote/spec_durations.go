@@ -0,0 +1,183 @@
+package remote
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/internal/spec_iterator"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+//SpecDurationsFile is the name of the file, stored alongside each suite, in which the Ginkgo CLI caches spec durations between parallel runs
+const SpecDurationsFile = ".ginkgo-spec-durations.json"
+
+/*
+SpecDurations maps specs to the time it took to run them.  Specs are identified by their stable ID so that specs with the same text don't share a duration,
+or by their name (the spec's component texts joined by spaces) if they have no ID.
+
+The Ginkgo CLI persists these between runs and, when running with -scheduleByDuration, uses them to hand out specs longest-first.
+*/
+type SpecDurations map[string]time.Duration
+
+//LoadSpecDurations reads previously saved durations.  A missing file simply results in an empty set of durations.
+func LoadSpecDurations(path string) (SpecDurations, error) {
+	durations := SpecDurations{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return durations, err
+	}
+	err = json.Unmarshal(data, &durations)
+	return durations, err
+}
+
+func (durations SpecDurations) Save(path string) error {
+	data, err := json.MarshalIndent(durations, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0666)
+}
+
+//Estimate returns the recorded duration for the spec with the given ID or, if it has none, name.  Specs that have never been recorded are estimated at the mean of all recorded durations.
+func (durations SpecDurations) Estimate(id string, name string) time.Duration {
+	return durations.estimate(id, name, durations.mean())
+}
+
+func (durations SpecDurations) estimate(id string, name string, mean time.Duration) time.Duration {
+	if duration, ok := durations[specDurationKey(id, name)]; ok {
+		return duration
+	}
+	return mean
+}
+
+//mean returns the mean of all recorded durations, or zero if there are none
+func (durations SpecDurations) mean() time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	total := time.Duration(0)
+	for _, duration := range durations {
+		total += duration
+	}
+	return total / time.Duration(len(durations))
+}
+
+//Estimates returns the estimated duration of each of the passed-in specs.  Specs that will not run are estimated at zero.
+func (durations SpecDurations) Estimates(specs []spec_iterator.ScheduledSpec) []time.Duration {
+	mean := durations.mean()
+	estimates := make([]time.Duration, len(specs))
+	for i, spec := range specs {
+		if spec.WillRun {
+			estimates[i] = durations.estimate(spec.ID, spec.Name, mean)
+		}
+	}
+	return estimates
+}
+
+//LongestFirst returns the indices of the passed-in specs ordered by decreasing estimated duration.
+//Specs that will not run go last, and specs with equal estimates retain their relative order.
+func (durations SpecDurations) LongestFirst(specs []spec_iterator.ScheduledSpec) []int {
+	estimates := durations.Estimates(specs)
+	order := make([]int, len(specs))
+	for i, spec := range specs {
+		order[i] = i
+		if !spec.WillRun {
+			estimates[i] = -1
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return estimates[order[i]] > estimates[order[j]]
+	})
+
+	return order
+}
+
+func specDurationKey(id string, name string) string {
+	if id != "" {
+		return id
+	}
+	return name
+}
+
+/*
+SpecDurationsRecorder is a reporter used by the Ginkgo CLI to record how long each spec took to run and how busy each parallel node was.
+*/
+type SpecDurationsRecorder struct {
+	durations SpecDurations
+	nodeCount int
+	busyTimes []time.Duration
+	startTime time.Time
+	endTime   time.Time
+	lock      *sync.Mutex
+}
+
+func NewSpecDurationsRecorder(durations SpecDurations, nodeCount int) *SpecDurationsRecorder {
+	return &SpecDurationsRecorder{
+		durations: durations,
+		nodeCount: nodeCount,
+		busyTimes: make([]time.Duration, nodeCount),
+		lock:      &sync.Mutex{},
+	}
+}
+
+func (recorder *SpecDurationsRecorder) SpecSuiteWillBegin(config config.GinkgoConfigType, summary *types.SuiteSummary) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	if recorder.startTime.IsZero() {
+		recorder.startTime = time.Now()
+	}
+}
+
+func (recorder *SpecDurationsRecorder) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {}
+
+func (recorder *SpecDurationsRecorder) SpecWillRun(specSummary *types.SpecSummary) {}
+
+func (recorder *SpecDurationsRecorder) SpecDidComplete(specSummary *types.SpecSummary) {
+	if specSummary.State == types.SpecStatePending || specSummary.State == types.SpecStateSkipped {
+		return
+	}
+
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.durations[specDurationKey(specSummary.ID, strings.Join(specSummary.ComponentTexts, " "))] = specSummary.RunTime
+	if specSummary.ParallelNode >= 1 && specSummary.ParallelNode <= recorder.nodeCount {
+		recorder.busyTimes[specSummary.ParallelNode-1] += specSummary.RunTime
+	}
+}
+
+func (recorder *SpecDurationsRecorder) AfterSuiteDidRun(setupSummary *types.SetupSummary) {}
+
+func (recorder *SpecDurationsRecorder) SpecSuiteDidEnd(summary *types.SuiteSummary) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.endTime = time.Now()
+}
+
+//Durations returns the previously loaded durations updated with those of every spec that ran
+func (recorder *SpecDurationsRecorder) Durations() SpecDurations {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	return recorder.durations
+}
+
+//Utilization returns, for each node, the fraction of the run's wall-clock time the node spent running specs
+func (recorder *SpecDurationsRecorder) Utilization() (wallTime time.Duration, utilization []float64) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+
+	wallTime = recorder.endTime.Sub(recorder.startTime)
+	utilization = make([]float64, recorder.nodeCount)
+	if wallTime <= 0 {
+		return 0, utilization
+	}
+	for i, busyTime := range recorder.busyTimes {
+		utilization[i] = float64(busyTime) / float64(wallTime)
+	}
+	return wallTime, utilization
+}
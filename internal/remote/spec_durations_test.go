@@ -0,0 +1,160 @@
+package remote_test
+
+import (
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/gomega"
+
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hackrish007/ginkgo/config"
+	. "github.com/hackrish007/ginkgo/internal/remote"
+	"github.com/hackrish007/ginkgo/internal/spec_iterator"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+var _ = Describe("SpecDurations", func() {
+	var durations SpecDurations
+
+	BeforeEach(func() {
+		durations = SpecDurations{
+			"A": time.Second,
+			"B": 3 * time.Second,
+		}
+	})
+
+	Describe("Estimate", func() {
+		It("should return the recorded duration", func() {
+			Ω(durations.Estimate("", "B")).Should(Equal(3 * time.Second))
+		})
+
+		It("should look specs with an ID up by their ID", func() {
+			durations["a_test.go:A"] = 5 * time.Second
+			Ω(durations.Estimate("a_test.go:A", "B")).Should(Equal(5 * time.Second))
+		})
+
+		It("should estimate unrecorded specs at the mean of the recorded durations", func() {
+			Ω(durations.Estimate("", "C")).Should(Equal(2 * time.Second))
+		})
+
+		It("should estimate zero when nothing has been recorded", func() {
+			Ω(SpecDurations{}.Estimate("", "C")).Should(BeZero())
+		})
+	})
+
+	Describe("LongestFirst", func() {
+		It("should order specs by decreasing estimate, putting specs that won't run last", func() {
+			order := durations.LongestFirst([]spec_iterator.ScheduledSpec{
+				{Name: "skipped", WillRun: false},
+				{Name: "A", WillRun: true},
+				{Name: "unknown", WillRun: true},
+				{Name: "B", WillRun: true},
+			})
+			Ω(order).Should(Equal([]int{3, 2, 1, 0}))
+		})
+
+		It("should tell specs with the same name apart by their IDs", func() {
+			order := SpecDurations{"a_test.go:A": time.Second, "b_test.go:A": 2 * time.Second}.LongestFirst([]spec_iterator.ScheduledSpec{
+				{ID: "a_test.go:A", Name: "A", WillRun: true},
+				{ID: "b_test.go:A", Name: "A", WillRun: true},
+			})
+			Ω(order).Should(Equal([]int{1, 0}))
+		})
+
+		It("should preserve the order of specs with equal estimates", func() {
+			order := SpecDurations{}.LongestFirst([]spec_iterator.ScheduledSpec{
+				{Name: "A", WillRun: true},
+				{Name: "B", WillRun: true},
+				{Name: "C", WillRun: true},
+			})
+			Ω(order).Should(Equal([]int{0, 1, 2}))
+		})
+	})
+
+	Describe("saving and loading", func() {
+		var path string
+
+		BeforeEach(func() {
+			dir, err := ioutil.TempDir("", "ginkgo-spec-durations")
+			Ω(err).ShouldNot(HaveOccurred())
+			path = filepath.Join(dir, SpecDurationsFile)
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(filepath.Dir(path))
+		})
+
+		It("should round-trip", func() {
+			Ω(durations.Save(path)).Should(Succeed())
+			loaded, err := LoadSpecDurations(path)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(loaded).Should(Equal(durations))
+		})
+
+		It("should return empty durations and an error when the file does not exist", func() {
+			loaded, err := LoadSpecDurations(path)
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+			Ω(loaded).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("SpecDurationsRecorder", func() {
+	var recorder *SpecDurationsRecorder
+
+	BeforeEach(func() {
+		recorder = NewSpecDurationsRecorder(SpecDurations{"[Top Level] old spec": time.Second}, 2)
+		recorder.SpecSuiteWillBegin(config.GinkgoConfigType{}, &types.SuiteSummary{})
+		recorder.SpecDidComplete(&types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "A", "passes"},
+			State:          types.SpecStatePassed,
+			RunTime:        time.Hour,
+			ParallelNode:   1,
+		})
+		recorder.SpecDidComplete(&types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "A", "fails"},
+			State:          types.SpecStateFailed,
+			RunTime:        time.Minute,
+			ParallelNode:   2,
+		})
+		recorder.SpecDidComplete(&types.SpecSummary{
+			ID:             "a_test.go:A/is duplicated",
+			ComponentTexts: []string{"[Top Level]", "A", "is duplicated"},
+			State:          types.SpecStatePassed,
+			RunTime:        time.Millisecond,
+			ParallelNode:   1,
+		})
+		recorder.SpecDidComplete(&types.SpecSummary{
+			ID:             "a_test.go:A/is duplicated~2",
+			ComponentTexts: []string{"[Top Level]", "A", "is duplicated"},
+			State:          types.SpecStatePassed,
+			RunTime:        time.Second,
+			ParallelNode:   1,
+		})
+		recorder.SpecDidComplete(&types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "A", "is skipped"},
+			State:          types.SpecStateSkipped,
+			ParallelNode:   2,
+		})
+		recorder.SpecSuiteDidEnd(&types.SuiteSummary{})
+	})
+
+	It("should record the durations of specs that ran, on top of the previous durations", func() {
+		Ω(recorder.Durations()).Should(Equal(SpecDurations{
+			"[Top Level] old spec":        time.Second,
+			"[Top Level] A passes":        time.Hour,
+			"[Top Level] A fails":         time.Minute,
+			"a_test.go:A/is duplicated":   time.Millisecond,
+			"a_test.go:A/is duplicated~2": time.Second,
+		}))
+	})
+
+	It("should compute each node's utilization", func() {
+		wallTime, utilization := recorder.Utilization()
+		Ω(wallTime).Should(BeNumerically(">", 0))
+		Ω(utilization).Should(HaveLen(2))
+		Ω(utilization[0]).Should(BeNumerically(">", utilization[1]))
+	})
+})
@@ -0,0 +1,188 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+//TimelineEvent is an event in the Chrome Trace Event format, as understood by chrome://tracing and Perfetto.
+//Timestamps and durations are in microseconds.
+type TimelineEvent struct {
+	Name      string                 `json:"name"`
+	Category  string                 `json:"cat,omitempty"`
+	Phase     string                 `json:"ph"`
+	Timestamp int64                  `json:"ts"`
+	Duration  int64                  `json:"dur"`
+	ProcessID int                    `json:"pid"`
+	ThreadID  int                    `json:"tid"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+}
+
+//Timeline is the contents of a Chrome Trace Event JSON file
+type Timeline struct {
+	TraceEvents     []TimelineEvent `json:"traceEvents"`
+	DisplayTimeUnit string          `json:"displayTimeUnit"`
+}
+
+/*
+TimelineRecorder is a reporter used by the Ginkgo CLI to record when each spec and each setup node ran on each parallel node.
+
+Nodes report specs and setup nodes once they complete, along with when they started and how long they ran, and the recorder places
+each event on the timeline accordingly.  Each parallel node is drawn as a thread of a single process.
+*/
+type TimelineRecorder struct {
+	suiteDescription string
+	startTime        time.Time
+	events           []TimelineEvent
+	nodes            map[int]bool
+	lock             *sync.Mutex
+}
+
+func NewTimelineRecorder() *TimelineRecorder {
+	return &TimelineRecorder{
+		nodes: map[int]bool{},
+		lock:  &sync.Mutex{},
+	}
+}
+
+func (recorder *TimelineRecorder) SpecSuiteWillBegin(config config.GinkgoConfigType, summary *types.SuiteSummary) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	if recorder.startTime.IsZero() {
+		recorder.startTime = time.Now()
+		if summary != nil {
+			recorder.suiteDescription = summary.SuiteDescription
+		}
+	}
+}
+
+func (recorder *TimelineRecorder) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {
+	recorder.recordSetup(setupSummary)
+}
+
+func (recorder *TimelineRecorder) SpecWillRun(specSummary *types.SpecSummary) {}
+
+func (recorder *TimelineRecorder) SpecDidComplete(specSummary *types.SpecSummary) {
+	if specSummary.State == types.SpecStatePending || specSummary.State == types.SpecStateSkipped {
+		return
+	}
+
+	args := map[string]interface{}{
+		"text":  culpritText(specSummary.ComponentTexts),
+		"state": specSummary.State.String(),
+		"node":  specSummary.ParallelNode,
+	}
+	if specSummary.ID != "" {
+		args["id"] = specSummary.ID
+	}
+	if len(specSummary.ComponentCodeLocations) > 0 {
+		args["location"] = specSummary.ComponentCodeLocations[len(specSummary.ComponentCodeLocations)-1].String()
+	}
+	category := "spec"
+	if specSummary.IsMeasurement {
+		category = "measurement"
+	}
+	recorder.record(culpritText(specSummary.ComponentTexts), category, specSummary.ParallelNode, specSummary.StartTime, specSummary.RunTime, args)
+}
+
+func (recorder *TimelineRecorder) AfterSuiteDidRun(setupSummary *types.SetupSummary) {
+	recorder.recordSetup(setupSummary)
+}
+
+func (recorder *TimelineRecorder) SpecSuiteDidEnd(summary *types.SuiteSummary) {}
+
+func (recorder *TimelineRecorder) recordSetup(setupSummary *types.SetupSummary) {
+	if setupSummary == nil || setupSummary.State == types.SpecStateInvalid {
+		return
+	}
+
+	name := "BeforeSuite"
+	if setupSummary.ComponentType == types.SpecComponentTypeAfterSuite {
+		name = "AfterSuite"
+	}
+	args := map[string]interface{}{
+		"text":     name,
+		"state":    setupSummary.State.String(),
+		"node":     setupSummary.ParallelNode,
+		"location": setupSummary.CodeLocation.String(),
+	}
+	recorder.record(name, "setup", setupSummary.ParallelNode, setupSummary.StartTime, setupSummary.RunTime, args)
+}
+
+//record adds an event to the timeline.  Events reported without a start time are taken to have ended when the recorder heard of them.
+func (recorder *TimelineRecorder) record(name string, category string, node int, startTime time.Time, runTime time.Duration, args map[string]interface{}) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+
+	if startTime.IsZero() {
+		startTime = time.Now().Add(-runTime)
+	}
+	if recorder.startTime.IsZero() {
+		recorder.startTime = startTime
+	}
+	start := startTime.Sub(recorder.startTime)
+	if start < 0 {
+		start = 0
+	}
+
+	recorder.nodes[node] = true
+	recorder.events = append(recorder.events, TimelineEvent{
+		Name:      name,
+		Category:  category,
+		Phase:     "X",
+		Timestamp: start.Microseconds(),
+		Duration:  runTime.Microseconds(),
+		ProcessID: 1,
+		ThreadID:  node,
+		Args:      args,
+	})
+}
+
+//Timeline returns the recorded events, preceded by metadata events naming the suite and each parallel node
+func (recorder *TimelineRecorder) Timeline() Timeline {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+
+	events := []TimelineEvent{{
+		Name:      "process_name",
+		Phase:     "M",
+		ProcessID: 1,
+		Args:      map[string]interface{}{"name": recorder.suiteDescription},
+	}}
+
+	nodes := []int{}
+	for node := range recorder.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Ints(nodes)
+	for _, node := range nodes {
+		events = append(events, TimelineEvent{
+			Name:      "thread_name",
+			Phase:     "M",
+			ProcessID: 1,
+			ThreadID:  node,
+			Args:      map[string]interface{}{"name": fmt.Sprintf("Node %d", node)},
+		})
+	}
+
+	return Timeline{
+		TraceEvents:     append(events, recorder.events...),
+		DisplayTimeUnit: "ms",
+	}
+}
+
+//Save writes the timeline to path in the Chrome Trace Event JSON format
+func (recorder *TimelineRecorder) Save(path string) error {
+	encoded, err := json.Marshal(recorder.Timeline())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, encoded, 0644)
+}
@@ -0,0 +1,134 @@
+package remote_test
+
+import (
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/gomega"
+
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hackrish007/ginkgo/config"
+	. "github.com/hackrish007/ginkgo/internal/remote"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+var _ = Describe("TimelineRecorder", func() {
+	var recorder *TimelineRecorder
+
+	BeforeEach(func() {
+		recorder = NewTimelineRecorder()
+		recorder.SpecSuiteWillBegin(config.GinkgoConfigType{}, &types.SuiteSummary{SuiteDescription: "My Suite"})
+	})
+
+	It("should record specs and setup nodes on the parallel node that ran them", func() {
+		recorder.BeforeSuiteDidRun(&types.SetupSummary{
+			ComponentType: types.SpecComponentTypeBeforeSuite,
+			State:         types.SpecStatePassed,
+			RunTime:       time.Millisecond,
+			ParallelNode:  1,
+		})
+		recorder.SpecDidComplete(&types.SpecSummary{
+			ID:                     "abc",
+			ComponentTexts:         []string{"[Top Level]", "A", "B"},
+			ComponentCodeLocations: []types.CodeLocation{{}, {}, {FileName: "foo_test.go", LineNumber: 3}},
+			State:                  types.SpecStateFailed,
+			RunTime:                2 * time.Millisecond,
+			ParallelNode:           2,
+		})
+		recorder.SpecDidComplete(&types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "pending"},
+			State:          types.SpecStatePending,
+			ParallelNode:   2,
+		})
+		recorder.AfterSuiteDidRun(&types.SetupSummary{
+			ComponentType: types.SpecComponentTypeAfterSuite,
+			State:         types.SpecStatePassed,
+			RunTime:       time.Millisecond,
+			ParallelNode:  1,
+		})
+		recorder.AfterSuiteDidRun(&types.SetupSummary{ParallelNode: 2})
+
+		timeline := recorder.Timeline()
+		Ω(timeline.DisplayTimeUnit).Should(Equal("ms"))
+
+		events := timeline.TraceEvents
+		Ω(events).Should(HaveLen(6))
+		Ω(events[0].Phase).Should(Equal("M"))
+		Ω(events[0].Args["name"]).Should(Equal("My Suite"))
+		Ω(events[1].Args["name"]).Should(Equal("Node 1"))
+		Ω(events[2].Args["name"]).Should(Equal("Node 2"))
+
+		Ω(events[3].Name).Should(Equal("BeforeSuite"))
+		Ω(events[3].Category).Should(Equal("setup"))
+		Ω(events[3].ThreadID).Should(Equal(1))
+		Ω(events[3].Duration).Should(BeEquivalentTo(1000))
+
+		spec := events[4]
+		Ω(spec.Name).Should(Equal("A B"))
+		Ω(spec.Category).Should(Equal("spec"))
+		Ω(spec.Phase).Should(Equal("X"))
+		Ω(spec.ThreadID).Should(Equal(2))
+		Ω(spec.Duration).Should(BeEquivalentTo(2000))
+		Ω(spec.Timestamp).Should(BeNumerically(">=", 0))
+		Ω(spec.Args).Should(Equal(map[string]interface{}{
+			"text":     "A B",
+			"state":    "failed",
+			"node":     2,
+			"id":       "abc",
+			"location": "foo_test.go:3",
+		}))
+
+		Ω(events[5].Name).Should(Equal("AfterSuite"))
+		Ω(events[5].Timestamp).Should(BeNumerically(">=", events[3].Timestamp))
+	})
+
+	It("should place events at the times the nodes report they started", func() {
+		start := time.Now().Add(time.Second)
+		recorder.BeforeSuiteDidRun(&types.SetupSummary{
+			ComponentType: types.SpecComponentTypeBeforeSuite,
+			State:         types.SpecStatePassed,
+			StartTime:     start,
+			RunTime:       time.Second,
+			ParallelNode:  1,
+		})
+		recorder.SpecDidComplete(&types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "A"},
+			State:          types.SpecStatePassed,
+			StartTime:      start.Add(3 * time.Second),
+			RunTime:        time.Second,
+			ParallelNode:   1,
+		})
+
+		events := recorder.Timeline().TraceEvents
+		Ω(events).Should(HaveLen(4))
+		Ω(events[2].Name).Should(Equal("BeforeSuite"))
+		Ω(events[3].Name).Should(Equal("A"))
+		Ω(events[2].Timestamp).Should(BeNumerically(">=", 1000000))
+		Ω(events[3].Timestamp - events[2].Timestamp).Should(BeEquivalentTo(3000000))
+	})
+
+	It("should save the timeline as Chrome Trace Event JSON", func() {
+		recorder.SpecDidComplete(&types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "A"},
+			State:          types.SpecStatePassed,
+			ParallelNode:   1,
+		})
+
+		dir, err := ioutil.TempDir("", "ginkgo-timeline")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "timeline.json")
+		Ω(recorder.Save(path)).Should(Succeed())
+
+		content, err := ioutil.ReadFile(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		var decoded map[string]interface{}
+		Ω(json.Unmarshal(content, &decoded)).Should(Succeed())
+		Ω(decoded["displayTimeUnit"]).Should(Equal("ms"))
+		Ω(decoded["traceEvents"]).Should(HaveLen(3))
+	})
+})
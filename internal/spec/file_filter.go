@@ -0,0 +1,60 @@
+package spec
+
+import (
+	"github.com/hackrish007/ginkgo/internal/codelocation"
+	"github.com/hackrish007/ginkgo/internal/spec/filefilter"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+/*
+FileFilter selects specs by the source file, and optionally the lines, they are defined on.
+
+Filters have the form path, path:line or path:line1-line2.  The path is a regular expression matched against the full path of the file, starting at
+one of its components: foo_test.go matches /src/foo_test.go but not /src/xfoo_test.go.
+*/
+type FileFilter filefilter.Filter
+
+func ParseFileFilter(filter string) (FileFilter, error) {
+	fileFilter, err := filefilter.Parse(filter)
+	return FileFilter(fileFilter), err
+}
+
+// ParseFileFilters parses the values of -focusFile or -skipFile.
+func ParseFileFilters(filters []string) ([]FileFilter, error) {
+	fileFilters := make([]FileFilter, len(filters))
+	for i, filter := range filters {
+		fileFilter, err := ParseFileFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		fileFilters[i] = fileFilter
+	}
+	return fileFilters, nil
+}
+
+// Matches returns true if the node at codeLocation is defined in a matching file
+// and its source range overlaps the filter's lines.
+func (f FileFilter) Matches(codeLocation types.CodeLocation) bool {
+	if codeLocation.FileName == "" || !f.Filename.MatchString(codeLocation.FileName) {
+		return false
+	}
+	if f.LineStart == 0 {
+		return true
+	}
+	return codeLocation.LineNumber <= f.LineEnd && codelocation.EndLine(codeLocation) >= f.LineStart
+}
+
+// MatchesFileFilters returns true if the spec's subject, or any of its containers, matches any of the filters.
+func (spec *Spec) MatchesFileFilters(filters []FileFilter) bool {
+	for _, filter := range filters {
+		if filter.Matches(spec.subject.CodeLocation()) {
+			return true
+		}
+		for _, container := range spec.containers {
+			if filter.Matches(container.CodeLocation()) {
+				return true
+			}
+		}
+	}
+	return false
+}
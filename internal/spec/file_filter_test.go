@@ -0,0 +1,139 @@
+package spec_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/ginkgo/internal/spec"
+	. "github.com/hackrish007/gomega"
+
+	"github.com/hackrish007/ginkgo/internal/containernode"
+	"github.com/hackrish007/ginkgo/internal/leafnodes"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+const fileFilterFixture = `package fixture
+
+var _ = Describe("A", func() {
+	It("1", func() {
+	})
+
+	It("2", func() {
+	})
+})
+
+var _ = Describe("B", func() {
+	It("1", func() {
+	})
+})
+`
+
+var _ = Describe("FileFilter", func() {
+	Describe("parsing", func() {
+		It("should parse a bare path", func() {
+			filter, err := ParseFileFilter("foo_test.go")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(filter.Filename.MatchString("/src/foo_test.go")).Should(BeTrue())
+			Ω(filter.Filename.MatchString("foo_test.go")).Should(BeTrue())
+			Ω(filter.LineStart).Should(BeZero())
+			Ω(filter.LineEnd).Should(BeZero())
+		})
+
+		It("should parse a path and a line", func() {
+			filter, err := ParseFileFilter("foo_test.go:12")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(filter.Filename.MatchString("/src/foo_test.go")).Should(BeTrue())
+			Ω(filter.LineStart).Should(Equal(12))
+			Ω(filter.LineEnd).Should(Equal(12))
+		})
+
+		It("should parse a path and a range of lines", func() {
+			filter, err := ParseFileFilter(`bar/.*_test\.go:12-20`)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(filter.Filename.MatchString("/src/bar/foo_test.go")).Should(BeTrue())
+			Ω(filter.Filename.MatchString("/src/foobar/foo_test.go")).Should(BeFalse())
+			Ω(filter.LineStart).Should(Equal(12))
+			Ω(filter.LineEnd).Should(Equal(20))
+		})
+
+		It("should match paths from the start of a path component", func() {
+			filter, err := ParseFileFilter("foo_test.go")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(filter.Filename.MatchString("/src/xfoo_test.go")).Should(BeFalse())
+		})
+
+		It("should error on an invalid regular expression", func() {
+			_, err := ParseFileFilter("foo(:12")
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should error on a backwards range", func() {
+			_, err := ParseFileFilter("foo_test.go:20-12")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("matching specs", func() {
+		var fileName string
+		var specA1, specA2, specB1 *Spec
+
+		newSpec := func(containerText string, containerLine int, text string, line int) *Spec {
+			subject := leafnodes.NewItNode(text, func() {}, noneFlag, types.CodeLocation{FileName: fileName, LineNumber: line}, 0, nil, 0)
+			return New(subject, []*containernode.ContainerNode{
+				containernode.New("[Top Level]", noneFlag, types.CodeLocation{}),
+				containernode.New(containerText, noneFlag, types.CodeLocation{FileName: fileName, LineNumber: containerLine}),
+			}, false)
+		}
+
+		matches := func(filters ...string) []bool {
+			fileFilters := []FileFilter{}
+			for _, filter := range filters {
+				fileFilter, err := ParseFileFilter(filter)
+				Ω(err).ShouldNot(HaveOccurred())
+				fileFilters = append(fileFilters, fileFilter)
+			}
+			return []bool{specA1.MatchesFileFilters(fileFilters), specA2.MatchesFileFilters(fileFilters), specB1.MatchesFileFilters(fileFilters)}
+		}
+
+		BeforeEach(func() {
+			dir, err := ioutil.TempDir("", "ginkgo-file-filter")
+			Ω(err).ShouldNot(HaveOccurred())
+			fileName = filepath.Join(dir, "fixture_test.go")
+			Ω(ioutil.WriteFile(fileName, []byte(fileFilterFixture), 0666)).Should(Succeed())
+
+			specA1 = newSpec("A", 3, "1", 4)
+			specA2 = newSpec("A", 3, "2", 7)
+			specB1 = newSpec("B", 11, "1", 12)
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(filepath.Dir(fileName))
+		})
+
+		It("should match every spec in a matching file", func() {
+			Ω(matches("fixture_test")).Should(Equal([]bool{true, true, true}))
+			Ω(matches("other_test")).Should(Equal([]bool{false, false, false}))
+		})
+
+		It("should match specs whose subject spans the line", func() {
+			Ω(matches("fixture_test.go:12")).Should(Equal([]bool{false, false, true}))
+			Ω(matches("fixture_test.go:13")).Should(Equal([]bool{false, false, true}))
+		})
+
+		It("should match every spec in a container that spans the line", func() {
+			Ω(matches("fixture_test.go:5")).Should(Equal([]bool{true, true, false}))
+			Ω(matches("fixture_test.go:9")).Should(Equal([]bool{true, true, false}))
+		})
+
+		It("should match specs that overlap a range of lines", func() {
+			Ω(matches("fixture_test.go:10-11")).Should(Equal([]bool{false, false, true}))
+			Ω(matches("fixture_test.go:10")).Should(Equal([]bool{false, false, false}))
+		})
+
+		It("should OR multiple filters", func() {
+			Ω(matches("other_test.go", "fixture_test.go:13")).Should(Equal([]bool{false, false, true}))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+/*
+Package filefilter parses the file filters that select specs by the file, and optionally the lines, they are defined on:
+the values of -focusFile and -skipFile and the locations in quarantine files.
+
+It depends on nothing else in Ginkgo so that the config package can reject invalid flags with the same parser the spec package uses.
+*/
+package filefilter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var filterRegexp = regexp.MustCompile(`^(.+?)(?::(\d+)(?:-(\d+))?)?$`)
+
+// Filter is a parsed file filter.  Filters have the form path, path:line or path:line1-line2.  The path is a regular expression matched against the
+// full path of the file, starting at one of its components: foo_test.go matches /src/foo_test.go but not /src/xfoo_test.go.  LineStart is zero
+// for filters without lines.
+type Filter struct {
+	Filename  *regexp.Regexp
+	LineStart int
+	LineEnd   int
+}
+
+func Parse(filter string) (Filter, error) {
+	components := filterRegexp.FindStringSubmatch(filter)
+	if components == nil {
+		return Filter{}, fmt.Errorf("Invalid file filter %q", filter)
+	}
+
+	filename, err := regexp.Compile(`(?:^|/)(?:` + components[1] + `)`)
+	if err != nil {
+		return Filter{}, fmt.Errorf("Invalid file filter %q: %s", filter, err.Error())
+	}
+
+	parsed := Filter{Filename: filename}
+	if components[2] != "" {
+		parsed.LineStart, _ = strconv.Atoi(components[2])
+		parsed.LineEnd = parsed.LineStart
+	}
+	if components[3] != "" {
+		parsed.LineEnd, _ = strconv.Atoi(components[3])
+		if parsed.LineEnd < parsed.LineStart {
+			return Filter{}, fmt.Errorf("Invalid file filter %q: line range ends before it starts", filter)
+		}
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,28 @@
+package spec
+
+// ApplyIDFocus skips every spec whose ID is not among ids.  Should no spec have
+// one of the IDs, e.g. because the specs were renamed or removed since the IDs
+// were recorded, ApplyIDFocus skips nothing.
+func (e *Specs) ApplyIDFocus(ids []string) {
+	focused := map[string]bool{}
+	for _, id := range ids {
+		focused[id] = true
+	}
+
+	matched := false
+	for _, spec := range e.specs {
+		if focused[spec.id] {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return
+	}
+
+	for _, spec := range e.specs {
+		if !focused[spec.id] {
+			spec.Skip()
+		}
+	}
+}
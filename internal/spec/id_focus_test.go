@@ -0,0 +1,46 @@
+package spec_test
+
+import (
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/ginkgo/internal/spec"
+	. "github.com/hackrish007/gomega"
+
+	"github.com/hackrish007/ginkgo/internal/codelocation"
+	"github.com/hackrish007/ginkgo/internal/containernode"
+	"github.com/hackrish007/ginkgo/internal/leafnodes"
+)
+
+var _ = Describe("Focusing on spec IDs", func() {
+	var specs *Specs
+
+	BeforeEach(func() {
+		specSlice := []*Spec{}
+		for _, id := range []string{"a", "b", "c"} {
+			subject := leafnodes.NewItNode("spec "+id, func() {}, noneFlag, codelocation.New(0), 0, nil, 0)
+			spec := New(subject, []*containernode.ContainerNode{}, false)
+			spec.SetID(id)
+			specSlice = append(specSlice, spec)
+		}
+		specs = NewSpecs(specSlice)
+	})
+
+	skipped := func() []string {
+		ids := []string{}
+		for _, spec := range specs.Specs() {
+			if spec.Skipped() {
+				ids = append(ids, spec.ID())
+			}
+		}
+		return ids
+	}
+
+	It("skips the specs whose IDs were not passed in", func() {
+		specs.ApplyIDFocus([]string{"a", "c", "gone"})
+		Ω(skipped()).Should(Equal([]string{"b"}))
+	})
+
+	It("skips nothing when no spec has one of the IDs", func() {
+		specs.ApplyIDFocus([]string{"gone"})
+		Ω(skipped()).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,130 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const quarantineDateFormat = "2006-01-02"
+
+/*
+QuarantineEntry identifies a known-flaky spec that should keep running without failing the suite.
+
+Exactly one of Text (the spec's full text), Regex (a regular expression over the spec's full text) or Location (a file:line or file:line1-line2 filter, as accepted by -focusFile) must be provided.
+Reason typically points at the ticket tracking the flake.  Expires, if set, is the last day (YYYY-MM-DD) on which the entry applies.
+*/
+type QuarantineEntry struct {
+	Text     string `json:"text,omitempty"`
+	Regex    string `json:"regex,omitempty"`
+	Location string `json:"location,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+
+	regex      *regexp.Regexp
+	location   FileFilter
+	expiration time.Time
+}
+
+// LoadQuarantine reads and validates a quarantine file: a JSON array of QuarantineEntry objects
+func LoadQuarantine(path string) ([]QuarantineEntry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []QuarantineEntry{}
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid quarantine file %s: %s", path, err.Error())
+	}
+
+	for i := range entries {
+		err = entries[i].compile()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid quarantine entry #%d in %s: %s", i+1, path, err.Error())
+		}
+	}
+
+	return entries, nil
+}
+
+func (entry *QuarantineEntry) compile() error {
+	identifiers := 0
+	for _, identifier := range []string{entry.Text, entry.Regex, entry.Location} {
+		if identifier != "" {
+			identifiers++
+		}
+	}
+	if identifiers != 1 {
+		return fmt.Errorf("exactly one of text, regex or location must be provided")
+	}
+
+	var err error
+	if entry.Regex != "" {
+		entry.regex, err = regexp.Compile(entry.Regex)
+		if err != nil {
+			return err
+		}
+	}
+	if entry.Location != "" {
+		entry.location, err = ParseFileFilter(entry.Location)
+		if err != nil {
+			return err
+		}
+	}
+	if entry.Expires != "" {
+		expires, err := time.Parse(quarantineDateFormat, entry.Expires)
+		if err != nil {
+			return fmt.Errorf("expires must be a YYYY-MM-DD date")
+		}
+		entry.expiration = expires.AddDate(0, 0, 1)
+	}
+	return nil
+}
+
+// Identifier returns whichever of Text, Regex or Location identifies the entry
+func (entry QuarantineEntry) Identifier() string {
+	if entry.Regex != "" {
+		return entry.Regex
+	}
+	if entry.Location != "" {
+		return entry.Location
+	}
+	return entry.Text
+}
+
+// Expired returns true if the entry's expiration date has passed
+func (entry QuarantineEntry) Expired(now time.Time) bool {
+	return !entry.expiration.IsZero() && !now.Before(entry.expiration)
+}
+
+// Matches returns true if the entry identifies the passed-in spec.  Spec texts may be given with or without the leading [Top Level].
+func (entry QuarantineEntry) Matches(spec *Spec) bool {
+	text := spec.ConcatenatedString()
+	switch {
+	case entry.regex != nil:
+		return entry.regex.MatchString(text)
+	case entry.Location != "":
+		return spec.MatchesFileFilters([]FileFilter{entry.location})
+	case entry.Text == text:
+		return true
+	default:
+		return len(spec.containers) > 0 && entry.Text == strings.TrimPrefix(text, spec.containers[0].Text()+" ")
+	}
+}
+
+// ApplyQuarantine marks every spec matched by an unexpired entry as quarantined
+func (e *Specs) ApplyQuarantine(entries []QuarantineEntry, now time.Time) {
+	for _, spec := range e.specs {
+		for _, entry := range entries {
+			if !entry.Expired(now) && entry.Matches(spec) {
+				spec.Quarantine(entry.Reason)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package spec_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/ginkgo/internal/spec"
+	. "github.com/hackrish007/gomega"
+
+	"github.com/hackrish007/ginkgo/internal/codelocation"
+	"github.com/hackrish007/ginkgo/internal/containernode"
+	"github.com/hackrish007/ginkgo/internal/leafnodes"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+var _ = Describe("Quarantine", func() {
+	var dir string
+
+	writeQuarantine := func(content string) string {
+		path := filepath.Join(dir, "quarantine.json")
+		Ω(ioutil.WriteFile(path, []byte(content), 0666)).Should(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "ginkgo-quarantine")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("loading", func() {
+		It("should load the entries", func() {
+			entries, err := LoadQuarantine(writeQuarantine(`[
+				{"text": "A flakes", "reason": "ISSUE-1", "expires": "2021-06-30"},
+				{"regex": "B.*flakes"},
+				{"location": "foo_test.go:12"}
+			]`))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(entries).Should(HaveLen(3))
+			Ω(entries[0].Identifier()).Should(Equal("A flakes"))
+			Ω(entries[0].Reason).Should(Equal("ISSUE-1"))
+			Ω(entries[1].Identifier()).Should(Equal("B.*flakes"))
+			Ω(entries[2].Identifier()).Should(Equal("foo_test.go:12"))
+		})
+
+		It("should error when the file does not exist", func() {
+			_, err := LoadQuarantine(filepath.Join(dir, "missing.json"))
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should error on invalid JSON", func() {
+			_, err := LoadQuarantine(writeQuarantine(`{`))
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should error when an entry has no identifier, or more than one", func() {
+			_, err := LoadQuarantine(writeQuarantine(`[{"reason": "ISSUE-1"}]`))
+			Ω(err).Should(MatchError(ContainSubstring("entry #1")))
+
+			_, err = LoadQuarantine(writeQuarantine(`[{"text": "A"}, {"text": "A", "regex": "A"}]`))
+			Ω(err).Should(MatchError(ContainSubstring("entry #2")))
+		})
+
+		It("should error on invalid regexes, locations and dates", func() {
+			_, err := LoadQuarantine(writeQuarantine(`[{"regex": "("}]`))
+			Ω(err).Should(HaveOccurred())
+
+			_, err = LoadQuarantine(writeQuarantine(`[{"location": "foo_test.go:12-10"}]`))
+			Ω(err).Should(HaveOccurred())
+
+			_, err = LoadQuarantine(writeQuarantine(`[{"text": "A", "expires": "next week"}]`))
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("expiry", func() {
+		It("should apply through the expiry date", func() {
+			entries, err := LoadQuarantine(writeQuarantine(`[{"text": "A", "expires": "2021-06-30"}, {"text": "B"}]`))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(entries[0].Expired(time.Date(2021, 6, 30, 23, 59, 0, 0, time.UTC))).Should(BeFalse())
+			Ω(entries[0].Expired(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC))).Should(BeTrue())
+			Ω(entries[1].Expired(time.Date(2121, 7, 1, 0, 0, 0, 0, time.UTC))).Should(BeFalse())
+		})
+	})
+
+	Describe("applying", func() {
+		var specs *Specs
+		var now time.Time
+
+		newSpec := func(text string) *Spec {
+			subject := leafnodes.NewItNode(text, func() {}, noneFlag, codelocation.New(0), 0, nil, 0)
+			return New(subject, []*containernode.ContainerNode{
+				containernode.New("[Top Level]", noneFlag, types.CodeLocation{}),
+				containernode.New("Container", noneFlag, types.CodeLocation{}),
+			}, false)
+		}
+
+		quarantinedTexts := func() []string {
+			texts := []string{}
+			for _, spec := range specs.Specs() {
+				if spec.Quarantined() {
+					texts = append(texts, spec.ConcatenatedString())
+				}
+			}
+			return texts
+		}
+
+		BeforeEach(func() {
+			now = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+			specs = NewSpecs([]*Spec{newSpec("A"), newSpec("B"), newSpec("C"), newSpec("D")})
+		})
+
+		It("should quarantine the specs matched by unexpired entries", func() {
+			entries, err := LoadQuarantine(writeQuarantine(`[
+				{"text": "Container A", "reason": "ISSUE-1"},
+				{"text": "[Top Level] Container B"},
+				{"regex": "Container [C]$"},
+				{"text": "Container D", "expires": "2021-05-31"}
+			]`))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			specs.ApplyQuarantine(entries, now)
+			Ω(quarantinedTexts()).Should(Equal([]string{"[Top Level] Container A", "[Top Level] Container B", "[Top Level] Container C"}))
+			Ω(specs.Specs()[0].Summary("suite").QuarantineReason).Should(Equal("ISSUE-1"))
+		})
+
+		It("should match specs by location", func() {
+			entries, err := LoadQuarantine(writeQuarantine(`[{"location": "quarantine_test.go"}]`))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			specs.ApplyQuarantine(entries, now)
+			Ω(quarantinedTexts()).Should(HaveLen(4))
+		})
+	})
+})
@@ -0,0 +1,32 @@
+package spec
+
+import "hash/fnv"
+
+// ShardFor returns the one-indexed shard, out of total, that the spec belongs to.
+// Shards are computed from a hash of the spec's ID (or of its text, for specs
+// without an ID) so that they do not depend on the random seed, the order of the
+// specs, or which specs are focused.
+func (spec *Spec) ShardFor(total int) int {
+	identity := spec.id
+	if identity == "" {
+		identity = spec.ConcatenatedString()
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(identity))
+	return int(hash.Sum32()%uint32(total)) + 1
+}
+
+// ApplySharding removes every spec that does not belong to the index-th of total shards.
+func (e *Specs) ApplySharding(index, total int) {
+	specs := []*Spec{}
+	names := []string{}
+	for i, spec := range e.specs {
+		if spec.ShardFor(total) == index {
+			spec.shard = index
+			specs = append(specs, spec)
+			names = append(names, e.names[i])
+		}
+	}
+	e.specs = specs
+	e.names = names
+}
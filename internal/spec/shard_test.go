@@ -0,0 +1,77 @@
+package spec_test
+
+import (
+	"fmt"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/ginkgo/internal/spec"
+	. "github.com/hackrish007/gomega"
+
+	"github.com/hackrish007/ginkgo/internal/codelocation"
+	"github.com/hackrish007/ginkgo/internal/containernode"
+	"github.com/hackrish007/ginkgo/internal/leafnodes"
+)
+
+var _ = Describe("Sharding", func() {
+	newSpec := func(text string) *Spec {
+		subject := leafnodes.NewItNode(text, func() {}, noneFlag, codelocation.New(0), 0, nil, 0)
+		return New(subject, []*containernode.ContainerNode{}, false)
+	}
+
+	newSpecs := func(texts []string) *Specs {
+		specs := []*Spec{}
+		for _, text := range texts {
+			specs = append(specs, newSpec(text))
+		}
+		return NewSpecs(specs)
+	}
+
+	var texts []string
+
+	BeforeEach(func() {
+		texts = []string{}
+		for i := 0; i < 100; i++ {
+			texts = append(texts, fmt.Sprintf("spec %d", i))
+		}
+	})
+
+	It("should assign each spec to the same shard, regardless of the other specs", func() {
+		Ω(newSpec("spec 17").ShardFor(4)).Should(Equal(newSpec("spec 17").ShardFor(4)))
+		Ω(newSpec("spec 17").ShardFor(4)).Should(BeNumerically(">=", 1))
+		Ω(newSpec("spec 17").ShardFor(4)).Should(BeNumerically("<=", 4))
+	})
+
+	It("should partition the specs across the shards, running each exactly once", func() {
+		seen := map[string]int{}
+		for shard := 1; shard <= 4; shard++ {
+			specs := newSpecs(texts)
+			specs.ApplySharding(shard, 4)
+			Ω(specs.Specs()).ShouldNot(BeEmpty())
+			for _, spec := range specs.Specs() {
+				Ω(spec.Summary("suite").Shard).Should(Equal(shard))
+				seen[spec.ConcatenatedString()]++
+			}
+		}
+		Ω(seen).Should(HaveLen(len(texts)))
+		for _, count := range seen {
+			Ω(count).Should(Equal(1))
+		}
+	})
+
+	It("should not depend on the order of the specs", func() {
+		reversed := []string{}
+		for i := len(texts) - 1; i >= 0; i-- {
+			reversed = append(reversed, texts[i])
+		}
+
+		specs := newSpecs(texts)
+		specs.ApplySharding(2, 3)
+		reversedSpecs := newSpecs(reversed)
+		reversedSpecs.ApplySharding(2, 3)
+
+		Ω(reversedSpecs.Specs()).Should(HaveLen(len(specs.Specs())))
+		for _, spec := range reversedSpecs.Specs() {
+			Ω(spec.ShardFor(3)).Should(Equal(2))
+		}
+	})
+})
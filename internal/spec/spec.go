@@ -13,6 +13,7 @@ import (
 )
 
 type Spec struct {
+	id               string
 	subject          leafnodes.SubjectNode
 	focused          bool
 	announceProgress bool
@@ -25,6 +26,11 @@ type Spec struct {
 	failure          types.SpecFailure
 	previousFailures bool
 
+	quarantined      bool
+	quarantineReason string
+
+	shard int
+
 	stateMutex *sync.Mutex
 }
 
@@ -45,6 +51,15 @@ func New(subject leafnodes.SubjectNode, containers []*containernode.ContainerNod
 	return spec
 }
 
+// SetID sets the spec's stable identifier, as computed by the specid package
+func (spec *Spec) SetID(id string) {
+	spec.id = id
+}
+
+func (spec *Spec) ID() string {
+	return spec.id
+}
+
 func (spec *Spec) processFlag(flag types.FlagType) {
 	if flag == types.FlagTypeFocused {
 		spec.focused = true
@@ -57,6 +72,16 @@ func (spec *Spec) Skip() {
 	spec.setState(types.SpecStateSkipped)
 }
 
+// Quarantine marks the spec as known to be flaky.  Quarantined specs run as usual but their failures do not fail the suite.
+func (spec *Spec) Quarantine(reason string) {
+	spec.quarantined = true
+	spec.quarantineReason = reason
+}
+
+func (spec *Spec) Quarantined() bool {
+	return spec.quarantined
+}
+
 func (spec *Spec) Failed() bool {
 	return spec.getState() == types.SpecStateFailed || spec.getState() == types.SpecStatePanicked || spec.getState() == types.SpecStateTimedOut
 }
@@ -103,6 +128,7 @@ func (spec *Spec) Summary(suiteID string) *types.SpecSummary {
 	}
 
 	return &types.SpecSummary{
+		ID:                     spec.id,
 		IsMeasurement:          spec.IsMeasurement(),
 		NumberOfSamples:        spec.subject.Samples(),
 		ComponentTexts:         componentTexts,
@@ -112,6 +138,10 @@ func (spec *Spec) Summary(suiteID string) *types.SpecSummary {
 		Failure:                spec.failure,
 		Measurements:           spec.measurementsReport(),
 		SuiteID:                suiteID,
+		Quarantined:            spec.quarantined,
+		QuarantineReason:       spec.quarantineReason,
+		Shard:                  spec.shard,
+		StartTime:              spec.startTime,
 	}
 }
 
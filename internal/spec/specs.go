@@ -13,6 +13,8 @@ type Specs struct {
 
 	hasProgrammaticFocus bool
 	RegexScansFilePath   bool
+	FocusFiles           []FileFilter
+	SkipFiles            []FileFilter
 }
 
 func NewSpecs(specs []*Spec) *Specs {
@@ -48,7 +50,7 @@ func (e *Specs) Shuffle(r *rand.Rand) {
 }
 
 func (e *Specs) ApplyFocus(description string, focus, skip []string) {
-	if len(focus)+len(skip) == 0 {
+	if len(focus)+len(skip)+len(e.FocusFiles)+len(e.SkipFiles) == 0 {
 		e.applyProgrammaticFocus()
 	} else {
 		e.applyRegExpFocusAndSkip(description, focus, skip)
@@ -114,6 +116,14 @@ func (e *Specs) applyRegExpFocusAndSkip(description string, focus, skip []string
 			matchesSkip = skipFilter.Match(toMatch)
 		}
 
+		if len(e.FocusFiles) > 0 {
+			matchesFocus = matchesFocus && spec.MatchesFileFilters(e.FocusFiles)
+		}
+
+		if len(e.SkipFiles) > 0 {
+			matchesSkip = matchesSkip || spec.MatchesFileFilters(e.SkipFiles)
+		}
+
 		if !matchesFocus || matchesSkip {
 			spec.Skip()
 		}
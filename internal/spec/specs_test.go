@@ -121,18 +121,26 @@ var _ = Describe("Specs", func() {
 
 	Describe("Applying focus/skip", func() {
 		var (
-			description string
-			focus, skip []string
+			description           string
+			focus, skip           []string
+			focusFiles, skipFiles []string
 		)
 
 		BeforeEach(func() {
 			description = ""
 			focus = []string{}
 			skip = []string{}
+			focusFiles = []string{}
+			skipFiles = []string{}
 		})
 
 		JustBeforeEach(func() {
 			specs = newSpecs("A1", focusedFlag, "A2", noneFlag, "B1", focusedFlag, "B2", pendingFlag)
+			var err error
+			specs.FocusFiles, err = ParseFileFilters(focusFiles)
+			Ω(err).ShouldNot(HaveOccurred())
+			specs.SkipFiles, err = ParseFileFilters(skipFiles)
+			Ω(err).ShouldNot(HaveOccurred())
 			specs.ApplyFocus(description, focus, skip)
 		})
 
@@ -234,6 +242,56 @@ var _ = Describe("Specs", func() {
 				Ω(specs.HasProgrammaticFocus()).Should(BeFalse())
 			})
 		})
+		Context("with a focus file", func() {
+			BeforeEach(func() {
+				focusFiles = []string{"specs_test.go"}
+			})
+
+			It("should override the programmatic focus", func() {
+				Ω(willRunTexts(specs)).Should(Equal([]string{"A1", "A2", "B1"}))
+				Ω(skippedTexts(specs)).Should(BeEmpty())
+				Ω(pendingTexts(specs)).Should(Equal([]string{"B2"}))
+			})
+
+			It("should not report as having programmatic specs", func() {
+				Ω(specs.HasProgrammaticFocus()).Should(BeFalse())
+			})
+		})
+
+		Context("with a focus file that matches nothing", func() {
+			BeforeEach(func() {
+				focusFiles = []string{"other_test.go"}
+			})
+
+			It("should skip everything", func() {
+				Ω(willRunTexts(specs)).Should(BeEmpty())
+				Ω(skippedTexts(specs)).Should(Equal([]string{"A1", "A2", "B1", "B2"}))
+			})
+		})
+
+		Context("with both a focus file and a focus regexp", func() {
+			BeforeEach(func() {
+				focusFiles = []string{"specs_test.go"}
+				focus = []string{"A"}
+			})
+
+			It("should AND the two", func() {
+				Ω(willRunTexts(specs)).Should(Equal([]string{"A1", "A2"}))
+				Ω(skippedTexts(specs)).Should(Equal([]string{"B1", "B2"}))
+			})
+		})
+
+		Context("with a skip file", func() {
+			BeforeEach(func() {
+				skipFiles = []string{"specs_test.go"}
+			})
+
+			It("should skip the specs in the file", func() {
+				Ω(willRunTexts(specs)).Should(BeEmpty())
+				Ω(skippedTexts(specs)).Should(Equal([]string{"A1", "A2", "B1", "B2"}))
+				Ω(pendingTexts(specs)).Should(BeEmpty())
+			})
+		})
 	})
 
 	Describe("With a focused spec within a pending context and a pending spec within a focused context", func() {
@@ -1,49 +1,114 @@
 package spec_iterator
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
 	"github.com/hackrish007/ginkgo/internal/spec"
+	"github.com/hackrish007/ginkgo/internal/transport"
 )
 
 type ParallelIterator struct {
 	specs  []*spec.Spec
 	host   string
 	client *http.Client
+
+	ScheduleByDuration bool
+	scheduleRegistered bool
+
+	batch []int
+
+	//Node, if set, identifies this node to the server so that it can hand out batches of specs and tell which spec the node is running should the node crash
+	Node int
 }
 
 func NewParallelIterator(specs []*spec.Spec, host string) *ParallelIterator {
+	client, baseURL := transport.Client(host)
 	return &ParallelIterator{
 		specs:  specs,
-		host:   host,
-		client: &http.Client{},
+		host:   baseURL,
+		client: client,
 	}
 }
 
 func (s *ParallelIterator) Next() (*spec.Spec, error) {
-	resp, err := s.client.Get(s.host + "/counter")
+	if (s.ScheduleByDuration || s.Node > 0) && !s.scheduleRegistered {
+		err := s.registerSchedule()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(s.batch) == 0 {
+		err := s.leaseBatch()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(s.batch) == 0 || s.batch[0] >= len(s.specs) {
+		s.batch = nil
+		return nil, ErrClosed
+	}
+
+	index := s.batch[0]
+	s.batch = s.batch[1:]
+	return s.specs[index], nil
+}
+
+//leaseBatch fetches the next batch of specs to run from the server.  An empty batch means that there are no more specs to run.
+func (s *ParallelIterator) leaseBatch() error {
+	batchURL := s.host + "/batch"
+	if s.Node > 0 {
+		batchURL = fmt.Sprintf("%s?node=%d", batchURL, s.Node)
+	}
+	resp, err := s.client.Get(batchURL)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
 	}
 
-	var counter Counter
-	err = json.NewDecoder(resp.Body).Decode(&counter)
+	var batch Batch
+	err = json.NewDecoder(resp.Body).Decode(&batch)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if counter.Index >= len(s.specs) {
-		return nil, ErrClosed
+	s.batch = batch.Indices
+	return nil
+}
+
+//registerSchedule sends the specs to the server so that it can size batches, hand specs out longest-first and name the spec a crashed node was running.
+//Every node sends the same list; the server only uses the first one it receives.
+func (s *ParallelIterator) registerSchedule() error {
+	schedule := make([]ScheduledSpec, len(s.specs))
+	for i, spec := range s.specs {
+		schedule[i] = ScheduledSpec{
+			ID:      spec.ID(),
+			Name:    spec.ConcatenatedString(),
+			WillRun: !spec.Skipped() && !spec.Pending(),
+		}
+	}
+
+	encoded, _ := json.Marshal(schedule)
+	resp, err := s.client.Post(s.host+"/schedule", "application/json", bytes.NewBuffer(encoded))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
 	}
 
-	return s.specs[counter.Index], nil
+	s.scheduleRegistered = true
+	return nil
 }
 
 func (s *ParallelIterator) NumberOfSpecsPriorToIteration() int {
@@ -1,7 +1,11 @@
 package spec_iterator_test
 
 import (
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
 
 	. "github.com/hackrish007/ginkgo/internal/spec_iterator"
 	"github.com/hackrish007/gomega/ghttp"
@@ -9,7 +13,9 @@ import (
 	"github.com/hackrish007/ginkgo/internal/codelocation"
 	"github.com/hackrish007/ginkgo/internal/containernode"
 	"github.com/hackrish007/ginkgo/internal/leafnodes"
+	"github.com/hackrish007/ginkgo/internal/remote"
 	"github.com/hackrish007/ginkgo/internal/spec"
+	"github.com/hackrish007/ginkgo/reporters"
 	"github.com/hackrish007/ginkgo/types"
 
 	. "github.com/hackrish007/ginkgo"
@@ -64,10 +70,10 @@ var _ = Describe("ParallelSpecIterator", func() {
 		Describe("when the server returns well-formed responses", func() {
 			BeforeEach(func() {
 				server.AppendHandlers(
-					ghttp.RespondWithJSONEncoded(http.StatusOK, Counter{Index: 0}),
-					ghttp.RespondWithJSONEncoded(http.StatusOK, Counter{Index: 1}),
-					ghttp.RespondWithJSONEncoded(http.StatusOK, Counter{Index: 3}),
-					ghttp.RespondWithJSONEncoded(http.StatusOK, Counter{Index: 4}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{0}}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{1}}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{3}}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{4}}),
 				)
 			})
 
@@ -81,6 +87,77 @@ var _ = Describe("ParallelSpecIterator", func() {
 			})
 		})
 
+		Describe("when the server hands out batches of specs", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/batch"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{2, 0, 3}}),
+					),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{1}}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{}}),
+				)
+			})
+
+			It("should run each batch in order before fetching the next one", func() {
+				Ω(iterator.Next()).Should(Equal(specs[2]))
+				Ω(iterator.Next()).Should(Equal(specs[0]))
+				Ω(iterator.Next()).Should(Equal(specs[3]))
+				Ω(server.ReceivedRequests()).Should(HaveLen(1))
+
+				Ω(iterator.Next()).Should(Equal(specs[1]))
+				spec, err := iterator.Next()
+				Ω(spec).Should(BeNil())
+				Ω(err).Should(MatchError(ErrClosed))
+				Ω(server.ReceivedRequests()).Should(HaveLen(3))
+			})
+		})
+
+		Describe("when scheduling by duration", func() {
+			BeforeEach(func() {
+				iterator.ScheduleByDuration = true
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/schedule"),
+						ghttp.VerifyJSONRepresenting([]ScheduledSpec{
+							{Name: "A", WillRun: false},
+							{Name: "B", WillRun: true},
+							{Name: "C", WillRun: true},
+							{Name: "D", WillRun: false},
+						}),
+					),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{2}}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{4}}),
+				)
+			})
+
+			It("should register the specs with the server before fetching the first one", func() {
+				Ω(iterator.Next()).Should(Equal(specs[2]))
+				spec, err := iterator.Next()
+				Ω(spec).Should(BeNil())
+				Ω(err).Should(MatchError(ErrClosed))
+				Ω(server.ReceivedRequests()).Should(HaveLen(3))
+			})
+		})
+
+		Describe("when identifying the node to the server", func() {
+			BeforeEach(func() {
+				iterator.Node = 2
+				server.AppendHandlers(
+					ghttp.VerifyRequest("POST", "/schedule"),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/batch", "node=2"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, Batch{Indices: []int{1}}),
+					),
+				)
+			})
+
+			It("should register the specs and identify the node when fetching specs", func() {
+				Ω(iterator.Next()).Should(Equal(specs[1]))
+				Ω(server.ReceivedRequests()).Should(HaveLen(2))
+			})
+		})
+
 		Describe("when the server 404s", func() {
 			BeforeEach(func() {
 				server.AppendHandlers(
@@ -109,4 +186,65 @@ var _ = Describe("ParallelSpecIterator", func() {
 			})
 		})
 	})
+
+	Describe("throughput against a real server", func() {
+		var specs []*spec.Spec
+
+		BeforeEach(func() {
+			specs = make([]*spec.Spec, 2000)
+			for i := range specs {
+				specs[i] = newSpec(fmt.Sprintf("spec %d", i), types.FlagTypeNone)
+			}
+		})
+
+		//drain runs every spec through a single node of a four node run and returns the number of specs handed out per second, along with the number of requests the node made
+		drain := func(b Benchmarker, name string, withReporters bool) (float64, int) {
+			server, err := remote.NewServer(4)
+			Ω(err).ShouldNot(HaveOccurred())
+			if withReporters {
+				server.RegisterReporters(reporters.NewFakeReporter())
+			}
+			server.Start()
+			defer server.Close()
+
+			target, err := url.Parse(server.Address())
+			Ω(err).ShouldNot(HaveOccurred())
+			proxy := httputil.NewSingleHostReverseProxy(target)
+			requests := 0
+			counter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/counter" || r.URL.Path == "/batch" {
+					requests++
+				}
+				proxy.ServeHTTP(w, r)
+			}))
+			defer counter.Close()
+
+			iterator := NewParallelIterator(specs, counter.URL)
+			iterator.Node = 1
+
+			n := 0
+			runtime := b.Time(name, func() {
+				for {
+					_, err := iterator.Next()
+					if err == ErrClosed {
+						break
+					}
+					Ω(err).ShouldNot(HaveOccurred())
+					n++
+				}
+			})
+			Ω(n).Should(Equal(len(specs)))
+			return float64(n) / runtime.Seconds(), requests
+		}
+
+		Measure("should hand out specs in far fewer requests in batches than one at a time", func(b Benchmarker) {
+			oneAtATime, oneAtATimeRequests := drain(b, "one at a time", false)
+			batched, batchedRequests := drain(b, "in batches", true)
+			b.RecordValueWithPrecision("specs per second handed out one at a time", oneAtATime, "specs/s", 0)
+			b.RecordValueWithPrecision("specs per second handed out in batches", batched, "specs/s", 0)
+
+			Ω(oneAtATimeRequests).Should(BeNumerically(">", len(specs)))
+			Ω(batchedRequests).Should(BeNumerically("<", len(specs)/10))
+		}, 3)
+	})
 })
@@ -18,3 +18,14 @@ type SpecIterator interface {
 type Counter struct {
 	Index int `json:"index"`
 }
+
+//Batch is a set of specs, identified by their indices, leased to a node.  The node runs them in the order given.
+type Batch struct {
+	Indices []int `json:"indices"`
+}
+
+type ScheduledSpec struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	WillRun bool   `json:"will-run"`
+}
@@ -0,0 +1,60 @@
+/*
+Package specid computes the stable identifiers Ginkgo assigns to specs.
+
+A spec's identifier is the name of the file it is defined in, followed by the texts of its containers and of the spec itself, separated by slashes:
+
+	books_test.go:Book/Categorizing book length/When the book has more than 300 pages
+
+Table entries are identified by their index within their table rather than by their description, which may be generated at runtime:
+
+	books_test.go:Book/Categorizing books by length/[2]
+
+Slashes and backslashes in texts are escaped with a backslash.  When several specs share an identifier, the second and subsequent specs, in the order in which they are defined, are disambiguated with a ~2, ~3, ... suffix.
+
+Both the suite, when it constructs the tree of specs, and `ginkgo outline`, when it parses a source file, use this package so that they agree on identifiers.
+*/
+package specid
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+//NotATableEntry is passed to New for specs that were not generated by a table entry
+const NotATableEntry = -1
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, `/`, `\/`)
+
+//New returns the identifier of a spec defined in fileName.  texts holds the texts of the spec's containers, outermost first, followed by the spec's own text.
+//For table entries, entryIndex is the zero-based index of the entry within its table and replaces the spec's own text.
+func New(fileName string, texts []string, entryIndex int) string {
+	components := make([]string, len(texts))
+	for i, text := range texts {
+		components[i] = textEscaper.Replace(text)
+	}
+	if entryIndex != NotATableEntry && len(components) > 0 {
+		components[len(components)-1] = fmt.Sprintf("[%d]", entryIndex)
+	}
+	return filepath.Base(fileName) + ":" + strings.Join(components, "/")
+}
+
+//Disambiguator makes identifiers unique by suffixing repeated identifiers with ~2, ~3, ...
+type Disambiguator struct {
+	counts map[string]int
+}
+
+func NewDisambiguator() *Disambiguator {
+	return &Disambiguator{
+		counts: map[string]int{},
+	}
+}
+
+//Disambiguate must be called with every identifier, in the order in which the specs are defined
+func (d *Disambiguator) Disambiguate(id string) string {
+	d.counts[id]++
+	if d.counts[id] == 1 {
+		return id
+	}
+	return fmt.Sprintf("%s~%d", id, d.counts[id])
+}
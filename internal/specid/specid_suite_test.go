@@ -0,0 +1,13 @@
+package specid_test
+
+import (
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/gomega"
+
+	"testing"
+)
+
+func TestSpecID(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SpecID Suite")
+}
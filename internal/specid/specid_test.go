@@ -0,0 +1,37 @@
+package specid_test
+
+import (
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/gomega"
+
+	"github.com/hackrish007/ginkgo/internal/specid"
+)
+
+var _ = Describe("Spec IDs", func() {
+	Describe("New", func() {
+		It("joins the file's base name with the container and spec texts", func() {
+			id := specid.New("/path/to/books_test.go", []string{"Book", "when long", "is long"}, specid.NotATableEntry)
+			Ω(id).Should(Equal("books_test.go:Book/when long/is long"))
+		})
+
+		It("escapes slashes and backslashes in texts", func() {
+			id := specid.New("books_test.go", []string{"a/b", `c\d`}, specid.NotATableEntry)
+			Ω(id).Should(Equal(`books_test.go:a\/b/c\\d`))
+		})
+
+		It("identifies table entries by their index", func() {
+			id := specid.New("books_test.go", []string{"Book", "Categorizing", "Entry: 300 pages"}, 2)
+			Ω(id).Should(Equal("books_test.go:Book/Categorizing/[2]"))
+		})
+	})
+
+	Describe("Disambiguator", func() {
+		It("suffixes repeated identifiers in the order they are seen", func() {
+			d := specid.NewDisambiguator()
+			Ω(d.Disambiguate("a")).Should(Equal("a"))
+			Ω(d.Disambiguate("b")).Should(Equal("b"))
+			Ω(d.Disambiguate("a")).Should(Equal("a~2"))
+			Ω(d.Disambiguate("a")).Should(Equal("a~3"))
+		})
+	})
+})
@@ -0,0 +1,24 @@
+package specrunner_test
+
+import "fmt"
+
+type fakeOutputInterceptor struct {
+	Starts int
+	Stops  int
+	Names  []string
+}
+
+func (interceptor *fakeOutputInterceptor) StartInterceptingOutput() error {
+	interceptor.Starts++
+	return nil
+}
+
+func (interceptor *fakeOutputInterceptor) StopInterceptingAndReturnOutput() (string, error) {
+	interceptor.Stops++
+	return fmt.Sprintf("output %d", interceptor.Stops), nil
+}
+
+func (interceptor *fakeOutputInterceptor) StopInterceptingAndRetainOutput(name string) (string, error) {
+	interceptor.Names = append(interceptor.Names, name)
+	return interceptor.StopInterceptingAndReturnOutput()
+}
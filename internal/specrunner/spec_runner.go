@@ -33,6 +33,16 @@ type SpecRunner struct {
 	interrupted     bool
 	processedSpecs  []*spec.Spec
 	lock            *sync.Mutex
+
+	outputInterceptor OutputInterceptor
+	intercepting      bool
+}
+
+//OutputInterceptor captures everything the process writes to stdout and stderr.  See remote.OutputInterceptor.
+type OutputInterceptor interface {
+	StartInterceptingOutput() error
+	StopInterceptingAndReturnOutput() (string, error)
+	StopInterceptingAndRetainOutput(name string) (string, error)
 }
 
 func New(description string, beforeSuiteNode leafnodes.SuiteNode, iterator spec_iterator.SpecIterator, afterSuiteNode leafnodes.SuiteNode, reporters []reporters.Reporter, writer Writer.WriterInterface, config config.GinkgoConfigType) *SpecRunner {
@@ -49,6 +59,12 @@ func New(description string, beforeSuiteNode leafnodes.SuiteNode, iterator spec_
 	}
 }
 
+//InterceptOutput makes the runner capture stdout and stderr while each spec and setup node runs.
+//The output is attached to the spec's or setup node's summary and emitted only if it fails.
+func (runner *SpecRunner) InterceptOutput(interceptor OutputInterceptor) {
+	runner.outputInterceptor = interceptor
+}
+
 func (runner *SpecRunner) Run() bool {
 	if runner.config.DryRun {
 		runner.performDryRun()
@@ -101,7 +117,7 @@ func (runner *SpecRunner) performDryRun() {
 		if summary.State == types.SpecStateInvalid {
 			summary.State = types.SpecStatePassed
 		}
-		runner.reportSpecDidComplete(summary, false)
+		runner.reportSpecDidComplete(summary, false, 1)
 	}
 
 	if runner.afterSuiteNode != nil {
@@ -119,12 +135,17 @@ func (runner *SpecRunner) runBeforeSuite() bool {
 	}
 
 	runner.writer.Truncate()
+	runner.startInterceptingOutput()
 	conf := runner.config
 	passed := runner.beforeSuiteNode.Run(conf.ParallelNode, conf.ParallelTotal, conf.SyncHost)
+	output := runner.stopInterceptingOutput(fmt.Sprintf("BeforeSuite-node-%d", conf.ParallelNode))
 	if !passed {
+		runner.bufferInterceptedOutput(output)
 		runner.writer.DumpOut()
 	}
-	runner.reportBeforeSuite(runner.beforeSuiteNode.Summary())
+	summary := runner.beforeSuiteNode.Summary()
+	summary.CapturedOutput = output
+	runner.reportBeforeSuite(summary)
 	return passed
 }
 
@@ -134,12 +155,17 @@ func (runner *SpecRunner) runAfterSuite() bool {
 	}
 
 	runner.writer.Truncate()
+	runner.startInterceptingOutput()
 	conf := runner.config
 	passed := runner.afterSuiteNode.Run(conf.ParallelNode, conf.ParallelTotal, conf.SyncHost)
+	output := runner.stopInterceptingOutput(fmt.Sprintf("AfterSuite-node-%d", conf.ParallelNode))
 	if !passed {
+		runner.bufferInterceptedOutput(output)
 		runner.writer.DumpOut()
 	}
-	runner.reportAfterSuite(runner.afterSuiteNode.Summary())
+	summary := runner.afterSuiteNode.Summary()
+	summary.CapturedOutput = output
+	runner.reportAfterSuite(summary)
 	return passed
 }
 
@@ -167,19 +193,19 @@ func (runner *SpecRunner) runSpecs() bool {
 		}
 
 		if !spec.Skipped() && !spec.Pending() {
-			if passed := runner.runSpec(spec); !passed {
+			if passed := runner.runSpec(spec); !passed && !spec.Quarantined() {
 				suiteFailed = true
 			}
 		} else if spec.Pending() && runner.config.FailOnPending {
 			runner.reportSpecWillRun(spec.Summary(runner.suiteID))
 			suiteFailed = true
-			runner.reportSpecDidComplete(spec.Summary(runner.suiteID), spec.Failed())
+			runner.reportSpecDidComplete(spec.Summary(runner.suiteID), spec.Failed(), 1)
 		} else {
 			runner.reportSpecWillRun(spec.Summary(runner.suiteID))
-			runner.reportSpecDidComplete(spec.Summary(runner.suiteID), spec.Failed())
+			runner.reportSpecDidComplete(spec.Summary(runner.suiteID), spec.Failed(), 1)
 		}
 
-		if spec.Failed() && runner.config.FailFast {
+		if spec.Failed() && !spec.Quarantined() && runner.config.FailFast {
 			skipRemainingSpecs = true
 		}
 	}
@@ -196,10 +222,11 @@ func (runner *SpecRunner) runSpec(spec *spec.Spec) (passed bool) {
 
 	for i := 0; i < maxAttempts; i++ {
 		runner.reportSpecWillRun(spec.Summary(runner.suiteID))
+		runner.startInterceptingOutput()
 		runner.runningSpec = spec
 		spec.Run(runner.writer)
 		runner.runningSpec = nil
-		runner.reportSpecDidComplete(spec.Summary(runner.suiteID), spec.Failed())
+		runner.reportSpecDidComplete(spec.Summary(runner.suiteID), spec.Failed(), i+1)
 		if !spec.Failed() {
 			return true
 		}
@@ -212,7 +239,9 @@ func (runner *SpecRunner) CurrentSpecSummary() (*types.SpecSummary, bool) {
 		return nil, false
 	}
 
-	return runner.runningSpec.Summary(runner.suiteID), true
+	summary := runner.runningSpec.Summary(runner.suiteID)
+	summary.ParallelNode = runner.config.ParallelNode
+	return summary, true
 }
 
 func (runner *SpecRunner) registerForInterrupts(signalRegistered chan struct{}) {
@@ -224,6 +253,7 @@ func (runner *SpecRunner) registerForInterrupts(signalRegistered chan struct{})
 	signal.Stop(c)
 	runner.markInterrupted()
 	go runner.registerForHardInterrupts()
+	runner.bufferInterceptedOutput(runner.stopInterceptingOutput(fmt.Sprintf("Interrupted-node-%d", runner.config.ParallelNode)))
 	runner.writer.DumpOutWithHeader(`
 Received interrupt.  Emitting contents of GinkgoWriter...
 ---------------------------------------------------------
@@ -280,12 +310,14 @@ func (runner *SpecRunner) reportSuiteWillBegin() {
 }
 
 func (runner *SpecRunner) reportBeforeSuite(summary *types.SetupSummary) {
+	summary.ParallelNode = runner.config.ParallelNode
 	for _, reporter := range runner.reporters {
 		reporter.BeforeSuiteDidRun(summary)
 	}
 }
 
 func (runner *SpecRunner) reportAfterSuite(summary *types.SetupSummary) {
+	summary.ParallelNode = runner.config.ParallelNode
 	for _, reporter := range runner.reporters {
 		reporter.AfterSuiteDidRun(summary)
 	}
@@ -293,16 +325,24 @@ func (runner *SpecRunner) reportAfterSuite(summary *types.SetupSummary) {
 
 func (runner *SpecRunner) reportSpecWillRun(summary *types.SpecSummary) {
 	runner.writer.Truncate()
+	summary.ParallelNode = runner.config.ParallelNode
 
 	for _, reporter := range runner.reporters {
 		reporter.SpecWillRun(summary)
 	}
 }
 
-func (runner *SpecRunner) reportSpecDidComplete(summary *types.SpecSummary, failed bool) {
+func (runner *SpecRunner) reportSpecDidComplete(summary *types.SpecSummary, failed bool, attempt int) {
+	name := Writer.SpecOutputName(summary.ID, runner.config.ParallelNode, attempt)
+	//the GinkgoWriter saves its own full output under the spec's name
+	output := runner.stopInterceptingOutput(name + "-intercepted")
 	if len(summary.CapturedOutput) == 0 {
-		summary.CapturedOutput = string(runner.writer.Bytes())
+		//the intercepted output now follows what the spec wrote to the GinkgoWriter in its buffer, and is emitted along with it should the spec fail
+		summary.CapturedOutput = runner.retainBuffered([]byte(output), name)
+	} else if failed {
+		runner.bufferInterceptedOutput(output)
 	}
+	summary.ParallelNode = runner.config.ParallelNode
 	for i := len(runner.reporters) - 1; i >= 1; i-- {
 		runner.reporters[i].SpecDidComplete(summary)
 	}
@@ -314,6 +354,43 @@ func (runner *SpecRunner) reportSpecDidComplete(summary *types.SpecSummary, fail
 	runner.reporters[0].SpecDidComplete(summary)
 }
 
+func (runner *SpecRunner) startInterceptingOutput() {
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+	if runner.outputInterceptor == nil || runner.intercepting {
+		return
+	}
+	runner.intercepting = runner.outputInterceptor.StartInterceptingOutput() == nil
+}
+
+//stopInterceptingOutput returns what was intercepted, as far as the retention policy retains it.  name names the file holding the full output, should the policy save it.
+func (runner *SpecRunner) stopInterceptingOutput(name string) string {
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+	if !runner.intercepting {
+		return ""
+	}
+	runner.intercepting = false
+	output, _ := runner.outputInterceptor.StopInterceptingAndRetainOutput(name)
+	return output
+}
+
+//retainBuffered applies the GinkgoWriter's retention policy to what the spec wrote to it followed by output.  Nodes that report to the CLI leave that to the ForwardingReporter,
+//which replaces the output with what it intercepted, so that each spec's output is retained, and its full output saved, only once.
+func (runner *SpecRunner) retainBuffered(output []byte, name string) string {
+	if runner.config.StreamHost != "" {
+		return string(append(runner.writer.Bytes(), output...))
+	}
+	return string(runner.writer.RetainBuffered(output, name))
+}
+
+//bufferInterceptedOutput adds intercepted output to the GinkgoWriter so that it is emitted along with the GinkgoWriter's contents
+func (runner *SpecRunner) bufferInterceptedOutput(output string) {
+	if output != "" {
+		runner.writer.Write([]byte(output))
+	}
+}
+
 func (runner *SpecRunner) reportSuiteDidEnd(success bool) {
 	summary := runner.suiteDidEndSummary(success)
 	summary.RunTime = time.Since(runner.startTime)
@@ -356,7 +433,11 @@ func (runner *SpecRunner) suiteDidEndSummary(success bool) *types.SuiteSummary {
 	})
 
 	numberOfFailedSpecs := runner.countSpecsThatRanSatisfying(func(ex *spec.Spec) bool {
-		return ex.Failed()
+		return ex.Failed() && !ex.Quarantined()
+	})
+
+	numberOfQuarantinedFailures := runner.countSpecsThatRanSatisfying(func(ex *spec.Spec) bool {
+		return ex.Failed() && ex.Quarantined()
 	})
 
 	if runner.beforeSuiteNode != nil && !runner.beforeSuiteNode.Passed() && !runner.config.DryRun {
@@ -381,6 +462,7 @@ func (runner *SpecRunner) suiteDidEndSummary(success bool) *types.SuiteSummary {
 		NumberOfPassedSpecs:                numberOfPassedSpecs,
 		NumberOfFailedSpecs:                numberOfFailedSpecs,
 		NumberOfFlakedSpecs:                numberOfFlakedSpecs,
+		NumberOfQuarantinedFailures:        numberOfQuarantinedFailures,
 	}
 }
 
@@ -407,5 +489,6 @@ func (runner *SpecRunner) suiteWillBeginSummary() *types.SuiteSummary {
 		NumberOfPassedSpecs:                -1,
 		NumberOfFailedSpecs:                -1,
 		NumberOfFlakedSpecs:                -1,
+		NumberOfQuarantinedFailures:        -1,
 	}
 }
@@ -677,6 +677,29 @@ var _ = Describe("Spec Runner", func() {
 			})
 		})
 
+		Context("when a quarantined test fails", func() {
+			BeforeEach(func() {
+				quarantined := newSpec("quarantined", noneFlag, true)
+				quarantined.Quarantine("ISSUE-123")
+				runner = newRunner(config.GinkgoConfigType{FailFast: true}, nil, nil, newSpec("passing", noneFlag, false), quarantined, newSpec("also passing", noneFlag, false))
+			})
+
+			It("should return true, report success and keep running", func() {
+				Ω(runner.Run()).Should(BeTrue())
+				Ω(reporter1.EndSummary.SuiteSucceeded).Should(BeTrue())
+				Ω(thingsThatRan).Should(Equal([]string{"passing", "quarantined", "also passing"}))
+			})
+
+			It("should report the failure as quarantined", func() {
+				runner.Run()
+				Ω(reporter1.SpecSummaries[1].State).Should(Equal(types.SpecStateFailed))
+				Ω(reporter1.SpecSummaries[1].HasQuarantinedFailure()).Should(BeTrue())
+				Ω(reporter1.SpecSummaries[1].QuarantineReason).Should(Equal("ISSUE-123"))
+				Ω(reporter1.EndSummary.NumberOfFailedSpecs).Should(Equal(0))
+				Ω(reporter1.EndSummary.NumberOfQuarantinedFailures).Should(Equal(1))
+			})
+		})
+
 		Context("when there is a pending test, but pendings count as failures", func() {
 			BeforeEach(func() {
 				runner = newRunner(config.GinkgoConfigType{FailOnPending: true}, nil, nil, newSpec("passing", noneFlag, false), newSpec("pending", pendingFlag, false))
@@ -720,14 +743,14 @@ var _ = Describe("Spec Runner", func() {
 				"R1.WillRun",
 				"R2.WillRun",
 				"A",
-				"BYTES",
+				"RETAIN_BUFFERED",
 				"R2.DidComplete",
 				"R1.DidComplete",
 				"TRUNCATE",
 				"R1.WillRun",
 				"R2.WillRun",
 				"B",
-				"BYTES",
+				"RETAIN_BUFFERED",
 				"R2.DidComplete",
 				"DUMP",
 				"R1.DidComplete",
@@ -735,7 +758,7 @@ var _ = Describe("Spec Runner", func() {
 				"R1.WillRun",
 				"R2.WillRun",
 				"C",
-				"BYTES",
+				"RETAIN_BUFFERED",
 				"R2.DidComplete",
 				"R1.DidComplete",
 			}))
@@ -767,6 +790,84 @@ var _ = Describe("Spec Runner", func() {
 		})
 	})
 
+	Describe("intercepting output", func() {
+		var interceptor *fakeOutputInterceptor
+
+		BeforeEach(func() {
+			interceptor = &fakeOutputInterceptor{}
+		})
+
+		It("should attach the output of each spec and setup node to its summary", func() {
+			runner = newRunner(
+				config.GinkgoConfigType{},
+				newBefSuite("BefSuite", false),
+				newAftSuite("AftSuite", false),
+				newSpec("A", noneFlag, false),
+				newSpec("pending", pendingFlag, false),
+				newSpec("B", noneFlag, true),
+			)
+			runner.InterceptOutput(interceptor)
+			runner.Run()
+
+			Ω(interceptor.Starts).Should(Equal(4))
+			Ω(interceptor.Stops).Should(Equal(4))
+			Ω(reporter1.BeforeSuiteSummary.CapturedOutput).Should(Equal("output 1"))
+			Ω(reporter1.SpecSummaries[0].CapturedOutput).Should(Equal("output 2"))
+			Ω(reporter1.SpecSummaries[1].CapturedOutput).Should(BeEmpty())
+			Ω(reporter1.SpecSummaries[2].CapturedOutput).Should(Equal("output 3"))
+			Ω(reporter1.AfterSuiteSummary.CapturedOutput).Should(Equal("output 4"))
+		})
+
+		It("should retain the intercepted output of each spec and setup node under its own name", func() {
+			runner = newRunner(
+				config.GinkgoConfigType{ParallelNode: 2},
+				newBefSuite("BefSuite", false),
+				newAftSuite("AftSuite", false),
+				newSpec("A", noneFlag, false),
+			)
+			runner.InterceptOutput(interceptor)
+			runner.Run()
+
+			id := reporter1.SpecSummaries[0].ID
+			Ω(interceptor.Names).Should(Equal([]string{"BeforeSuite-node-2", id + "-node-2-attempt-1-intercepted", "AfterSuite-node-2"}))
+		})
+
+		It("should not intercept output when no interceptor is set", func() {
+			runner = newRunner(config.GinkgoConfigType{}, nil, nil, newSpec("A", noneFlag, false))
+			runner.Run()
+
+			Ω(interceptor.Starts).Should(BeZero())
+			Ω(reporter1.SpecSummaries[0].CapturedOutput).Should(BeEmpty())
+		})
+	})
+
+	Describe("retaining output", func() {
+		It("should retain the output of each attempt at a spec under a name that includes the node and the attempt", func() {
+			runner = newRunner(
+				config.GinkgoConfigType{FlakeAttempts: 2, ParallelNode: 3},
+				newBefSuite("BefSuite", false),
+				nil,
+				newFlakySpec("A", noneFlag, 2),
+			)
+			runner.Run()
+
+			id := reporter1.SpecSummaries[0].ID
+			Ω(writer.Retained).Should(Equal([]string{id + "-node-3-attempt-1", id + "-node-3-attempt-2"}))
+		})
+
+		It("should leave retaining output to the reporter that reports to the CLI", func() {
+			runner = newRunner(
+				config.GinkgoConfigType{StreamHost: "127.0.0.1:7788", ParallelNode: 1},
+				newBefSuite("BefSuite", false),
+				nil,
+				newSpec("A", noneFlag, false),
+			)
+			runner.Run()
+
+			Ω(writer.Retained).Should(BeEmpty())
+		})
+	})
+
 	Describe("generating a suite id", func() {
 		It("should generate an id randomly", func() {
 			runnerA := newRunner(config.GinkgoConfigType{}, nil, nil)
@@ -1,8 +1,12 @@
 package suite
 
 import (
+	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/hackrish007/ginkgo/internal/spec_iterator"
@@ -12,7 +16,9 @@ import (
 	"github.com/hackrish007/ginkgo/internal/failer"
 	"github.com/hackrish007/ginkgo/internal/leafnodes"
 	"github.com/hackrish007/ginkgo/internal/spec"
+	"github.com/hackrish007/ginkgo/internal/specid"
 	"github.com/hackrish007/ginkgo/internal/specrunner"
+	"github.com/hackrish007/ginkgo/internal/transport"
 	"github.com/hackrish007/ginkgo/internal/writer"
 	"github.com/hackrish007/ginkgo/reporters"
 	"github.com/hackrish007/ginkgo/types"
@@ -42,6 +48,10 @@ type Suite struct {
 	failer              *failer.Failer
 	running             bool
 	expandTopLevelNodes bool
+	outputInterceptor   specrunner.OutputInterceptor
+
+	tableEntryIndices map[leafnodes.SubjectNode]int
+	specIDs           map[leafnodes.SubjectNode]string
 }
 
 func New(failer *failer.Failer) *Suite {
@@ -53,6 +63,7 @@ func New(failer *failer.Failer) *Suite {
 		failer:                 failer,
 		containerIndex:         1,
 		deferredContainerNodes: []deferredContainerNode{},
+		tableEntryIndices:      map[leafnodes.SubjectNode]int{},
 	}
 }
 
@@ -70,10 +81,40 @@ func (suite *Suite) Run(t ginkgoTestingT, description string, reporters []report
 		suite.PushContainerNode(deferredNode.text, deferredNode.body, deferredNode.flag, deferredNode.codeLocation)
 	}
 
+	suite.computeSpecIDs()
+
+	var quarantine []spec.QuarantineEntry
+	if config.Quarantine != "" {
+		var err error
+		quarantine, err = spec.LoadQuarantine(config.Quarantine)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			t.Fail()
+			return false, false
+		}
+	}
+
+	focusFiles, skipFiles, err := parseFileFilters(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		t.Fail()
+		return false, false
+	}
+
+	focusIDs, err := loadFocusIDs(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		t.Fail()
+		return false, false
+	}
+
 	r := rand.New(rand.NewSource(config.RandomSeed))
 	suite.topLevelContainer.Shuffle(r)
-	iterator, hasProgrammaticFocus := suite.generateSpecsIterator(description, config)
+	iterator, hasProgrammaticFocus := suite.generateSpecsIterator(description, focusFiles, skipFiles, focusIDs, quarantine, config)
 	suite.runner = specrunner.New(description, suite.beforeSuiteNode, iterator, suite.afterSuiteNode, reporters, writer, config)
+	if suite.outputInterceptor != nil {
+		suite.runner.InterceptOutput(suite.outputInterceptor)
+	}
 
 	suite.running = true
 	success := suite.runner.Run()
@@ -83,15 +124,84 @@ func (suite *Suite) Run(t ginkgoTestingT, description string, reporters []report
 	return success, hasProgrammaticFocus
 }
 
-func (suite *Suite) generateSpecsIterator(description string, config config.GinkgoConfigType) (spec_iterator.SpecIterator, bool) {
+// computeSpecIDs assigns each spec its stable identifier.  This must happen
+// before the tree is shuffled so that duplicates are disambiguated in the
+// order in which they are defined.
+func (suite *Suite) computeSpecIDs() {
+	suite.specIDs = map[leafnodes.SubjectNode]string{}
+	disambiguator := specid.NewDisambiguator()
+	for _, collatedNodes := range suite.topLevelContainer.Collate() {
+		texts := []string{}
+		for _, container := range collatedNodes.Containers[1:] {
+			texts = append(texts, container.Text())
+		}
+		texts = append(texts, collatedNodes.Subject.Text())
+
+		entryIndex, isTableEntry := suite.tableEntryIndices[collatedNodes.Subject]
+		if !isTableEntry {
+			entryIndex = specid.NotATableEntry
+		}
+
+		id := specid.New(collatedNodes.Subject.CodeLocation().FileName, texts, entryIndex)
+		suite.specIDs[collatedNodes.Subject] = disambiguator.Disambiguate(id)
+	}
+}
+
+// parseFileFilters parses the -focusFile and -skipFile values.  The flags
+// reject invalid filters, but configs can be built without going through them.
+func parseFileFilters(config config.GinkgoConfigType) ([]spec.FileFilter, []spec.FileFilter, error) {
+	focusFiles, err := spec.ParseFileFilters(config.FocusFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	skipFiles, err := spec.ParseFileFilters(config.SkipFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return focusFiles, skipFiles, nil
+}
+
+// loadFocusIDs returns the -focusID values along with the IDs listed, one per
+// line, in the -focusIDFile files.
+func loadFocusIDs(config config.GinkgoConfigType) ([]string, error) {
+	focusIDs := append([]string{}, config.FocusIDs...)
+	for _, path := range config.FocusIDFiles {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if id := strings.TrimSpace(line); id != "" {
+				focusIDs = append(focusIDs, id)
+			}
+		}
+	}
+	return focusIDs, nil
+}
+
+func (suite *Suite) generateSpecsIterator(description string, focusFiles, skipFiles []spec.FileFilter, focusIDs []string, quarantine []spec.QuarantineEntry, config config.GinkgoConfigType) (spec_iterator.SpecIterator, bool) {
 	specsSlice := []*spec.Spec{}
 	suite.topLevelContainer.BackPropagateProgrammaticFocus()
 	for _, collatedNodes := range suite.topLevelContainer.Collate() {
-		specsSlice = append(specsSlice, spec.New(collatedNodes.Subject, collatedNodes.Containers, config.EmitSpecProgress))
+		collatedSpec := spec.New(collatedNodes.Subject, collatedNodes.Containers, config.EmitSpecProgress)
+		collatedSpec.SetID(suite.specIDs[collatedNodes.Subject])
+		specsSlice = append(specsSlice, collatedSpec)
 	}
 
 	specs := spec.NewSpecs(specsSlice)
 	specs.RegexScansFilePath = config.RegexScansFilePath
+	specs.FocusFiles = focusFiles
+	specs.SkipFiles = skipFiles
+
+	// IDs are matched before sharding so that whether any spec has one of them
+	// doesn't depend on the shard.
+	if len(focusIDs) > 0 {
+		specs.ApplyIDFocus(focusIDs)
+	}
+
+	if config.ShardTotal > 1 {
+		specs.ApplySharding(config.ShardIndex, config.ShardTotal)
+	}
 
 	if config.RandomizeAllSpecs {
 		specs.Shuffle(rand.New(rand.NewSource(config.RandomSeed)))
@@ -99,6 +209,10 @@ func (suite *Suite) generateSpecsIterator(description string, config config.Gink
 
 	specs.ApplyFocus(description, config.FocusStrings, config.SkipStrings)
 
+	if len(quarantine) > 0 {
+		specs.ApplyQuarantine(quarantine, time.Now())
+	}
+
 	if config.SkipMeasurements {
 		specs.SkipMeasurements()
 	}
@@ -106,8 +220,12 @@ func (suite *Suite) generateSpecsIterator(description string, config config.Gink
 	var iterator spec_iterator.SpecIterator
 
 	if config.ParallelTotal > 1 {
-		iterator = spec_iterator.NewParallelIterator(specs.Specs(), config.SyncHost)
-		resp, err := http.Get(config.SyncHost + "/has-counter")
+		parallelIterator := spec_iterator.NewParallelIterator(specs.Specs(), config.SyncHost)
+		parallelIterator.ScheduleByDuration = config.ScheduleByDuration
+		parallelIterator.Node = config.ParallelNode
+		iterator = parallelIterator
+		client, baseURL := transport.Client(config.SyncHost)
+		resp, err := client.Get(baseURL + "/has-counter")
 		if err != nil || resp.StatusCode != http.StatusOK {
 			iterator = spec_iterator.NewShardedParallelIterator(specs.Specs(), config.ParallelTotal, config.ParallelNode)
 		}
@@ -118,6 +236,12 @@ func (suite *Suite) generateSpecsIterator(description string, config config.Gink
 	return iterator, specs.HasProgrammaticFocus()
 }
 
+// InterceptOutput makes the suite capture stdout and stderr while each spec
+// and setup node runs.  It must be called before Run.
+func (suite *Suite) InterceptOutput(interceptor specrunner.OutputInterceptor) {
+	suite.outputInterceptor = interceptor
+}
+
 func (suite *Suite) CurrentRunningSpecSummary() (*types.SpecSummary, bool) {
 	if !suite.running {
 		return nil, false
@@ -191,6 +315,16 @@ func (suite *Suite) PushItNode(text string, body interface{}, flag types.FlagTyp
 	suite.currentContainer.PushSubjectNode(leafnodes.NewItNode(text, body, flag, codeLocation, timeout, suite.failer, suite.containerIndex))
 }
 
+// PushTableEntryNode pushes the It generated for the entryIndex-th entry of a DescribeTable.
+func (suite *Suite) PushTableEntryNode(text string, body interface{}, flag types.FlagType, codeLocation types.CodeLocation, timeout time.Duration, entryIndex int) {
+	if suite.running {
+		suite.failer.Fail("You may only call Entry from within a DescribeTable", codeLocation)
+	}
+	subject := leafnodes.NewItNode(text, body, flag, codeLocation, timeout, suite.failer, suite.containerIndex)
+	suite.tableEntryIndices[subject] = entryIndex
+	suite.currentContainer.PushSubjectNode(subject)
+}
+
 func (suite *Suite) PushMeasureNode(text string, body interface{}, flag types.FlagType, codeLocation types.CodeLocation, samples int) {
 	if suite.running {
 		suite.failer.Fail("You may only call Measure from within a Describe, Context or When", codeLocation)
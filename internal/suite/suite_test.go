@@ -7,7 +7,9 @@ import (
 	. "github.com/hackrish007/ginkgo/internal/suite"
 	. "github.com/hackrish007/gomega"
 
+	"io/ioutil"
 	"math/rand"
+	"os"
 	"time"
 
 	"github.com/hackrish007/ginkgo/config"
@@ -45,6 +47,9 @@ var _ = Describe("Suite", func() {
 			parallelTotal        int
 			runResult            bool
 			hasProgrammaticFocus bool
+			quarantine           string
+			skipFiles            []string
+			focusIDFiles         []string
 		)
 
 		var f = func(runText string) func() {
@@ -59,6 +64,9 @@ var _ = Describe("Suite", func() {
 			parallelNode = 1
 			parallelTotal = 1
 			focusStrings = []string{}
+			quarantine = ""
+			skipFiles = nil
+			focusIDFiles = nil
 
 			runOrder = make([]string, 0)
 			specSuite.SetBeforeSuiteNode(f("BeforeSuite"), codelocation.New(0), 0)
@@ -94,6 +102,9 @@ var _ = Describe("Suite", func() {
 				FocusStrings:      focusStrings,
 				ParallelNode:      parallelNode,
 				ParallelTotal:     parallelTotal,
+				Quarantine:        quarantine,
+				SkipFiles:         skipFiles,
+				FocusIDFiles:      focusIDFiles,
 			})
 		})
 
@@ -203,6 +214,33 @@ var _ = Describe("Suite", func() {
 			})
 		})
 
+		Context("when reporting specs", func() {
+			BeforeEach(func() {
+				randomizeAllSpecs = true
+				specSuite.PushContainerNode("table", func() {
+					specSuite.PushTableEntryNode("entry a", f("entry a"), types.FlagTypeNone, codelocation.New(0), 0, 0)
+					specSuite.PushTableEntryNode("entry b", f("entry b"), types.FlagTypeNone, codelocation.New(0), 0, 1)
+				}, types.FlagTypeNone, codelocation.New(0))
+				specSuite.PushItNode("top level it", f("duplicate top IT"), types.FlagTypeNone, codelocation.New(0), 0)
+			})
+
+			It("assigns each spec a stable ID, independent of the spec order", func() {
+				ids := []string{}
+				for _, summary := range fakeR.SpecSummaries {
+					ids = append(ids, summary.ID)
+				}
+				Ω(ids).Should(ConsistOf(
+					"suite_test.go:container/it",
+					"suite_test.go:container/inner container/inner it",
+					"suite_test.go:container 2/it 2",
+					"suite_test.go:top level it",
+					"suite_test.go:table/[0]",
+					"suite_test.go:table/[1]",
+					"suite_test.go:top level it~2",
+				))
+			})
+		})
+
 		Context("with a programatically focused spec", func() {
 			BeforeEach(func() {
 				specSuite.PushItNode("focused it", f("focused it"), types.FlagTypeFocused, codelocation.New(0), 0)
@@ -238,6 +276,67 @@ var _ = Describe("Suite", func() {
 			})
 		})
 
+		Context("when the quarantine file can't be loaded", func() {
+			BeforeEach(func() {
+				quarantine = "/does/not/exist/quarantine.json"
+			})
+
+			It("fails without running anything", func() {
+				Ω(runResult).Should(BeFalse())
+				Ω(fakeT.didFail).Should(BeTrue())
+				Ω(runOrder).Should(BeEmpty())
+				Ω(fakeR.BeginSummary).Should(BeNil())
+			})
+		})
+
+		Context("when a file filter is invalid", func() {
+			BeforeEach(func() {
+				skipFiles = []string{"foo_test.go:20-12"}
+			})
+
+			It("fails without running anything", func() {
+				Ω(runResult).Should(BeFalse())
+				Ω(fakeT.didFail).Should(BeTrue())
+				Ω(runOrder).Should(BeEmpty())
+				Ω(fakeR.BeginSummary).Should(BeNil())
+			})
+		})
+
+		Context("when focusing on IDs listed in a file", func() {
+			BeforeEach(func() {
+				file, err := ioutil.TempFile("", "ginkgo-focus-ids")
+				Ω(err).ShouldNot(HaveOccurred())
+				file.WriteString("suite_test.go:container 2/it 2\n\nsuite_test.go:top level it\n")
+				file.Close()
+				focusIDFiles = []string{file.Name()}
+			})
+
+			AfterEach(func() {
+				os.Remove(focusIDFiles[0])
+			})
+
+			It("runs only the specs with those IDs", func() {
+				Ω(runOrder).Should(Equal([]string{
+					"BeforeSuite",
+					"top BE", "BE 2", "top JBE", "IT 2", "top AE",
+					"top BE", "top JBE", "top IT", "top AE",
+					"AfterSuite",
+				}))
+			})
+		})
+
+		Context("when a file of IDs to focus on can't be read", func() {
+			BeforeEach(func() {
+				focusIDFiles = []string{"/does/not/exist/ids"}
+			})
+
+			It("fails without running anything", func() {
+				Ω(runResult).Should(BeFalse())
+				Ω(fakeT.didFail).Should(BeTrue())
+				Ω(runOrder).Should(BeEmpty())
+			})
+		})
+
 		Context("when a spec fails", func() {
 			var location types.CodeLocation
 			BeforeEach(func() {
@@ -0,0 +1,56 @@
+/*
+The transport package lets parallel nodes reach the Ginkgo CLI's server over either TCP or a Unix domain socket.
+
+Server addresses are passed to nodes as URLs: http://127.0.0.1:<port> for TCP, and unix://<path to socket> for Unix domain sockets.
+*/
+package transport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+//UnixScheme prefixes the addresses of servers listening on Unix domain sockets
+const UnixScheme = "unix://"
+
+//unixBaseURL is the base URL of requests sent over a Unix domain socket.  The host is ignored as the client always dials the socket.
+const unixBaseURL = "http://ginkgo"
+
+var unixClients = map[string]*http.Client{}
+var unixClientsLock = &sync.Mutex{}
+
+//Client returns an HTTP client that can reach the server at address, along with the base URL to prefix request paths with
+func Client(address string) (*http.Client, string) {
+	if !strings.HasPrefix(address, UnixScheme) {
+		return http.DefaultClient, address
+	}
+
+	socket := strings.TrimPrefix(address, UnixScheme)
+
+	unixClientsLock.Lock()
+	defer unixClientsLock.Unlock()
+	client, ok := unixClients[socket]
+	if !ok {
+		dialer := &net.Dialer{}
+		client = &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		}
+		unixClients[socket] = client
+	}
+	return client, unixBaseURL
+}
+
+//Address returns the address at which the server accepting connections from listener can be reached
+func Address(listener net.Listener) string {
+	if listener.Addr().Network() == "unix" {
+		return UnixScheme + listener.Addr().String()
+	}
+	return "http://" + listener.Addr().String()
+}
@@ -0,0 +1,13 @@
+package transport_test
+
+import (
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/gomega"
+
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Transport Suite")
+}
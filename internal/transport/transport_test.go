@@ -0,0 +1,79 @@
+package transport_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/ginkgo/internal/transport"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("Transport", func() {
+	serve := func(listener net.Listener) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/hello", func(writer http.ResponseWriter, request *http.Request) {
+			writer.Write([]byte("hello"))
+		})
+		go http.Serve(listener, mux)
+	}
+
+	get := func(address string, path string) string {
+		client, baseURL := Client(address)
+		resp, err := client.Get(baseURL + path)
+		Ω(err).ShouldNot(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(body)
+	}
+
+	Context("with a TCP listener", func() {
+		It("should address the listener over http and reach it", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Ω(err).ShouldNot(HaveOccurred())
+			defer listener.Close()
+			serve(listener)
+
+			address := Address(listener)
+			Ω(address).Should(Equal("http://" + listener.Addr().String()))
+
+			client, baseURL := Client(address)
+			Ω(client).Should(Equal(http.DefaultClient))
+			Ω(baseURL).Should(Equal(address))
+			Ω(get(address, "/hello")).Should(Equal("hello"))
+		})
+	})
+
+	Context("with a Unix domain socket listener", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "ginkgo-transport")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("should address the listener with the unix scheme and reach it through the socket", func() {
+			socket := filepath.Join(dir, "test.sock")
+			listener, err := net.Listen("unix", socket)
+			if err != nil {
+				Skip("Unix domain sockets are unavailable: " + err.Error())
+			}
+			defer listener.Close()
+			serve(listener)
+
+			address := Address(listener)
+			Ω(address).Should(Equal("unix://" + socket))
+			Ω(get(address, "/hello")).Should(Equal("hello"))
+			Ω(get(address, "/hello?again=true")).Should(Equal("hello"))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package writer
 
 type FakeGinkgoWriter struct {
 	EventStream []string
+	Retained    []string
 }
 
 func NewFake() *FakeGinkgoWriter {
@@ -31,6 +32,12 @@ func (writer *FakeGinkgoWriter) Bytes() []byte {
 	return nil
 }
 
+func (writer *FakeGinkgoWriter) RetainBuffered(output []byte, name string) []byte {
+	writer.EventStream = append(writer.EventStream, "RETAIN_BUFFERED")
+	writer.Retained = append(writer.Retained, name)
+	return output
+}
+
 func (writer *FakeGinkgoWriter) Write(data []byte) (n int, err error) {
 	return 0, nil
 }
@@ -0,0 +1,291 @@
+package writer
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+/*
+RetentionPolicy bounds how much of a spec's output Ginkgo keeps in the spec's summary, and so sends to the CLI and writes to reports.
+
+Output keeps its beginning, up to its first HeadLines lines, and its end, up to its last TailLines lines.  Neither keeps more than MaxBytes/2 bytes.
+Zero values mean no limit, except that output with line limits keeps no more lines than those limits allow.  A marker replaces whatever was dropped in between.
+
+If OutputDir is set, the full output of each spec whose output was truncated is written to a file in OutputDir and the marker names that file.
+*/
+type RetentionPolicy struct {
+	MaxBytes  int
+	HeadLines int
+	TailLines int
+	OutputDir string
+}
+
+//IsLimited returns true if the policy can truncate output
+func (policy RetentionPolicy) IsLimited() bool {
+	return policy.MaxBytes > 0 || policy.HeadLines > 0 || policy.TailLines > 0
+}
+
+func (policy RetentionPolicy) limitsLines() bool {
+	return policy.HeadLines > 0 || policy.TailLines > 0
+}
+
+//Retain applies the policy to output.  name identifies the spec, or setup node, that produced the output and is used to name the file holding its full output.
+func (policy RetentionPolicy) Retain(output []byte, name string) []byte {
+	//output is already all in memory, so there's nothing to spool
+	buffer := RetainingBuffer{policy: RetentionPolicy{MaxBytes: policy.MaxBytes, HeadLines: policy.HeadLines, TailLines: policy.TailLines}}
+	buffer.Write(output)
+	if buffer.dropped == 0 {
+		return output
+	}
+
+	retained := buffer.Bytes()
+	if policy.OutputDir != "" {
+		path, err := policy.fullOutputPath(name)
+		if err == nil {
+			err = ioutil.WriteFile(path, output, 0644)
+		}
+		retained = append(retained, savedMarker(path, err)...)
+	}
+	return retained
+}
+
+/*
+RetainingBuffer applies a RetentionPolicy to output as it is written, so that it never holds much more than the policy retains:
+the beginning of the output and a window onto its end, with a count of what was dropped in between.
+
+Policies with an OutputDir need the full output of truncated specs.  Once the buffer starts dropping output it spools the full output to a file in OutputDir,
+which Retain renames after the spec.
+*/
+type RetainingBuffer struct {
+	policy RetentionPolicy
+
+	head         []byte
+	headNewlines int
+	tail         []byte
+	tailNewlines int
+
+	written         int
+	dropped         int
+	droppedNewlines int
+	droppedLastByte byte
+
+	spool    *os.File
+	spoolErr error
+}
+
+//NewBuffer returns an empty buffer that applies the policy
+func (policy RetentionPolicy) NewBuffer() *RetainingBuffer {
+	return &RetainingBuffer{policy: policy}
+}
+
+func (buffer *RetainingBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	buffer.written += n
+	if buffer.spool != nil {
+		buffer.spool.Write(p)
+	}
+
+	room := buffer.headRoom(p)
+	buffer.head = append(buffer.head, p[:room]...)
+	buffer.headNewlines += bytes.Count(p[:room], []byte("\n"))
+	p = p[room:]
+	if len(p) == 0 {
+		return n, nil
+	}
+
+	buffer.tail = append(buffer.tail, p...)
+	buffer.tailNewlines += bytes.Count(p, []byte("\n"))
+	buffer.trimTail()
+	return n, nil
+}
+
+//headRoom returns how much of p belongs to the beginning of the output
+func (buffer *RetainingBuffer) headRoom(p []byte) int {
+	policy := buffer.policy
+	if len(buffer.tail) > 0 || buffer.dropped > 0 {
+		return 0
+	}
+
+	room := len(p)
+	if policy.MaxBytes > 0 && len(buffer.head)+room > policy.MaxBytes/2 {
+		room = policy.MaxBytes/2 - len(buffer.head)
+	}
+	if policy.limitsLines() {
+		lines := 0
+		for i, b := range p[:room] {
+			if buffer.headNewlines+lines == policy.HeadLines {
+				return i
+			}
+			if b == '\n' {
+				lines++
+			}
+		}
+		if buffer.headNewlines+lines > policy.HeadLines {
+			return 0
+		}
+	}
+	return room
+}
+
+//trimTail drops the beginning of the tail until it fits the policy's limits on the end of the output
+func (buffer *RetainingBuffer) trimTail() {
+	policy := buffer.policy
+	drop := 0
+	if policy.limitsLines() {
+		lines := buffer.tailNewlines
+		if len(buffer.tail) > 0 && buffer.tail[len(buffer.tail)-1] != '\n' {
+			lines++
+		}
+		for ; lines > policy.TailLines; lines-- {
+			newline := bytes.IndexByte(buffer.tail[drop:], '\n')
+			if newline == -1 {
+				drop = len(buffer.tail)
+				break
+			}
+			drop += newline + 1
+		}
+	}
+	if tailLimit := policy.MaxBytes - policy.MaxBytes/2; policy.MaxBytes > 0 && len(buffer.tail)-drop > tailLimit {
+		drop = len(buffer.tail) - tailLimit
+	}
+	if drop == 0 {
+		return
+	}
+
+	if buffer.dropped == 0 && buffer.spool == nil && policy.OutputDir != "" {
+		buffer.startSpooling()
+	}
+	dropped := buffer.tail[:drop]
+	buffer.dropped += drop
+	buffer.droppedNewlines += bytes.Count(dropped, []byte("\n"))
+	buffer.droppedLastByte = dropped[drop-1]
+	buffer.tailNewlines -= bytes.Count(dropped, []byte("\n"))
+	//appending to the resliced tail moves it to a new array once it runs out of room, so the dropped bytes don't pile up
+	buffer.tail = buffer.tail[drop:]
+}
+
+//startSpooling saves everything written so far, none of which has been dropped yet, to a file in OutputDir.  Later writes are appended to the file.
+func (buffer *RetainingBuffer) startSpooling() {
+	err := os.MkdirAll(buffer.policy.OutputDir, 0755)
+	if err != nil {
+		buffer.spoolErr = err
+		return
+	}
+	buffer.spool, buffer.spoolErr = ioutil.TempFile(buffer.policy.OutputDir, ".spooled-output-")
+	if buffer.spoolErr == nil {
+		buffer.spool.Write(buffer.head)
+		buffer.spool.Write(buffer.tail)
+	}
+}
+
+//Len returns how much output has been written to the buffer, including any it dropped
+func (buffer *RetainingBuffer) Len() int {
+	return buffer.written
+}
+
+//Bytes returns the output the buffer retains, with a marker in place of whatever it dropped
+func (buffer *RetainingBuffer) Bytes() []byte {
+	retained := append([]byte{}, buffer.head...)
+	retained = append(retained, buffer.marker()...)
+	return append(retained, buffer.tail...)
+}
+
+//Since returns the output written after the first offset bytes, as far as the buffer retains it, with a marker in place of whatever it dropped
+func (buffer *RetainingBuffer) Since(offset int) []byte {
+	tailStart := buffer.written - len(buffer.tail)
+	switch {
+	case offset >= tailStart:
+		return append([]byte{}, buffer.tail[offset-tailStart:]...)
+	case offset < len(buffer.head):
+		return buffer.Bytes()[offset:]
+	default:
+		return append([]byte(fmt.Sprintf(bytesMarker, tailStart-offset)), buffer.tail...)
+	}
+}
+
+const bytesMarker = "\n...[%d bytes of output truncated]...\n"
+const linesMarker = "...[%d lines of output truncated]...\n"
+
+//marker notes what the buffer dropped.  When the line limits dropped whole lines it counts lines, otherwise bytes.
+func (buffer *RetainingBuffer) marker() string {
+	if buffer.dropped == 0 {
+		return ""
+	}
+	headEndsLine := len(buffer.head) == 0 || buffer.head[len(buffer.head)-1] == '\n'
+	if buffer.policy.limitsLines() && headEndsLine && buffer.droppedLastByte == '\n' {
+		return fmt.Sprintf(linesMarker, buffer.droppedNewlines)
+	}
+	return fmt.Sprintf(bytesMarker, buffer.dropped)
+}
+
+//Retain returns the output the buffer retains.  If the buffer dropped output and the policy has an OutputDir, the full output is saved to a file named after name, and the marker names that file.
+func (buffer *RetainingBuffer) Retain(name string) []byte {
+	retained := buffer.Bytes()
+	if buffer.dropped == 0 || buffer.policy.OutputDir == "" {
+		return retained
+	}
+
+	path, err := buffer.policy.fullOutputPath(name)
+	if err == nil {
+		err = buffer.spoolErr
+	}
+	if err == nil {
+		spool := buffer.spool
+		buffer.spool, buffer.spoolErr = nil, fmt.Errorf("the full output was already saved")
+		err = spool.Close()
+		if err == nil {
+			err = os.Rename(spool.Name(), path)
+		}
+	}
+	return append(retained, savedMarker(path, err)...)
+}
+
+//Reset empties the buffer, removing any full output it spooled but wasn't asked to save
+func (buffer *RetainingBuffer) Reset() {
+	if buffer.spool != nil {
+		buffer.spool.Close()
+		os.Remove(buffer.spool.Name())
+	}
+	*buffer = RetainingBuffer{policy: buffer.policy}
+}
+
+func savedMarker(path string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("\n...[failed to save the full output: %s]...\n", err.Error())
+	}
+	return fmt.Sprintf("\n...[full output saved to %s]...\n", path)
+}
+
+//SpecOutputName names the output of an attempt at running a spec on a parallel node, so that retries and nodes don't overwrite each other's full output
+func SpecOutputName(specID string, node int, attempt int) string {
+	return fmt.Sprintf("%s-node-%d-attempt-%d", specID, node, attempt)
+}
+
+var unsafeFileNameCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+//maxFileNameLength keeps file names well clear of the limits of common filesystems
+const maxFileNameLength = 100
+
+//fullOutputPath returns the path of the file holding the full output named name, creating OutputDir if necessary
+func (policy RetentionPolicy) fullOutputPath(name string) (string, error) {
+	err := os.MkdirAll(policy.OutputDir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	//names are sanitized, so a hash of the original name keeps different specs from sharing a file
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	fileName := unsafeFileNameCharacters.ReplaceAllString(name, "_")
+	if len(fileName) > maxFileNameLength {
+		fileName = fileName[:maxFileNameLength]
+	}
+	fileName = fmt.Sprintf("%s-%08x.log", fileName, hash.Sum32())
+
+	return filepath.Join(policy.OutputDir, fileName), nil
+}
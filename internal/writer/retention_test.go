@@ -0,0 +1,211 @@
+package writer_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/ginkgo/internal/writer"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("RetentionPolicy", func() {
+	var policy RetentionPolicy
+	var output []byte
+
+	BeforeEach(func() {
+		policy = RetentionPolicy{}
+		output = []byte("1\n2\n3\n4\n5\n6\n")
+	})
+
+	It("should keep everything by default", func() {
+		Ω(policy.IsLimited()).Should(BeFalse())
+		Ω(string(policy.Retain(output, "spec"))).Should(Equal("1\n2\n3\n4\n5\n6\n"))
+	})
+
+	Context("with line limits", func() {
+		It("should keep the first and last lines", func() {
+			policy.HeadLines = 2
+			policy.TailLines = 1
+			Ω(string(policy.Retain(output, "spec"))).Should(Equal("1\n2\n...[3 lines of output truncated]...\n6\n"))
+		})
+
+		It("should keep only the last lines if asked to", func() {
+			policy.TailLines = 2
+			Ω(string(policy.Retain(output, "spec"))).Should(Equal("...[4 lines of output truncated]...\n5\n6\n"))
+		})
+
+		It("should leave output that fits alone", func() {
+			policy.HeadLines = 3
+			policy.TailLines = 3
+			Ω(string(policy.Retain(output, "spec"))).Should(Equal("1\n2\n3\n4\n5\n6\n"))
+		})
+
+		It("should count a trailing line without a newline", func() {
+			policy.HeadLines = 1
+			policy.TailLines = 1
+			Ω(string(policy.Retain([]byte("a\nb\nc"), "spec"))).Should(Equal("a\n...[1 lines of output truncated]...\nc"))
+		})
+	})
+
+	Context("with a byte limit", func() {
+		It("should keep the beginning and the end of the output", func() {
+			policy.MaxBytes = 4
+			Ω(string(policy.Retain(output, "spec"))).Should(Equal("1\n\n...[8 bytes of output truncated]...\n6\n"))
+		})
+
+		It("should apply the byte limit after the line limits", func() {
+			policy.HeadLines = 1
+			policy.TailLines = 1
+			policy.MaxBytes = 1000
+			Ω(string(policy.Retain(output, "spec"))).Should(Equal("1\n...[4 lines of output truncated]...\n6\n"))
+		})
+	})
+
+	Context("with an output directory", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "ginkgo-output")
+			Ω(err).ShouldNot(HaveOccurred())
+			policy.OutputDir = filepath.Join(dir, "output")
+			policy.TailLines = 1
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("should save the full output of truncated specs and say where", func() {
+			retained := string(policy.Retain(output, "books_test.go:Book/can be read"))
+			files, err := ioutil.ReadDir(policy.OutputDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(files).Should(HaveLen(1))
+			Ω(files[0].Name()).Should(MatchRegexp(`^books_test.go_Book_can_be_read-[0-9a-f]{8}\.log$`))
+
+			path := filepath.Join(policy.OutputDir, files[0].Name())
+			Ω(retained).Should(Equal("...[5 lines of output truncated]...\n6\n\n...[full output saved to " + path + "]...\n"))
+			Ω(ioutil.ReadFile(path)).Should(Equal(output))
+		})
+
+		It("should give specs whose names sanitize alike different files", func() {
+			policy.Retain(output, "a/b")
+			policy.Retain(output, "a:b")
+			files, _ := ioutil.ReadDir(policy.OutputDir)
+			Ω(files).Should(HaveLen(2))
+		})
+
+		It("should shorten long names", func() {
+			policy.Retain(output, strings.Repeat("x", 500))
+			files, _ := ioutil.ReadDir(policy.OutputDir)
+			Ω(len(files[0].Name())).Should(BeNumerically("<", 120))
+		})
+
+		It("should not save output that wasn't truncated", func() {
+			policy.Retain([]byte("short"), "spec")
+			_, err := os.Stat(policy.OutputDir)
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("RetainingBuffer", func() {
+	write := func(buffer *RetainingBuffer, output []byte, chunkSize int) {
+		for len(output) > chunkSize {
+			buffer.Write(output[:chunkSize])
+			output = output[chunkSize:]
+		}
+		buffer.Write(output)
+	}
+
+	It("should retain what the policy retains, however the output is written", func() {
+		output := []byte("1\n2\n3\n4\n5\n6\n")
+		policies := []RetentionPolicy{
+			{},
+			{HeadLines: 2, TailLines: 1},
+			{TailLines: 2},
+			{MaxBytes: 5},
+			{MaxBytes: 8, HeadLines: 1, TailLines: 4},
+		}
+		for _, policy := range policies {
+			for chunkSize := 1; chunkSize <= len(output); chunkSize++ {
+				buffer := policy.NewBuffer()
+				write(buffer, output, chunkSize)
+				Ω(string(buffer.Bytes())).Should(Equal(string(policy.Retain(output, "spec"))), "%#v, written %d bytes at a time", policy, chunkSize)
+			}
+		}
+	})
+
+	It("should hold no more than the policy retains while output is written", func() {
+		buffer := RetentionPolicy{MaxBytes: 100}.NewBuffer()
+		for i := 0; i < 10000; i++ {
+			buffer.Write([]byte(strings.Repeat("x", 99) + "\n"))
+		}
+		Ω(buffer.Len()).Should(Equal(1000000))
+		Ω(string(buffer.Bytes())).Should(HavePrefix(strings.Repeat("x", 50) + "\n...[999900 bytes of output truncated]...\n"))
+		Ω(len(buffer.Bytes())).Should(BeNumerically("<", 150))
+	})
+
+	It("should return what was written since an offset, with a marker in place of what it dropped", func() {
+		buffer := RetentionPolicy{MaxBytes: 4}.NewBuffer()
+		buffer.Write([]byte("abcdef"))
+		Ω(string(buffer.Since(1))).Should(Equal("b\n...[2 bytes of output truncated]...\nef"))
+		Ω(string(buffer.Since(3))).Should(Equal("\n...[1 bytes of output truncated]...\nef"))
+		Ω(string(buffer.Since(5))).Should(Equal("f"))
+		Ω(buffer.Since(6)).Should(BeEmpty())
+	})
+
+	Context("with an output directory", func() {
+		var dir string
+		var policy RetentionPolicy
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "ginkgo-output")
+			Ω(err).ShouldNot(HaveOccurred())
+			policy = RetentionPolicy{MaxBytes: 10, OutputDir: filepath.Join(dir, "output")}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("should save the full output of truncated specs without holding it in memory", func() {
+			output := []byte(strings.Repeat("0123456789", 100))
+			buffer := policy.NewBuffer()
+			write(buffer, output, 7)
+			Ω(len(buffer.Bytes())).Should(BeNumerically("<", 60))
+
+			retained := string(buffer.Retain("spec"))
+			files, err := ioutil.ReadDir(policy.OutputDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(files).Should(HaveLen(1))
+			path := filepath.Join(policy.OutputDir, files[0].Name())
+			Ω(retained).Should(Equal(string(policy.Retain(output, "spec"))))
+			Ω(ioutil.ReadFile(path)).Should(Equal(output))
+		})
+
+		It("should discard the full output of specs that aren't retained", func() {
+			buffer := policy.NewBuffer()
+			buffer.Write([]byte(strings.Repeat("x", 100)))
+			buffer.Reset()
+			files, _ := ioutil.ReadDir(policy.OutputDir)
+			Ω(files).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("Writer retention", func() {
+	It("should apply the writer's retention policy to what it buffers", func() {
+		writer := New(ioutil.Discard)
+		writer.Write([]byte("abc"))
+		Ω(string(writer.RetainBuffered([]byte("def"), "spec"))).Should(Equal("abcdef"))
+		writer.Truncate()
+		writer.SetRetentionPolicy(RetentionPolicy{MaxBytes: 2})
+		writer.Write([]byte("abc"))
+		Ω(string(writer.RetainBuffered([]byte("def"), "spec"))).Should(Equal("a\n...[4 bytes of output truncated]...\nf"))
+	})
+})
@@ -2,8 +2,19 @@ package writer
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"sync"
+	"time"
+)
+
+//TimestampMode determines what, if anything, the Writer prefixes each line with
+type TimestampMode string
+
+const (
+	NoTimestamps        TimestampMode = ""
+	RelativeTimestamps  TimestampMode = "relative"
+	WallClockTimestamps TimestampMode = "wallclock"
 )
 
 type WriterInterface interface {
@@ -13,22 +24,30 @@ type WriterInterface interface {
 	DumpOut()
 	DumpOutWithHeader(header string)
 	Bytes() []byte
+	RetainBuffered(output []byte, name string) []byte
 }
 
 type Writer struct {
-	buffer     *bytes.Buffer
+	buffer     *RetainingBuffer
 	outWriter  io.Writer
 	lock       *sync.Mutex
 	stream     bool
 	redirector io.Writer
+	teeWriters []io.Writer
+
+	timestamps  TimestampMode
+	specStart   time.Time
+	atLineStart bool
 }
 
 func New(outWriter io.Writer) *Writer {
 	return &Writer{
-		buffer:    &bytes.Buffer{},
-		lock:      &sync.Mutex{},
-		outWriter: outWriter,
-		stream:    true,
+		buffer:      RetentionPolicy{}.NewBuffer(),
+		lock:        &sync.Mutex{},
+		outWriter:   outWriter,
+		stream:      true,
+		specStart:   time.Now(),
+		atLineStart: true,
 	}
 }
 
@@ -42,41 +61,135 @@ func (w *Writer) SetStream(stream bool) {
 	w.stream = stream
 }
 
+//SetRetentionPolicy sets the policy applied to the output of each spec.  The buffer applies it as output is written, so that it never holds much more than the policy retains.
+func (w *Writer) SetRetentionPolicy(policy RetentionPolicy) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.buffer.Reset()
+	w.buffer = policy.NewBuffer()
+}
+
+//RetainBuffered appends output to the buffer, without streaming it, and returns what the buffer retains of everything written since it was last truncated.
+//name identifies the spec that produced the output and names the file holding its full output, should the retention policy save it.
+func (w *Writer) RetainBuffered(output []byte, name string) []byte {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.buffer.Write(output)
+	return w.buffer.Retain(name)
+}
+
+//SetTimestamps makes the writer prefix each line with the time since the current spec started, or with the time of day
+func (w *Writer) SetTimestamps(timestamps TimestampMode) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.timestamps = timestamps
+}
+
+//TeeTo makes the writer also write everything it is given to writer, e.g. a log file.  Tee writers are not truncated between specs.
+func (w *Writer) TeeTo(writer io.Writer) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.teeWriters = append(w.teeWriters, writer)
+}
+
+//ClearTeeWriters detaches the writers attached with TeeTo
+func (w *Writer) ClearTeeWriters() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.teeWriters = nil
+}
+
+func (w *Writer) Print(a ...interface{}) {
+	fmt.Fprint(w, a...)
+}
+
+func (w *Writer) Printf(format string, a ...interface{}) {
+	fmt.Fprintf(w, format, a...)
+}
+
+func (w *Writer) Println(a ...interface{}) {
+	fmt.Fprintln(w, a...)
+}
+
 func (w *Writer) Write(b []byte) (n int, err error) {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 
-	n, err = w.buffer.Write(b)
+	stamped := w.stamp(b)
+	w.buffer.Write(stamped)
 	if w.redirector != nil {
-		w.redirector.Write(b)
+		w.redirector.Write(stamped)
+	}
+	for _, teeWriter := range w.teeWriters {
+		teeWriter.Write(stamped)
 	}
 	if w.stream {
-		return w.outWriter.Write(b)
+		_, err = w.outWriter.Write(stamped)
 	}
-	return n, err
+	//callers expect to hear back how much of what they wrote was written, not how long it was once stamped
+	return len(b), err
 }
 
+//stamp prefixes the start of each line in b with a timestamp
+func (w *Writer) stamp(b []byte) []byte {
+	if w.timestamps == NoTimestamps || len(b) == 0 {
+		return b
+	}
+
+	var timestamp string
+	if w.timestamps == WallClockTimestamps {
+		timestamp = fmt.Sprintf("[%s] ", time.Now().Format("2006-01-02T15:04:05.000Z07:00"))
+	} else {
+		timestamp = fmt.Sprintf("[+%.3fs] ", time.Since(w.specStart).Seconds())
+	}
+
+	stamped := make([]byte, 0, len(b)+len(timestamp))
+	for len(b) > 0 {
+		if w.atLineStart {
+			stamped = append(stamped, timestamp...)
+		}
+		newline := bytes.IndexByte(b, '\n')
+		if newline == -1 {
+			stamped = append(stamped, b...)
+			w.atLineStart = false
+			break
+		}
+		stamped = append(stamped, b[:newline+1]...)
+		b = b[newline+1:]
+		w.atLineStart = true
+	}
+	return stamped
+}
+
+//Truncate empties the buffer at the start of each spec and setup node, and restarts the clock relative timestamps are measured against
 func (w *Writer) Truncate() {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 	w.buffer.Reset()
+	w.specStart = time.Now()
+	w.atLineStart = true
 }
 
 func (w *Writer) DumpOut() {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 	if !w.stream {
-		w.buffer.WriteTo(w.outWriter)
+		w.outWriter.Write(w.buffer.Bytes())
+		w.buffer.Reset()
 	}
 }
 
 func (w *Writer) Bytes() []byte {
 	w.lock.Lock()
 	defer w.lock.Unlock()
-	b := w.buffer.Bytes()
-	copied := make([]byte, len(b))
-	copy(copied, b)
-	return copied
+	return w.buffer.Bytes()
+}
+
+//BytesSince returns what was buffered after the first offset bytes, with a marker in place of anything the retention policy dropped, and how much has been buffered in all
+func (w *Writer) BytesSince(offset int) ([]byte, int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.buffer.Since(offset), w.buffer.Len()
 }
 
 func (w *Writer) DumpOutWithHeader(header string) {
@@ -84,6 +197,7 @@ func (w *Writer) DumpOutWithHeader(header string) {
 	defer w.lock.Unlock()
 	if !w.stream && w.buffer.Len() > 0 {
 		w.outWriter.Write([]byte(header))
-		w.buffer.WriteTo(w.outWriter)
+		w.outWriter.Write(w.buffer.Bytes())
+		w.buffer.Reset()
 	}
 }
@@ -72,4 +72,69 @@ var _ = Describe("Writer", func() {
 			})
 		})
 	})
+
+	Describe("timestamps", func() {
+		It("should prefix each line with the time since the spec started", func() {
+			writer.SetTimestamps(RelativeTimestamps)
+			writer.Truncate()
+			writer.Write([]byte("foo\nbar"))
+			writer.Write([]byte(" baz\n"))
+			Ω(string(writer.Bytes())).Should(MatchRegexp(`^\[\+0\.\d{3}s\] foo\n\[\+0\.\d{3}s\] bar baz\n$`))
+		})
+
+		It("should prefix each line with the time of day", func() {
+			writer.SetTimestamps(WallClockTimestamps)
+			writer.Write([]byte("foo\n"))
+			Ω(string(writer.Bytes())).Should(MatchRegexp(`^\[\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}[^\]]*\] foo\n$`))
+		})
+
+		It("should start a new line at the start of each spec", func() {
+			writer.SetTimestamps(RelativeTimestamps)
+			writer.Write([]byte("unfinished"))
+			writer.Truncate()
+			writer.Write([]byte("foo"))
+			Ω(string(writer.Bytes())).Should(MatchRegexp(`^\[\+0\.\d{3}s\] foo$`))
+		})
+
+		It("should report the number of bytes it was given as written", func() {
+			writer.SetTimestamps(RelativeTimestamps)
+			n, err := writer.Write([]byte("foo\n"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(n).Should(Equal(4))
+		})
+	})
+
+	Describe("tee writers", func() {
+		It("should write to every tee writer until they are cleared", func() {
+			tee1 := gbytes.NewBuffer()
+			tee2 := gbytes.NewBuffer()
+			writer.TeeTo(tee1)
+			writer.TeeTo(tee2)
+			writer.Write([]byte("foo"))
+			Ω(tee1.Contents()).Should(Equal([]byte("foo")))
+			Ω(tee2.Contents()).Should(Equal([]byte("foo")))
+
+			writer.ClearTeeWriters()
+			writer.Write([]byte("bar"))
+			Ω(tee1.Contents()).Should(Equal([]byte("foo")))
+		})
+
+		It("should keep writing to tee writers when it isn't streaming", func() {
+			tee := gbytes.NewBuffer()
+			writer.SetStream(false)
+			writer.TeeTo(tee)
+			writer.Write([]byte("foo"))
+			Ω(tee.Contents()).Should(Equal([]byte("foo")))
+			Ω(out.Contents()).Should(BeEmpty())
+		})
+	})
+
+	Describe("printing", func() {
+		It("should print like fmt", func() {
+			writer.Print("a", 1)
+			writer.Printf(" %d-%s ", 2, "b")
+			writer.Println("c", 3)
+			Ω(string(writer.Bytes())).Should(Equal("a1 2-b c 3\n"))
+		})
+	})
 })
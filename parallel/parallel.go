@@ -0,0 +1,223 @@
+/*
+Package parallel lets the nodes of a parallel Ginkgo run coordinate with one another beyond SynchronizedBeforeSuite.
+
+It provides a key/value store shared by all nodes:
+
+	parallel.Set("admin-token", token)
+	token, err := parallel.WaitForKey("admin-token", time.Minute)
+
+	swapped, err := parallel.CompareAndSwap("schema-version", nil, []byte("42"))
+
+and pools of resources that are leased to one node at a time:
+
+	port, err := parallel.AcquirePort()
+	database, err := parallel.AcquireName("db")
+	dir, err := parallel.AcquireTempDir("fixtures")
+
+	parallel.ReleasePort(port)
+	parallel.Release("db", database)
+
+Resources still leased to a node when it exits are returned to their pool, and temporary directories are removed once the run completes.
+
+When a suite runs serially the same API is backed by an in-process store, so specs don't need to know whether they are running in parallel.
+Temporary directories handed out to a serial suite are not removed.
+*/
+package parallel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/internal/remote"
+	"github.com/hackrish007/ginkgo/internal/transport"
+)
+
+const portPool = "ginkgo-ports"
+
+var localState *remote.SharedState
+var localStateOnce sync.Once
+
+//Get returns the value stored under key, and whether there is one
+func Get(key string) ([]byte, bool, error) {
+	if !isParallel() {
+		value, ok := local().Get(key)
+		return value, ok, nil
+	}
+
+	return get(key, 0)
+}
+
+//Set stores value under key, replacing any previous value
+func Set(key string, value []byte) error {
+	if !isParallel() {
+		local().Set(key, value)
+		return nil
+	}
+
+	return post("/shared/value", remote.SharedValue{Key: key, Value: value}, nil)
+}
+
+//CompareAndSwap atomically stores new under key if key currently holds old, and reports whether it did.
+//Pass a nil old to store new only if key does not hold a value yet.
+func CompareAndSwap(key string, old []byte, new []byte) (bool, error) {
+	if !isParallel() {
+		return local().CompareAndSwap(key, old, new), nil
+	}
+
+	cas := remote.SharedCompareAndSwap{Key: key, Old: old, New: new}
+	err := post("/shared/compare-and-swap", cas, &cas)
+	return cas.Swapped, err
+}
+
+//WaitForKey blocks until a value is stored under key and returns it.  It returns an error if timeout elapses first.
+func WaitForKey(key string, timeout time.Duration) ([]byte, error) {
+	if !isParallel() {
+		value, ok := local().Wait(key, timeout)
+		if !ok {
+			return nil, waitTimeoutError(key, timeout)
+		}
+		return value, nil
+	}
+
+	//the server holds each request until the key is set, or for at most remote.MaxSharedValueWait
+	deadline := time.Now().Add(timeout)
+	for {
+		value, ok, err := get(key, time.Until(deadline))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return value, nil
+		}
+		if !time.Now().Before(deadline) {
+			return nil, waitTimeoutError(key, timeout)
+		}
+	}
+}
+
+func waitTimeoutError(key string, timeout time.Duration) error {
+	return fmt.Errorf("Timed out after %s waiting for shared key %q", timeout, key)
+}
+
+//AcquirePort leases a free TCP port on 127.0.0.1 that no other node holds
+func AcquirePort() (int, error) {
+	port, err := acquire(portPool, remote.ResourceKindPort)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(port)
+}
+
+//ReleasePort returns a port leased with AcquirePort
+func ReleasePort(port int) error {
+	return Release(portPool, strconv.Itoa(port))
+}
+
+//AcquireName leases a name, of the form <pool>_<n>, that no other node holds.  This is useful for, e.g., database names.
+func AcquireName(pool string) (string, error) {
+	return acquire(pool, remote.ResourceKindName)
+}
+
+//AcquireTempDir leases an empty temporary directory that no other node holds
+func AcquireTempDir(pool string) (string, error) {
+	return acquire(pool, remote.ResourceKindTempDir)
+}
+
+//Release returns a resource leased with AcquireName or AcquireTempDir to its pool
+func Release(pool string, resource string) error {
+	if !isParallel() {
+		return local().Release(config.GinkgoConfig.ParallelNode, pool, resource)
+	}
+
+	return post("/shared/release", remote.SharedResourceLease{Node: config.GinkgoConfig.ParallelNode, Pool: pool, Resource: resource}, nil)
+}
+
+func acquire(pool string, kind remote.ResourceKind) (string, error) {
+	if !isParallel() {
+		return local().Acquire(config.GinkgoConfig.ParallelNode, pool, kind)
+	}
+
+	lease := remote.SharedResourceLease{Node: config.GinkgoConfig.ParallelNode, Pool: pool, Kind: kind}
+	err := post("/shared/acquire", lease, &lease)
+	return lease.Resource, err
+}
+
+func isParallel() bool {
+	return config.GinkgoConfig.ParallelTotal > 1 && config.GinkgoConfig.SyncHost != ""
+}
+
+func local() *remote.SharedState {
+	localStateOnce.Do(func() {
+		localState = remote.NewSharedState()
+	})
+	return localState
+}
+
+//get asks the server for the value stored under key, waiting up to wait for one to be stored
+func get(key string, wait time.Duration) ([]byte, bool, error) {
+	query := "?key=" + url.QueryEscape(key)
+	if wait > 0 {
+		query += "&wait=" + url.QueryEscape(wait.String())
+	}
+
+	client, baseURL := transport.Client(config.GinkgoConfig.SyncHost)
+	resp, err := client.Get(baseURL + "/shared/value" + query)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	var value remote.SharedValue
+	err = decodeResponse(resp, &value)
+	if err != nil {
+		return nil, false, err
+	}
+	return value.Value, true, nil
+}
+
+func post(path string, request interface{}, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	client, baseURL := transport.Client(config.GinkgoConfig.SyncHost)
+	resp, err := client.Post(baseURL+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if response == nil {
+		return checkResponse(resp)
+	}
+	return decodeResponse(resp, response)
+}
+
+func decodeResponse(resp *http.Response, response interface{}) error {
+	err := checkResponse(resp)
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	message, _ := ioutil.ReadAll(resp.Body)
+	if len(bytes.TrimSpace(message)) == 0 {
+		return errors.New(resp.Status)
+	}
+	return errors.New(string(bytes.TrimSpace(message)))
+}
@@ -0,0 +1,13 @@
+package parallel_test
+
+import (
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/gomega"
+
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Parallel Suite")
+}
@@ -0,0 +1,108 @@
+package parallel_test
+
+import (
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	. "github.com/hackrish007/gomega"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/internal/remote"
+	"github.com/hackrish007/ginkgo/parallel"
+)
+
+var _ = Describe("Parallel", func() {
+	var originalConfig config.GinkgoConfigType
+
+	BeforeEach(func() {
+		originalConfig = config.GinkgoConfig
+	})
+
+	AfterEach(func() {
+		config.GinkgoConfig = originalConfig
+	})
+
+	sharesStateAndResources := func() {
+		It("shares a key/value store", func() {
+			_, ok, err := parallel.Get("missing")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ok).Should(BeFalse())
+
+			swapped, err := parallel.CompareAndSwap("token", nil, []byte("abc"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(swapped).Should(BeTrue())
+
+			swapped, err = parallel.CompareAndSwap("token", nil, []byte("def"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(swapped).Should(BeFalse())
+
+			value, ok, err := parallel.Get("token")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal([]byte("abc")))
+		})
+
+		It("waits for keys", func() {
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				parallel.Set("ready", []byte("yes"))
+			}()
+
+			value, err := parallel.WaitForKey("ready", time.Second)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(value).Should(Equal([]byte("yes")))
+
+			_, err = parallel.WaitForKey("never", 100*time.Millisecond)
+			Ω(err).Should(MatchError(ContainSubstring(`"never"`)))
+		})
+
+		It("leases resources", func() {
+			port, err := parallel.AcquirePort()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(port).Should(BeNumerically(">", 0))
+			Ω(parallel.ReleasePort(port)).Should(Succeed())
+
+			name, err := parallel.AcquireName("db")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(name).Should(HavePrefix("db_"))
+			Ω(parallel.Release("db", name)).Should(Succeed())
+			Ω(parallel.Release("db", name)).ShouldNot(Succeed())
+
+			dir, err := parallel.AcquireTempDir("fixtures")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(dir).Should(BeADirectory())
+			Ω(parallel.Release("fixtures", dir)).Should(Succeed())
+		})
+	}
+
+	Context("when running serially", func() {
+		BeforeEach(func() {
+			config.GinkgoConfig.ParallelNode = 1
+			config.GinkgoConfig.ParallelTotal = 1
+			config.GinkgoConfig.SyncHost = ""
+		})
+
+		sharesStateAndResources()
+	})
+
+	Context("when running in parallel", func() {
+		var server *remote.Server
+
+		BeforeEach(func() {
+			var err error
+			server, err = remote.NewServer(2)
+			Ω(err).ShouldNot(HaveOccurred())
+			server.Start()
+
+			config.GinkgoConfig.ParallelNode = 2
+			config.GinkgoConfig.ParallelTotal = 2
+			config.GinkgoConfig.SyncHost = server.Address()
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		sharesStateAndResources()
+	})
+})
@@ -28,6 +28,9 @@ func NewDefaultReporter(config config.DefaultReporterConfigType, stenographer st
 
 func (reporter *DefaultReporter) SpecSuiteWillBegin(config config.GinkgoConfigType, summary *types.SuiteSummary) {
 	reporter.stenographer.AnnounceSuite(summary.SuiteDescription, config.RandomSeed, config.RandomizeAllSpecs, reporter.config.Succinct)
+	if shardStenographer, ok := reporter.stenographer.(stenographer.ShardStenographer); ok && config.ShardTotal > 1 {
+		shardStenographer.AnnounceShard(config.ShardIndex, config.ShardTotal, reporter.config.Succinct)
+	}
 	if config.ParallelTotal > 1 {
 		reporter.stenographer.AnnounceParallelRun(config.ParallelNode, config.ParallelTotal, reporter.config.Succinct)
 	} else {
@@ -66,6 +66,23 @@ var _ = Describe("DefaultReporter", func() {
 			})
 		})
 
+		Context("when a shard of the suite begins", func() {
+			BeforeEach(func() {
+				ginkgoConfig.ParallelTotal = 1
+				ginkgoConfig.ShardIndex = 2
+				ginkgoConfig.ShardTotal = 3
+
+				reporter.SpecSuiteWillBegin(ginkgoConfig, suite)
+			})
+
+			It("should announce the suite, then the shard, then the number of specs", func() {
+				Ω(stenographer.Calls()).Should(HaveLen(3))
+				Ω(stenographer.Calls()[0]).Should(Equal(call("AnnounceSuite", "A Sweet Suite", ginkgoConfig.RandomSeed, true, false)))
+				Ω(stenographer.Calls()[1]).Should(Equal(call("AnnounceShard", 2, 3, false)))
+				Ω(stenographer.Calls()[2]).Should(Equal(call("AnnounceNumberOfSpecs", 8, 10, false)))
+			})
+		})
+
 		Context("when a parallel suite begins", func() {
 			BeforeEach(func() {
 				ginkgoConfig.ParallelTotal = 2
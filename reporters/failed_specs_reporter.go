@@ -0,0 +1,95 @@
+/*
+
+Failed Specs Reporter for Ginkgo
+
+Records the specs whose last attempt failed, identified by their stable IDs, and writes them to a JSON file when the suite ends.
+Failures of quarantined specs don't count.  The Ginkgo CLI uses the list to support -rerunFailed without collecting full reports of every run.
+
+*/
+
+package reporters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+//FailedSpec identifies a spec that failed by its stable ID.  Text is the spec's text, for readers of the file.
+type FailedSpec struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+type FailedSpecsReporter struct {
+	filename string
+	specs    []FailedSpec
+	failed   map[string]bool
+	lock     *sync.Mutex
+}
+
+//NewFailedSpecsReporter creates a new failed specs reporter.  The failed specs will be stored in the passed in filename, unless it is empty.
+func NewFailedSpecsReporter(filename string) *FailedSpecsReporter {
+	return &FailedSpecsReporter{
+		filename: filename,
+		specs:    []FailedSpec{},
+		failed:   map[string]bool{},
+		lock:     &sync.Mutex{},
+	}
+}
+
+//FailedSpecs returns the specs whose last attempt failed, in the order they first ran
+func (reporter *FailedSpecsReporter) FailedSpecs() []FailedSpec {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+	failedSpecs := []FailedSpec{}
+	for _, spec := range reporter.specs {
+		if reporter.failed[spec.ID] {
+			failedSpecs = append(failedSpecs, spec)
+		}
+	}
+	return failedSpecs
+}
+
+func (reporter *FailedSpecsReporter) SpecSuiteWillBegin(config config.GinkgoConfigType, summary *types.SuiteSummary) {
+}
+
+func (reporter *FailedSpecsReporter) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {
+}
+
+func (reporter *FailedSpecsReporter) SpecWillRun(specSummary *types.SpecSummary) {
+}
+
+func (reporter *FailedSpecsReporter) SpecDidComplete(specSummary *types.SpecSummary) {
+	if specSummary.ID == "" {
+		return
+	}
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+	if _, seen := reporter.failed[specSummary.ID]; !seen {
+		reporter.specs = append(reporter.specs, FailedSpec{ID: specSummary.ID, Text: strings.Join(specSummary.ComponentTexts[1:], " ")})
+	}
+	reporter.failed[specSummary.ID] = specSummary.HasFailureState() && !specSummary.Quarantined
+}
+
+func (reporter *FailedSpecsReporter) AfterSuiteDidRun(setupSummary *types.SetupSummary) {
+}
+
+func (reporter *FailedSpecsReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
+	if reporter.filename == "" {
+		return
+	}
+	data, err := json.Marshal(reporter.FailedSpecs())
+	if err == nil {
+		err = ioutil.WriteFile(reporter.filename, data, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nFailed to record the specs that failed:\n\t%s\n", err.Error())
+	}
+}
@@ -0,0 +1,68 @@
+package reporters_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/reporters"
+	"github.com/hackrish007/ginkgo/types"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("Failed Specs Reporter", func() {
+	specSummary := func(id string, text string, state types.SpecState) *types.SpecSummary {
+		return &types.SpecSummary{ID: id, ComponentTexts: []string{"[Top Level]", "Widget", text}, State: state}
+	}
+
+	run := func(reporter *reporters.FailedSpecsReporter) {
+		quarantined := specSummary("d", "is quarantined", types.SpecStateFailed)
+		quarantined.Quarantined = true
+
+		reporter.SpecSuiteWillBegin(config.GinkgoConfigType{}, &types.SuiteSummary{})
+		for _, summary := range []*types.SpecSummary{
+			specSummary("a", "fails", types.SpecStateFailed),
+			specSummary("b", "passes", types.SpecStatePassed),
+			specSummary("c", "flakes", types.SpecStatePanicked),
+			quarantined,
+			specSummary("c", "flakes", types.SpecStatePassed),
+			specSummary("e", "times out", types.SpecStateTimedOut),
+			specSummary("", "has no ID", types.SpecStateFailed),
+		} {
+			reporter.SpecWillRun(summary)
+			reporter.SpecDidComplete(summary)
+		}
+		reporter.SpecSuiteDidEnd(&types.SuiteSummary{})
+	}
+
+	It("lists the specs whose last attempt failed, leaving out quarantined failures", func() {
+		reporter := reporters.NewFailedSpecsReporter("")
+		run(reporter)
+
+		Ω(reporter.FailedSpecs()).Should(Equal([]reporters.FailedSpec{
+			{ID: "a", Text: "Widget fails"},
+			{ID: "e", Text: "Widget times out"},
+		}))
+	})
+
+	It("writes them to its file when the suite ends", func() {
+		dir, err := ioutil.TempDir("", "ginkgo-failed-specs")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		filename := filepath.Join(dir, "failed-specs.json")
+		run(reporters.NewFailedSpecsReporter(filename))
+
+		data, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		var specs []reporters.FailedSpec
+		Ω(json.Unmarshal(data, &specs)).Should(Succeed())
+		Ω(specs).Should(Equal([]reporters.FailedSpec{
+			{ID: "a", Text: "Widget fails"},
+			{ID: "e", Text: "Widget times out"},
+		}))
+	})
+})
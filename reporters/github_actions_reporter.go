@@ -0,0 +1,218 @@
+/*
+
+GitHub Actions Reporter for Ginkgo
+
+Prints GitHub Actions workflow commands so that failures show up as annotations of the workflow run, pointing at the line that failed.
+https://docs.github.com/en/actions/reference/workflow-commands-for-github-actions
+
+The suite's output is folded into a group.  Once the suite ends each failure is printed in a group of its own holding the failing spec's output,
+followed by an error annotation.  Flaked specs and failures of quarantined specs are annotated as warnings.  Paths are relative to the root of the suite's module.
+
+*/
+
+package reporters
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+type GitHubActionsReporter struct {
+	*SuiteReportRecorder
+	writer io.Writer
+}
+
+func NewGitHubActionsReporter(writer io.Writer) *GitHubActionsReporter {
+	return &GitHubActionsReporter{
+		SuiteReportRecorder: NewSuiteReportRecorder(),
+		writer:              writer,
+	}
+}
+
+func (reporter *GitHubActionsReporter) SpecSuiteWillBegin(config config.GinkgoConfigType, summary *types.SuiteSummary) {
+	reporter.SuiteReportRecorder.SpecSuiteWillBegin(config, summary)
+	BeginGitHubActionsGroup(reporter.writer, summary.SuiteDescription)
+}
+
+func (reporter *GitHubActionsReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
+	reporter.SuiteReportRecorder.SpecSuiteDidEnd(summary)
+	EndGitHubActionsGroup(reporter.writer)
+	WriteGitHubActionsAnnotations(reporter.writer, reporter.Report())
+}
+
+//BeginGitHubActionsGroup starts a group folding the output that follows, up to the next EndGitHubActionsGroup.  Groups don't nest.
+func BeginGitHubActionsGroup(writer io.Writer, title string) {
+	fmt.Fprintf(writer, "::group::%s\n", escapeWorkflowCommandData(title))
+}
+
+func EndGitHubActionsGroup(writer io.Writer) {
+	fmt.Fprintln(writer, "::endgroup::")
+}
+
+//WriteGitHubActionsAnnotations prints a group holding the output of each failure of the suite followed by an error annotation,
+//and a warning annotation for each flaked spec and each failure of a quarantined spec
+func WriteGitHubActionsAnnotations(writer io.Writer, report types.SuiteReport) {
+	root := moduleRoot(report.SuitePath)
+
+	if report.CompilationFailure != "" {
+		title := fmt.Sprintf("Failed to compile %s", report.SuiteDescription)
+		BeginGitHubActionsGroup(writer, title)
+		fmt.Fprintln(writer, report.CompilationFailure)
+		EndGitHubActionsGroup(writer)
+		writeWorkflowCommand(writer, "error", title, nil, root, report.CompilationFailure)
+	}
+	if report.BeforeSuite != nil && report.BeforeSuite.HasFailureState() {
+		writeGitHubActionsFailure(writer, "BeforeSuite", report.BeforeSuite.State, report.BeforeSuite.Failure, report.BeforeSuite.CapturedOutput, root)
+	}
+
+	//specs retried with -flakeAttempts are reported once per attempt: only their last attempt counts, and earlier failed attempts make a passing spec a flake
+	lastAttempts := map[string]int{}
+	failedAttempts := map[string]int{}
+	for i, spec := range report.Specs {
+		key := specKey(spec)
+		lastAttempts[key] = i
+		if spec.HasFailureState() {
+			failedAttempts[key]++
+		}
+	}
+	for i, spec := range report.Specs {
+		key := specKey(spec)
+		if lastAttempts[key] != i {
+			continue
+		}
+		name := strings.Join(spec.ComponentTexts[1:], " ")
+		switch {
+		case spec.HasQuarantinedFailure():
+			message := fmt.Sprintf("%s\n%s", quarantinedFailureMessage(spec.QuarantineReason), failureReportMessage(spec.Failure))
+			writeWorkflowCommand(writer, "warning", "[QUARANTINED] "+name, failureReportLocation(spec.Failure), root, message)
+		case spec.HasFailureState():
+			writeGitHubActionsFailure(writer, name, spec.State, spec.Failure, spec.CapturedOutput, root)
+		case failedAttempts[key] > 0:
+			message := fmt.Sprintf("%s failed %d %s before passing", name, failedAttempts[key], pluralize("time", failedAttempts[key]))
+			writeWorkflowCommand(writer, "warning", "[FLAKED] "+name, specLocation(spec), root, message)
+		}
+	}
+
+	if report.AfterSuite != nil && report.AfterSuite.HasFailureState() {
+		writeGitHubActionsFailure(writer, "AfterSuite", report.AfterSuite.State, report.AfterSuite.Failure, report.AfterSuite.CapturedOutput, root)
+	}
+}
+
+func writeGitHubActionsFailure(writer io.Writer, name string, state string, failure *types.FailureReport, output string, root string) {
+	title := fmt.Sprintf("[%s] %s", strings.ToUpper(state), name)
+	message := failureReportMessage(failure)
+	location := failureReportLocation(failure)
+
+	BeginGitHubActionsGroup(writer, title)
+	if output != "" {
+		fmt.Fprintln(writer, strings.TrimRight(output, "\n"))
+	}
+	fmt.Fprintln(writer, message)
+	if location != nil {
+		fmt.Fprintln(writer, location.String())
+	}
+	EndGitHubActionsGroup(writer)
+	writeWorkflowCommand(writer, "error", title, location, root, message)
+}
+
+//writeWorkflowCommand prints an error or warning annotation, pointing at location if it is known
+func writeWorkflowCommand(writer io.Writer, command string, title string, location *types.CodeLocation, root string, message string) {
+	properties := []string{}
+	if location != nil && location.FileName != "" {
+		properties = append(properties, "file="+escapeWorkflowCommandProperty(relativePath(root, location.FileName)))
+		properties = append(properties, fmt.Sprintf("line=%d", location.LineNumber))
+	}
+	properties = append(properties, "title="+escapeWorkflowCommandProperty(title))
+	fmt.Fprintf(writer, "::%s %s::%s\n", command, strings.Join(properties, ","), escapeWorkflowCommandData(message))
+}
+
+func failureReportMessage(failure *types.FailureReport) string {
+	if failure == nil {
+		return ""
+	}
+	if failure.ForwardedPanic != "" {
+		return fmt.Sprintf("%s\n%s", failure.Message, failure.ForwardedPanic)
+	}
+	return failure.Message
+}
+
+func failureReportLocation(failure *types.FailureReport) *types.CodeLocation {
+	if failure == nil {
+		return nil
+	}
+	return &failure.Location
+}
+
+func specLocation(spec types.SpecReport) *types.CodeLocation {
+	if len(spec.ComponentCodeLocations) == 0 {
+		return nil
+	}
+	return &spec.ComponentCodeLocations[len(spec.ComponentCodeLocations)-1]
+}
+
+func specKey(spec types.SpecReport) string {
+	if spec.ID != "" {
+		return spec.ID
+	}
+	return strings.Join(spec.ComponentTexts, " ")
+}
+
+func pluralize(word string, count int) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+//moduleRoot returns the directory of the go.mod file of the module dir belongs to, or "" if there is none
+func moduleRoot(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+//relativePath returns path relative to root, unless path lies outside root
+func relativePath(root string, path string) string {
+	if root == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	relative, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		return path
+	}
+	return filepath.ToSlash(relative)
+}
+
+func escapeWorkflowCommandData(data string) string {
+	data = strings.Replace(data, "%", "%25", -1)
+	data = strings.Replace(data, "\r", "%0D", -1)
+	data = strings.Replace(data, "\n", "%0A", -1)
+	return data
+}
+
+func escapeWorkflowCommandProperty(property string) string {
+	property = escapeWorkflowCommandData(property)
+	property = strings.Replace(property, ":", "%3A", -1)
+	property = strings.Replace(property, ",", "%2C", -1)
+	return property
+}
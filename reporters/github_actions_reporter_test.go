@@ -0,0 +1,133 @@
+package reporters_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/reporters"
+	"github.com/hackrish007/ginkgo/types"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("GitHub Actions Reporter", func() {
+	var (
+		buffer   *bytes.Buffer
+		root     string
+		suiteDir string
+		report   types.SuiteReport
+	)
+
+	spec := func(text string, state types.SpecState) types.SpecReport {
+		return types.SpecReport{
+			ID:             "a_test.go:" + text,
+			ComponentTexts: []string{"[Top Level]", "A", text},
+			ComponentCodeLocations: []types.CodeLocation{
+				{FileName: filepath.Join(suiteDir, "suite_test.go"), LineNumber: 1},
+				{FileName: filepath.Join(suiteDir, "a_test.go"), LineNumber: 3},
+				{FileName: filepath.Join(suiteDir, "a_test.go"), LineNumber: 5},
+			},
+			State: state.String(),
+		}
+	}
+
+	failure := func(message string, line int) *types.FailureReport {
+		return &types.FailureReport{
+			Message:  message,
+			Location: types.CodeLocation{FileName: filepath.Join(suiteDir, "a_test.go"), LineNumber: line},
+		}
+	}
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		var err error
+		root, err = ioutil.TempDir("", "github-actions-reporter")
+		Ω(err).ShouldNot(HaveOccurred())
+		suiteDir = filepath.Join(root, "pkg")
+		Ω(os.MkdirAll(suiteDir, os.ModePerm)).Should(Succeed())
+		Ω(ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644)).Should(Succeed())
+
+		report = types.SuiteReport{SuiteDescription: "My test suite", SuitePath: suiteDir}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	It("annotates failures with an error, after a group holding the failure's output", func() {
+		failed := spec("fails", types.SpecStateFailed)
+		failed.Failure = failure("Expected 1\nto equal 2", 7)
+		failed.CapturedOutput = "some output\n"
+		panicked := spec("panics, badly", types.SpecStatePanicked)
+		panicked.Failure = failure("Test Panicked", 9)
+		panicked.Failure.ForwardedPanic = "100% broken"
+		report.Specs = []types.SpecReport{spec("passes", types.SpecStatePassed), failed, panicked}
+
+		reporters.WriteGitHubActionsAnnotations(buffer, report)
+		Ω(buffer.String()).Should(Equal(
+			"::group::[FAILED] A fails\n" +
+				"some output\n" +
+				"Expected 1\nto equal 2\n" +
+				filepath.Join(suiteDir, "a_test.go") + ":7\n" +
+				"::endgroup::\n" +
+				"::error file=pkg/a_test.go,line=7,title=[FAILED] A fails::Expected 1%0Ato equal 2\n" +
+				"::group::[PANICKED] A panics, badly\n" +
+				"Test Panicked\n100% broken\n" +
+				filepath.Join(suiteDir, "a_test.go") + ":9\n" +
+				"::endgroup::\n" +
+				"::error file=pkg/a_test.go,line=9,title=[PANICKED] A panics%2C badly::Test Panicked%0A100%25 broken\n"))
+	})
+
+	It("annotates flaked specs and failures of quarantined specs with warnings", func() {
+		firstAttempt := spec("flakes", types.SpecStateFailed)
+		firstAttempt.Failure = failure("nope", 7)
+		quarantined := spec("is quarantined", types.SpecStateFailed)
+		quarantined.Failure = failure("still broken", 11)
+		quarantined.Quarantined = true
+		quarantined.QuarantineReason = "ISSUE-123"
+		report.Specs = []types.SpecReport{firstAttempt, spec("flakes", types.SpecStatePassed), quarantined}
+
+		reporters.WriteGitHubActionsAnnotations(buffer, report)
+		Ω(buffer.String()).Should(Equal(
+			"::warning file=pkg/a_test.go,line=5,title=[FLAKED] A flakes::A flakes failed 1 time before passing\n" +
+				"::warning file=pkg/a_test.go,line=11,title=[QUARANTINED] A is quarantined::Quarantined failure (ISSUE-123)%0Astill broken\n"))
+	})
+
+	It("annotates failed BeforeSuites, AfterSuites and compilations", func() {
+		report.BeforeSuite = &types.SetupReport{State: types.SpecStateTimedOut.String(), Failure: failure("took too long", 2)}
+		report.AfterSuite = &types.SetupReport{State: types.SpecStatePassed.String()}
+		report.CompilationFailure = "undefined: x"
+
+		reporters.WriteGitHubActionsAnnotations(buffer, report)
+		Ω(buffer.String()).Should(ContainSubstring("::error title=Failed to compile My test suite::undefined: x\n"))
+		Ω(buffer.String()).Should(ContainSubstring("::error file=pkg/a_test.go,line=2,title=[TIMEDOUT] BeforeSuite::took too long\n"))
+		Ω(buffer.String()).ShouldNot(ContainSubstring("AfterSuite"))
+	})
+
+	It("leaves paths outside the suite's module alone", func() {
+		failed := spec("fails", types.SpecStateFailed)
+		failed.Failure = &types.FailureReport{Message: "boom", Location: types.CodeLocation{FileName: "/elsewhere/b.go", LineNumber: 3}}
+		report.Specs = []types.SpecReport{failed}
+
+		reporters.WriteGitHubActionsAnnotations(buffer, report)
+		Ω(buffer.String()).Should(ContainSubstring("::error file=/elsewhere/b.go,line=3,"))
+	})
+
+	It("folds the suite's output into a group when reporting a suite as it runs", func() {
+		reporter := reporters.NewGitHubActionsReporter(buffer)
+		reporter.SetSuitePath(suiteDir)
+		reporter.SpecSuiteWillBegin(config.GinkgoConfigType{}, &types.SuiteSummary{SuiteDescription: "My test suite"})
+		reporter.SpecDidComplete(&types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "A"},
+			State:          types.SpecStateFailed,
+			Failure:        types.SpecFailure{Message: "boom", Location: types.CodeLocation{FileName: filepath.Join(suiteDir, "a_test.go"), LineNumber: 4}},
+		})
+		reporter.SpecSuiteDidEnd(&types.SuiteSummary{})
+
+		Ω(buffer.String()).Should(HavePrefix("::group::My test suite\n::endgroup::\n::group::[FAILED] A\n"))
+		Ω(buffer.String()).Should(HaveSuffix("::error file=pkg/a_test.go,line=4,title=[FAILED] A::boom\n"))
+	})
+})
@@ -0,0 +1,84 @@
+/*
+
+JSON Reporter for Ginkgo
+
+Writes a versioned JSON document describing the whole run of a suite.  The document unmarshals into a types.SuiteReport.
+
+*/
+
+package reporters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hackrish007/ginkgo/types"
+)
+
+type JSONReporter struct {
+	*SuiteReportRecorder
+	filename string
+}
+
+//NewJSONReporter creates a new JSON reporter.  The report will be stored in the passed in filename.
+func NewJSONReporter(filename string) *JSONReporter {
+	return &JSONReporter{
+		SuiteReportRecorder: NewSuiteReportRecorder(),
+		filename:            filename,
+	}
+}
+
+func (reporter *JSONReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
+	reporter.SuiteReportRecorder.SpecSuiteDidEnd(summary)
+
+	_, err := WriteJSONReport(reporter.filename, reporter.Report())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nFailed to generate JSON report:\n\t%s\n", err.Error())
+	}
+}
+
+//WriteJSONReport writes report, a types.SuiteReport or a slice of them, to filename, creating any missing parent directories, and returns the absolute path of the file
+func WriteJSONReport(filename string, report interface{}) (string, error) {
+	filePath, err := filepath.Abs(filename)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return filePath, ioutil.WriteFile(filePath, data, 0644)
+}
+
+//ReadJSONReport reads the suite reports in filename, which holds either a single types.SuiteReport, as written by a suite run with go test, or an array of them, as written by the Ginkgo CLI
+func ReadJSONReport(filename string) ([]types.SuiteReport, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	reports := []types.SuiteReport{}
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		err = json.Unmarshal(data, &reports)
+	} else {
+		report := types.SuiteReport{}
+		err = json.Unmarshal(data, &report)
+		reports = append(reports, report)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a JSON report: %s", filename, err.Error())
+	}
+	for _, report := range reports {
+		if report.Version > types.JSONReportVersion {
+			return nil, fmt.Errorf("%s is a version %d JSON report, but this version of Ginkgo only reads version %d reports", filename, report.Version, types.JSONReportVersion)
+		}
+	}
+	return reports, nil
+}
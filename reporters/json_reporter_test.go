@@ -0,0 +1,201 @@
+package reporters_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/reporters"
+	"github.com/hackrish007/ginkgo/types"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("JSON Reporter", func() {
+	var (
+		tmpDir     string
+		outputFile string
+		reporter   *reporters.JSONReporter
+	)
+
+	readOutputFile := func() types.SuiteReport {
+		bytes, err := ioutil.ReadFile(outputFile)
+		Ω(err).ShouldNot(HaveOccurred())
+		var report types.SuiteReport
+		err = json.Unmarshal(bytes, &report)
+		Ω(err).ShouldNot(HaveOccurred())
+		return report
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "json-reporter")
+		Ω(err).ShouldNot(HaveOccurred())
+		outputFile = filepath.Join(tmpDir, "reports", "report.json")
+
+		reporter = reporters.NewJSONReporter(outputFile)
+		reporter.SpecSuiteWillBegin(config.GinkgoConfigType{
+			RandomSeed:    17,
+			FocusStrings:  []string{"A"},
+			ParallelTotal: 1,
+		}, &types.SuiteSummary{
+			SuiteDescription: "My test suite",
+		})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Context("when the suite runs", func() {
+		BeforeEach(func() {
+			reporter.BeforeSuiteDidRun(&types.SetupSummary{
+				ComponentType: types.SpecComponentTypeBeforeSuite,
+				State:         types.SpecStatePassed,
+				RunTime:       time.Second,
+			})
+
+			passed := &types.SpecSummary{
+				ID:             "a_test.go:A/B",
+				ComponentTexts: []string{"[Top Level]", "A", "B"},
+				ComponentCodeLocations: []types.CodeLocation{
+					{FileName: "suite_test.go", LineNumber: 1},
+					{FileName: "a_test.go", LineNumber: 10},
+					{FileName: "a_test.go", LineNumber: 12},
+				},
+				State:          types.SpecStatePassed,
+				RunTime:        2 * time.Second,
+				CapturedOutput: "some output",
+				ParallelNode:   1,
+				IsMeasurement:  true,
+				Measurements: map[string]*types.SpecMeasurement{
+					"speed": {Name: "speed", Results: []float64{1, 2}, Average: 1.5, Units: "ms"},
+				},
+			}
+			reporter.SpecWillRun(passed)
+			reporter.SpecDidComplete(passed)
+
+			panicked := &types.SpecSummary{
+				ID:             "a_test.go:A/C",
+				ComponentTexts: []string{"[Top Level]", "A", "C"},
+				State:          types.SpecStatePanicked,
+				Failure: types.SpecFailure{
+					Message:               "Test Panicked",
+					Location:              types.CodeLocation{FileName: "a_test.go", LineNumber: 20, FullStackTrace: "the stack"},
+					ForwardedPanic:        "boom",
+					ComponentType:         types.SpecComponentTypeIt,
+					ComponentCodeLocation: types.CodeLocation{FileName: "a_test.go", LineNumber: 19},
+				},
+			}
+			reporter.SpecWillRun(panicked)
+			reporter.SpecDidComplete(panicked)
+
+			reporter.AfterSuiteDidRun(&types.SetupSummary{
+				ComponentType: types.SpecComponentTypeAfterSuite,
+				State:         types.SpecStateFailed,
+				Failure:       types.SpecFailure{Message: "cleanup failed", ComponentType: types.SpecComponentTypeAfterSuite},
+			})
+
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{
+				SuiteDescription:           "My test suite",
+				SuiteSucceeded:             false,
+				NumberOfTotalSpecs:         2,
+				NumberOfSpecsThatWillBeRun: 2,
+				NumberOfPassedSpecs:        1,
+				NumberOfFailedSpecs:        1,
+				RunTime:                    3 * time.Second,
+			})
+		})
+
+		It("writes a versioned report describing the suite, creating any missing directories", func() {
+			report := readOutputFile()
+			Ω(report.Version).Should(Equal(types.JSONReportVersion))
+			Ω(report.SuiteDescription).Should(Equal("My test suite"))
+			Ω(report.SuitePath).ShouldNot(BeEmpty())
+			Ω(report.SuiteSucceeded).Should(BeFalse())
+			Ω(report.Config.RandomSeed).Should(BeEquivalentTo(17))
+			Ω(report.Config.FocusStrings).Should(Equal([]string{"A"}))
+			Ω(report.Config.ParallelTotal).Should(Equal(1))
+			Ω(report.EndTime).ShouldNot(BeTemporally("<", report.StartTime))
+			Ω(report.RunTime).Should(Equal(3 * time.Second))
+			Ω(report.NumberOfPassedSpecs).Should(Equal(1))
+			Ω(report.NumberOfFailedSpecs).Should(Equal(1))
+		})
+
+		It("reports the BeforeSuite and AfterSuite", func() {
+			report := readOutputFile()
+			Ω(report.BeforeSuite.State).Should(Equal("passed"))
+			Ω(report.BeforeSuite.RunTime).Should(Equal(time.Second))
+			Ω(report.BeforeSuite.Failure).Should(BeNil())
+			Ω(report.AfterSuite.State).Should(Equal("failed"))
+			Ω(report.AfterSuite.Failure.Message).Should(Equal("cleanup failed"))
+			Ω(report.AfterSuite.Failure.ComponentType).Should(Equal("AfterSuite"))
+		})
+
+		It("reports every spec", func() {
+			report := readOutputFile()
+			Ω(report.Specs).Should(HaveLen(2))
+
+			passed := report.Specs[0]
+			Ω(passed.ID).Should(Equal("a_test.go:A/B"))
+			Ω(passed.ComponentTexts).Should(Equal([]string{"[Top Level]", "A", "B"}))
+			Ω(passed.ComponentCodeLocations[2]).Should(Equal(types.CodeLocation{FileName: "a_test.go", LineNumber: 12}))
+			Ω(passed.State).Should(Equal("passed"))
+			Ω(passed.RunTime).Should(Equal(2 * time.Second))
+			Ω(passed.EndTime.Sub(passed.StartTime)).Should(Equal(2 * time.Second))
+			Ω(passed.ParallelNode).Should(Equal(1))
+			Ω(passed.CapturedOutput).Should(Equal("some output"))
+			Ω(passed.Failure).Should(BeNil())
+			Ω(passed.Measurements["speed"].Results).Should(Equal([]float64{1, 2}))
+			Ω(passed.Measurements["speed"].Units).Should(Equal("ms"))
+
+			panicked := report.Specs[1]
+			Ω(panicked.State).Should(Equal("panicked"))
+			Ω(panicked.Failure.Message).Should(Equal("Test Panicked"))
+			Ω(panicked.Failure.Location.LineNumber).Should(Equal(20))
+			Ω(panicked.Failure.Location.FullStackTrace).Should(Equal("the stack"))
+			Ω(panicked.Failure.ForwardedPanic).Should(Equal("boom"))
+			Ω(panicked.Failure.ComponentType).Should(Equal("It"))
+			Ω(panicked.Failure.ComponentCodeLocation.LineNumber).Should(Equal(19))
+		})
+	})
+
+	Describe("reading reports", func() {
+		It("reads a report of a single suite", func() {
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{SuiteSucceeded: true})
+
+			reports, err := reporters.ReadJSONReport(outputFile)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(reports).Should(HaveLen(1))
+			Ω(reports[0].SuiteDescription).Should(Equal("My test suite"))
+		})
+
+		It("reads a report of several suites", func() {
+			_, err := reporters.WriteJSONReport(outputFile, []types.SuiteReport{
+				{Version: types.JSONReportVersion, SuiteDescription: "A"},
+				{Version: types.JSONReportVersion, SuiteDescription: "B"},
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			reports, err := reporters.ReadJSONReport(outputFile)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(reports).Should(HaveLen(2))
+			Ω(reports[1].SuiteDescription).Should(Equal("B"))
+		})
+
+		It("refuses files that aren't reports, and reports of newer versions", func() {
+			Ω(os.MkdirAll(filepath.Dir(outputFile), os.ModePerm)).Should(Succeed())
+			Ω(ioutil.WriteFile(outputFile, []byte("<testsuites/>"), 0644)).Should(Succeed())
+			_, err := reporters.ReadJSONReport(outputFile)
+			Ω(err).Should(MatchError(ContainSubstring("is not a JSON report")))
+
+			_, err = reporters.WriteJSONReport(outputFile, types.SuiteReport{Version: types.JSONReportVersion + 1})
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = reporters.ReadJSONReport(outputFile)
+			Ω(err).Should(MatchError(ContainSubstring("only reads version 1 reports")))
+		})
+	})
+})
@@ -11,32 +11,62 @@ package reporters
 import (
 	"encoding/xml"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hackrish007/ginkgo/config"
 	"github.com/hackrish007/ginkgo/types"
 )
 
+type JUnitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	TestSuites []JUnitTestSuite `xml:"testsuite"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       float64          `xml:"time,attr"`
+}
+
 type JUnitTestSuite struct {
-	XMLName   xml.Name        `xml:"testsuite"`
-	TestCases []JUnitTestCase `xml:"testcase"`
-	Name      string          `xml:"name,attr"`
-	Tests     int             `xml:"tests,attr"`
-	Failures  int             `xml:"failures,attr"`
-	Errors    int             `xml:"errors,attr"`
-	Time      float64         `xml:"time,attr"`
+	XMLName    xml.Name         `xml:"testsuite"`
+	Properties *JUnitProperties `xml:"properties,omitempty"`
+	TestCases  []JUnitTestCase  `xml:"testcase"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       float64          `xml:"time,attr"`
+	Timestamp  string           `xml:"timestamp,attr,omitempty"`
+	Hostname   string           `xml:"hostname,attr,omitempty"`
 }
 
 type JUnitTestCase struct {
 	Name           string               `xml:"name,attr"`
 	ClassName      string               `xml:"classname,attr"`
+	File           string               `xml:"file,attr,omitempty"`
+	Line           int                  `xml:"line,attr,omitempty"`
+	Properties     *JUnitProperties     `xml:"properties,omitempty"`
 	FailureMessage *JUnitFailureMessage `xml:"failure,omitempty"`
+	ErrorMessage   *JUnitFailureMessage `xml:"error,omitempty"`
 	Skipped        *JUnitSkipped        `xml:"skipped,omitempty"`
 	Time           float64              `xml:"time,attr"`
 	SystemOut      string               `xml:"system-out,omitempty"`
+	SystemErr      string               `xml:"system-err,omitempty"`
+}
+
+type JUnitProperties struct {
+	Properties []JUnitProperty `xml:"property"`
+}
+
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
 }
 
 type JUnitFailureMessage struct {
@@ -49,25 +79,22 @@ type JUnitSkipped struct {
 }
 
 type JUnitReporter struct {
+	recorder       *SuiteReportRecorder
 	suite          JUnitTestSuite
 	filename       string
-	testSuiteName  string
 	ReporterConfig config.DefaultReporterConfigType
 }
 
 //NewJUnitReporter creates a new JUnit XML reporter.  The XML will be stored in the passed in filename.
 func NewJUnitReporter(filename string) *JUnitReporter {
 	return &JUnitReporter{
+		recorder: NewSuiteReportRecorder(),
 		filename: filename,
 	}
 }
 
 func (reporter *JUnitReporter) SpecSuiteWillBegin(ginkgoConfig config.GinkgoConfigType, summary *types.SuiteSummary) {
-	reporter.suite = JUnitTestSuite{
-		Name:      summary.SuiteDescription,
-		TestCases: []JUnitTestCase{},
-	}
-	reporter.testSuiteName = summary.SuiteDescription
+	reporter.recorder.SpecSuiteWillBegin(ginkgoConfig, summary)
 	reporter.ReporterConfig = config.DefaultReporterConfig
 }
 
@@ -75,69 +102,249 @@ func (reporter *JUnitReporter) SpecWillRun(specSummary *types.SpecSummary) {
 }
 
 func (reporter *JUnitReporter) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {
-	reporter.handleSetupSummary("BeforeSuite", setupSummary)
+	reporter.recorder.BeforeSuiteDidRun(setupSummary)
 }
 
 func (reporter *JUnitReporter) AfterSuiteDidRun(setupSummary *types.SetupSummary) {
-	reporter.handleSetupSummary("AfterSuite", setupSummary)
+	reporter.recorder.AfterSuiteDidRun(setupSummary)
 }
 
-func failureMessage(failure types.SpecFailure) string {
-	return fmt.Sprintf("%s\n%s\n%s", failure.ComponentCodeLocation.String(), failure.Message, failure.Location.String())
+func (reporter *JUnitReporter) SpecDidComplete(specSummary *types.SpecSummary) {
+	reporter.recorder.SpecDidComplete(specSummary)
+}
+
+//NewJUnitTestSuites converts the reports of the suites of a run into a JUnit <testsuites> document
+func NewJUnitTestSuites(reports []types.SuiteReport, reporterConfig config.DefaultReporterConfigType) JUnitTestSuites {
+	suites := JUnitTestSuites{
+		TestSuites: []JUnitTestSuite{},
+	}
+	for _, report := range reports {
+		suite := NewJUnitTestSuite(report, reporterConfig)
+		suites.TestSuites = append(suites.TestSuites, suite)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+		suites.Time += suite.Time
+	}
+	suites.Time = math.Trunc(suites.Time*1000) / 1000
+	return suites
 }
 
-func (reporter *JUnitReporter) handleSetupSummary(name string, setupSummary *types.SetupSummary) {
-	if setupSummary.State != types.SpecStatePassed {
-		testCase := JUnitTestCase{
-			Name:      name,
-			ClassName: reporter.testSuiteName,
+/*
+NewJUnitTestSuite converts the report of a suite into a JUnit <testsuite>.
+
+Failed specs and setup nodes are reported with a <failure>, and those that panicked or timed out with an <error>.
+Unless reporterConfig.ReportPassed is set, only they include their captured output, which goes in <system-out>, or <system-err> with reporterConfig.JUnitSystemErr.
+reporterConfig.JUnitClassName picks the classname of specs.
+A suite that failed to compile is reported as a suite with a single errored test case holding the compiler's output.
+*/
+func NewJUnitTestSuite(report types.SuiteReport, reporterConfig config.DefaultReporterConfigType) JUnitTestSuite {
+	suite := JUnitTestSuite{
+		Name:      report.SuiteDescription,
+		TestCases: []JUnitTestCase{},
+		Tests:     report.NumberOfSpecsThatWillBeRun,
+		Time:      math.Trunc(report.RunTime.Seconds()*1000) / 1000,
+		Hostname:  report.Hostname,
+	}
+	if !report.StartTime.IsZero() {
+		suite.Timestamp = report.StartTime.Format("2006-01-02T15:04:05")
+	}
+
+	if report.CompilationFailure != "" {
+		suite.TestCases = append(suite.TestCases, JUnitTestCase{
+			Name:      "Compilation",
+			ClassName: report.SuiteDescription,
+			ErrorMessage: &JUnitFailureMessage{
+				Type:    "CompilationFailure",
+				Message: report.CompilationFailure,
+			},
+		})
+	} else {
+		suite.Properties = suiteProperties(report)
+		if report.BeforeSuite != nil {
+			suite.TestCases = appendSetupTestCase(suite.TestCases, "BeforeSuite", report.SuiteDescription, report.BeforeSuite, reporterConfig)
 		}
+		for _, spec := range report.Specs {
+			suite.TestCases = append(suite.TestCases, newJUnitTestCase(spec, report.SuiteDescription, reporterConfig))
+		}
+		if report.AfterSuite != nil {
+			suite.TestCases = appendSetupTestCase(suite.TestCases, "AfterSuite", report.SuiteDescription, report.AfterSuite, reporterConfig)
+		}
+	}
 
-		testCase.FailureMessage = &JUnitFailureMessage{
-			Type:    reporter.failureTypeForState(setupSummary.State),
-			Message: failureMessage(setupSummary.Failure),
+	for _, testCase := range suite.TestCases {
+		if testCase.FailureMessage != nil {
+			suite.Failures++
+		}
+		if testCase.ErrorMessage != nil {
+			suite.Errors++
+		}
+		if testCase.Skipped != nil {
+			suite.Skipped++
 		}
-		testCase.SystemOut = setupSummary.CapturedOutput
-		testCase.Time = setupSummary.RunTime.Seconds()
-		reporter.suite.TestCases = append(reporter.suite.TestCases, testCase)
 	}
+	return suite
 }
 
-func (reporter *JUnitReporter) SpecDidComplete(specSummary *types.SpecSummary) {
-	testCase := JUnitTestCase{
-		Name:      strings.Join(specSummary.ComponentTexts[1:], " "),
-		ClassName: reporter.testSuiteName,
+func suiteProperties(report types.SuiteReport) *JUnitProperties {
+	properties := []JUnitProperty{
+		{Name: "SuiteSucceeded", Value: fmt.Sprintf("%t", report.SuiteSucceeded)},
+		{Name: "RandomSeed", Value: fmt.Sprintf("%d", report.Config.RandomSeed)},
+		{Name: "RandomizeAllSpecs", Value: fmt.Sprintf("%t", report.Config.RandomizeAllSpecs)},
+		{Name: "FocusStrings", Value: strings.Join(report.Config.FocusStrings, ",")},
+		{Name: "SkipStrings", Value: strings.Join(report.Config.SkipStrings, ",")},
+		{Name: "FocusFiles", Value: strings.Join(report.Config.FocusFiles, ",")},
+		{Name: "SkipFiles", Value: strings.Join(report.Config.SkipFiles, ",")},
+		{Name: "ParallelTotal", Value: fmt.Sprintf("%d", report.Config.ParallelTotal)},
 	}
-	if reporter.ReporterConfig.ReportPassed && specSummary.State == types.SpecStatePassed {
-		testCase.SystemOut = specSummary.CapturedOutput
+	if report.Config.ShardTotal > 0 {
+		properties = append(properties, JUnitProperty{Name: "Shard", Value: fmt.Sprintf("%d/%d", report.Config.ShardIndex, report.Config.ShardTotal)})
 	}
-	if specSummary.State == types.SpecStateFailed || specSummary.State == types.SpecStateTimedOut || specSummary.State == types.SpecStatePanicked {
-		testCase.FailureMessage = &JUnitFailureMessage{
-			Type:    reporter.failureTypeForState(specSummary.State),
-			Message: failureMessage(specSummary.Failure),
-		}
-		if specSummary.State == types.SpecStatePanicked {
-			testCase.FailureMessage.Message += fmt.Sprintf("\n\nPanic: %s\n\nFull stack:\n%s",
-				specSummary.Failure.ForwardedPanic,
-				specSummary.Failure.Location.FullStackTrace)
+	if report.GoVersion != "" {
+		properties = append(properties, JUnitProperty{Name: "GoVersion", Value: report.GoVersion})
+	}
+	return &JUnitProperties{Properties: properties}
+}
+
+func failureMessage(failure *types.FailureReport) string {
+	return fmt.Sprintf("%s\n%s\n%s", failure.ComponentCodeLocation.String(), failure.Message, failure.Location.String())
+}
+
+func quarantinedFailureMessage(quarantineReason string) string {
+	if quarantineReason == "" {
+		return "Quarantined failure"
+	}
+	return fmt.Sprintf("Quarantined failure (%s)", quarantineReason)
+}
+
+//failureElement returns the <failure> or <error> for a failed spec or setup node
+func failureElement(state string, failure *types.FailureReport) *JUnitFailureMessage {
+	element := &JUnitFailureMessage{
+		Type: failureTypeForState(state),
+	}
+	if failure != nil {
+		element.Message = failureMessage(failure)
+		if state == types.SpecStatePanicked.String() {
+			element.Message += fmt.Sprintf("\n\nPanic: %s\n\nFull stack:\n%s",
+				failure.ForwardedPanic,
+				failure.Location.FullStackTrace)
 		}
-		testCase.SystemOut = specSummary.CapturedOutput
 	}
-	if specSummary.State == types.SpecStateSkipped || specSummary.State == types.SpecStatePending {
+	return element
+}
+
+//setFailure records the failure of a spec or setup node as a <failure>, or as an <error> if it panicked or timed out
+func (testCase *JUnitTestCase) setFailure(state string, failure *types.FailureReport) {
+	if state == types.SpecStatePanicked.String() || state == types.SpecStateTimedOut.String() {
+		testCase.ErrorMessage = failureElement(state, failure)
+	} else {
+		testCase.FailureMessage = failureElement(state, failure)
+	}
+}
+
+func (testCase *JUnitTestCase) setOutput(output string, reporterConfig config.DefaultReporterConfigType) {
+	if reporterConfig.JUnitSystemErr {
+		testCase.SystemErr = output
+	} else {
+		testCase.SystemOut = output
+	}
+}
+
+func appendSetupTestCase(testCases []JUnitTestCase, name string, className string, setup *types.SetupReport, reporterConfig config.DefaultReporterConfigType) []JUnitTestCase {
+	if setup.State == types.SpecStatePassed.String() {
+		return testCases
+	}
+	testCase := JUnitTestCase{
+		Name:      name,
+		ClassName: className,
+		File:      setup.CodeLocation.FileName,
+		Line:      setup.CodeLocation.LineNumber,
+		Time:      setup.RunTime.Seconds(),
+	}
+	testCase.setFailure(setup.State, setup.Failure)
+	testCase.setOutput(setup.CapturedOutput, reporterConfig)
+	return append(testCases, testCase)
+}
+
+func newJUnitTestCase(spec types.SpecReport, suiteDescription string, reporterConfig config.DefaultReporterConfigType) JUnitTestCase {
+	testCase := JUnitTestCase{
+		Name:       strings.Join(spec.ComponentTexts[1:], " "),
+		ClassName:  className(spec, suiteDescription, reporterConfig.JUnitClassName),
+		Properties: specProperties(spec),
+	}
+	if len(spec.ComponentCodeLocations) > 0 {
+		location := spec.ComponentCodeLocations[len(spec.ComponentCodeLocations)-1]
+		testCase.File = location.FileName
+		testCase.Line = location.LineNumber
+	}
+	if reporterConfig.ReportPassed && spec.State == types.SpecStatePassed.String() {
+		testCase.setOutput(spec.CapturedOutput, reporterConfig)
+	}
+	if spec.HasQuarantinedFailure() {
+		testCase.Skipped = &JUnitSkipped{Message: quarantinedFailureMessage(spec.QuarantineReason) + "\n" + failureMessage(spec.Failure)}
+		testCase.setOutput(spec.CapturedOutput, reporterConfig)
+	} else if spec.HasFailureState() {
+		testCase.setFailure(spec.State, spec.Failure)
+		testCase.setOutput(spec.CapturedOutput, reporterConfig)
+	}
+	if spec.State == types.SpecStateSkipped.String() || spec.State == types.SpecStatePending.String() {
 		testCase.Skipped = &JUnitSkipped{}
-		if specSummary.Failure.Message != "" {
-			testCase.Skipped.Message = failureMessage(specSummary.Failure)
+		if spec.Failure != nil && spec.Failure.Message != "" {
+			testCase.Skipped.Message = failureMessage(spec.Failure)
+		}
+	}
+	testCase.Time = spec.RunTime.Seconds()
+	return testCase
+}
+
+//className returns the classname of a spec: the suite's description, the spec's top-level container or the path of the spec's containers.
+//Specs outside of any container fall back to the suite's description.
+func className(spec types.SpecReport, suiteDescription string, strategy string) string {
+	containers := []string{}
+	if len(spec.ComponentTexts) > 2 {
+		containers = spec.ComponentTexts[1 : len(spec.ComponentTexts)-1]
+	}
+	switch {
+	case strategy == "container" && len(containers) > 0:
+		return containers[0]
+	case strategy == "path" && len(containers) > 0:
+		return strings.Join(containers, " ")
+	default:
+		return suiteDescription
+	}
+}
+
+func specProperties(spec types.SpecReport) *JUnitProperties {
+	properties := []JUnitProperty{}
+	if spec.ID != "" {
+		properties = append(properties, JUnitProperty{Name: "id", Value: spec.ID})
+	}
+	if spec.ParallelNode > 0 {
+		properties = append(properties, JUnitProperty{Name: "node", Value: fmt.Sprintf("%d", spec.ParallelNode)})
+	}
+	names := []string{}
+	for name := range spec.Measurements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		measurement := spec.Measurements[name]
+		value := fmt.Sprintf(measurement.PrecisionFmt(), measurement.Average)
+		if measurement.Units != "" {
+			value += " " + measurement.Units
 		}
+		properties = append(properties, JUnitProperty{Name: "measurement: " + name, Value: value})
 	}
-	testCase.Time = specSummary.RunTime.Seconds()
-	reporter.suite.TestCases = append(reporter.suite.TestCases, testCase)
+	if len(properties) == 0 {
+		return nil
+	}
+	return &JUnitProperties{Properties: properties}
 }
 
 func (reporter *JUnitReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
-	reporter.suite.Tests = summary.NumberOfSpecsThatWillBeRun
-	reporter.suite.Time = math.Trunc(summary.RunTime.Seconds()*1000) / 1000
-	reporter.suite.Failures = summary.NumberOfFailedSpecs
-	reporter.suite.Errors = 0
+	reporter.recorder.SpecSuiteDidEnd(summary)
+	reporter.suite = NewJUnitTestSuite(reporter.recorder.Report(), reporter.ReporterConfig)
 	if reporter.ReporterConfig.ReportFile != "" {
 		reporter.filename = reporter.ReporterConfig.ReportFile
 		fmt.Printf("\nJUnit path was configured: %s\n", reporter.filename)
@@ -164,15 +371,50 @@ func (reporter *JUnitReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
 	}
 }
 
-func (reporter *JUnitReporter) failureTypeForState(state types.SpecState) string {
+func failureTypeForState(state string) string {
 	switch state {
-	case types.SpecStateFailed:
+	case types.SpecStateFailed.String():
 		return "Failure"
-	case types.SpecStateTimedOut:
+	case types.SpecStateTimedOut.String():
 		return "Timeout"
-	case types.SpecStatePanicked:
+	case types.SpecStatePanicked.String():
 		return "Panic"
 	default:
 		return ""
 	}
 }
+
+//WriteJUnitReport writes a <testsuites> document to filename, creating any missing parent directories, and returns the absolute path of the file
+func WriteJUnitReport(filename string, suites JUnitTestSuites) (string, error) {
+	filePath, err := filepath.Abs(filename)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	data, err := xml.MarshalIndent(suites, "  ", "    ")
+	if err != nil {
+		return "", err
+	}
+	return filePath, ioutil.WriteFile(filePath, append([]byte(xml.Header), data...), 0644)
+}
+
+//ReadJUnitReport reads the JUnit report in filename, which holds either a <testsuite>, as written by a suite run with go test, or a <testsuites> document, as written by the Ginkgo CLI
+func ReadJUnitReport(filename string) (JUnitTestSuites, error) {
+	suites := JUnitTestSuites{}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return suites, err
+	}
+	err = xml.Unmarshal(data, &suites)
+	if err != nil {
+		suite := JUnitTestSuite{}
+		if xml.Unmarshal(data, &suite) != nil {
+			return suites, fmt.Errorf("%s is not a JUnit report: %s", filename, err.Error())
+		}
+		suites.TestSuites = []JUnitTestSuite{suite}
+	}
+	return suites, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	. "github.com/hackrish007/ginkgo"
 	"github.com/hackrish007/ginkgo/config"
+	. "github.com/hackrish007/ginkgo/extensions/table"
 	"github.com/hackrish007/ginkgo/internal/codelocation"
 	"github.com/hackrish007/ginkgo/reporters"
 	"github.com/hackrish007/ginkgo/types"
@@ -65,6 +66,7 @@ var _ = Describe("JUnit Reporter", func() {
 			reporter.ReporterConfig.ReportPassed = true
 
 			spec := &types.SpecSummary{
+				ID:             "a_test.go:A/B/C",
 				ComponentTexts: []string{"[Top Level]", "A", "B", "C"},
 				CapturedOutput: "Test scenario...",
 				State:          types.SpecStatePassed,
@@ -94,6 +96,7 @@ var _ = Describe("JUnit Reporter", func() {
 			Expect(output.TestCases[0].Skipped).To(BeNil())
 			Expect(output.TestCases[0].Time).To(Equal(5.0))
 			Expect(output.TestCases[0].SystemOut).To(ContainSubstring("Test scenario"))
+			Expect(output.TestCases[0].Properties.Properties).To(Equal([]reporters.JUnitProperty{{Name: "id", Value: "a_test.go:A/B/C"}}))
 		})
 	})
 
@@ -276,28 +279,75 @@ var _ = Describe("JUnit Reporter", func() {
 				})
 			})
 
-			It("should record test as failing", func() {
+			It("should record test as failing, or as errored if it panicked or timed out", func() {
 				output := readOutputFile()
 				Expect(output.Name).To(Equal("My test suite"))
 				Expect(output.Tests).To(Equal(1))
-				Expect(output.Failures).To(Equal(1))
 				Expect(output.Time).To(Equal(reportedSuiteTime))
-				Expect(output.Errors).To(Equal(0))
 				Expect(output.TestCases[0].Name).To(Equal("A B C"))
 				Expect(output.TestCases[0].ClassName).To(Equal("My test suite"))
-				Expect(output.TestCases[0].FailureMessage.Type).To(Equal(specStateCase.message))
-				Expect(output.TestCases[0].FailureMessage.Message).To(ContainSubstring("I failed"))
-				Expect(output.TestCases[0].FailureMessage.Message).To(ContainSubstring(spec.Failure.ComponentCodeLocation.String()))
-				Expect(output.TestCases[0].FailureMessage.Message).To(ContainSubstring(spec.Failure.Location.String()))
 				Expect(output.TestCases[0].Skipped).To(BeNil())
+
+				failure := output.TestCases[0].FailureMessage
+				if specStateCase.state == types.SpecStateFailed {
+					Expect(output.Failures).To(Equal(1))
+					Expect(output.Errors).To(Equal(0))
+					Expect(output.TestCases[0].ErrorMessage).To(BeNil())
+				} else {
+					Expect(output.Failures).To(Equal(0))
+					Expect(output.Errors).To(Equal(1))
+					Expect(failure).To(BeNil())
+					failure = output.TestCases[0].ErrorMessage
+				}
+				Expect(failure.Type).To(Equal(specStateCase.message))
+				Expect(failure.Message).To(ContainSubstring("I failed"))
+				Expect(failure.Message).To(ContainSubstring(spec.Failure.ComponentCodeLocation.String()))
+				Expect(failure.Message).To(ContainSubstring(spec.Failure.Location.String()))
 				if specStateCase.state == types.SpecStatePanicked {
-					Expect(output.TestCases[0].FailureMessage.Message).To(ContainSubstring("\nPanic: " + specStateCase.forwardedPanic + "\n"))
-					Expect(output.TestCases[0].FailureMessage.Message).To(ContainSubstring("\nFull stack:\n" + spec.Failure.Location.FullStackTrace))
+					Expect(failure.Message).To(ContainSubstring("\nPanic: " + specStateCase.forwardedPanic + "\n"))
+					Expect(failure.Message).To(ContainSubstring("\nFull stack:\n" + spec.Failure.Location.FullStackTrace))
 				}
 			})
 		})
 	}
 
+	Describe("a quarantined failing test", func() {
+		var spec *types.SpecSummary
+		BeforeEach(func() {
+			spec = &types.SpecSummary{
+				ComponentTexts:   []string{"[Top Level]", "A", "B", "C"},
+				State:            types.SpecStateFailed,
+				RunTime:          5 * time.Second,
+				CapturedOutput:   "some output",
+				Quarantined:      true,
+				QuarantineReason: "ISSUE-123",
+				Failure: types.SpecFailure{
+					ComponentCodeLocation: codelocation.New(0),
+					Location:              codelocation.New(2),
+					Message:               "I failed",
+				},
+			}
+			reporter.SpecWillRun(spec)
+			reporter.SpecDidComplete(spec)
+
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{
+				NumberOfSpecsThatWillBeRun:  1,
+				NumberOfQuarantinedFailures: 1,
+				RunTime:                     testSuiteTime,
+			})
+		})
+
+		It("should record the test as skipped, with the failure", func() {
+			output := readOutputFile()
+			Expect(output.Tests).To(Equal(1))
+			Expect(output.Failures).To(Equal(0))
+			Expect(output.TestCases[0].FailureMessage).To(BeNil())
+			Expect(output.TestCases[0].Skipped.Message).To(HavePrefix("Quarantined failure (ISSUE-123)\n"))
+			Expect(output.TestCases[0].Skipped.Message).To(ContainSubstring("I failed"))
+			Expect(output.TestCases[0].SystemOut).To(Equal("some output"))
+		})
+	})
+
 	for _, specStateCase := range []types.SpecState{types.SpecStatePending, types.SpecStateSkipped} {
 		specStateCase := specStateCase
 		Describe("a skipped test", func() {
@@ -333,3 +383,186 @@ var _ = Describe("JUnit Reporter", func() {
 		})
 	}
 })
+
+var _ = Describe("JUnit test suites", func() {
+	It("reports each suite of a run as a test suite, and suites that failed to compile as errored suites", func() {
+		passed := types.NewSpecReport(&types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "A"},
+			State:          types.SpecStatePassed,
+		}, time.Now())
+		failed := types.NewSpecReport(&types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "B"},
+			State:          types.SpecStateFailed,
+			Failure:        types.SpecFailure{Message: "I failed"},
+		}, time.Now())
+
+		suites := reporters.NewJUnitTestSuites([]types.SuiteReport{
+			{
+				SuiteDescription:           "Suite A",
+				NumberOfSpecsThatWillBeRun: 2,
+				NumberOfFailedSpecs:        1,
+				RunTime:                    1500 * time.Millisecond,
+				Specs:                      []types.SpecReport{passed, failed},
+			},
+			{
+				SuiteDescription:   "b",
+				CompilationFailure: "Failed to compile b",
+			},
+		}, config.DefaultReporterConfigType{})
+
+		Ω(suites.Tests).Should(Equal(2))
+		Ω(suites.Failures).Should(Equal(1))
+		Ω(suites.Errors).Should(Equal(1))
+		Ω(suites.Time).Should(Equal(1.5))
+		Ω(suites.TestSuites).Should(HaveLen(2))
+
+		Ω(suites.TestSuites[0].Name).Should(Equal("Suite A"))
+		Ω(suites.TestSuites[0].TestCases).Should(HaveLen(2))
+		Ω(suites.TestSuites[0].TestCases[1].FailureMessage.Message).Should(ContainSubstring("I failed"))
+
+		Ω(suites.TestSuites[1].Name).Should(Equal("b"))
+		Ω(suites.TestSuites[1].Errors).Should(Equal(1))
+		Ω(suites.TestSuites[1].TestCases[0].ErrorMessage.Message).Should(Equal("Failed to compile b"))
+	})
+
+	It("writes a <testsuites> document", func() {
+		dir, err := ioutil.TempDir("", "junit-suites")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path, err := reporters.WriteJUnitReport(dir+"/reports/junit.xml", reporters.NewJUnitTestSuites([]types.SuiteReport{{SuiteDescription: "Suite A"}}, config.DefaultReporterConfigType{}))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		data, err := ioutil.ReadFile(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		var suites reporters.JUnitTestSuites
+		Ω(xml.Unmarshal(data, &suites)).Should(Succeed())
+		Ω(suites.TestSuites).Should(HaveLen(1))
+		Ω(suites.TestSuites[0].Name).Should(Equal("Suite A"))
+	})
+
+	It("reads <testsuites> documents and lone <testsuite>s", func() {
+		dir, err := ioutil.TempDir("", "junit-suites")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path, err := reporters.WriteJUnitReport(dir+"/suites.xml", reporters.NewJUnitTestSuites([]types.SuiteReport{{SuiteDescription: "Suite A"}, {SuiteDescription: "Suite B"}}, config.DefaultReporterConfigType{}))
+		Ω(err).ShouldNot(HaveOccurred())
+		suites, err := reporters.ReadJUnitReport(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(suites.TestSuites).Should(HaveLen(2))
+
+		data, err := xml.Marshal(reporters.NewJUnitTestSuite(types.SuiteReport{SuiteDescription: "Suite C"}, config.DefaultReporterConfigType{}))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(ioutil.WriteFile(dir+"/suite.xml", data, 0644)).Should(Succeed())
+		suites, err = reporters.ReadJUnitReport(dir + "/suite.xml")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(suites.TestSuites).Should(HaveLen(1))
+		Ω(suites.TestSuites[0].Name).Should(Equal("Suite C"))
+
+		Ω(ioutil.WriteFile(dir+"/nope.xml", []byte("{}"), 0644)).Should(Succeed())
+		_, err = reporters.ReadJUnitReport(dir + "/nope.xml")
+		Ω(err).Should(MatchError(ContainSubstring("is not a JUnit report")))
+	})
+})
+
+var _ = Describe("JUnit test suite", func() {
+	var (
+		report         types.SuiteReport
+		reporterConfig config.DefaultReporterConfigType
+	)
+
+	propertyValue := func(properties *reporters.JUnitProperties, name string) string {
+		for _, property := range properties.Properties {
+			if property.Name == name {
+				return property.Value
+			}
+		}
+		return "<missing>"
+	}
+
+	BeforeEach(func() {
+		reporterConfig = config.DefaultReporterConfigType{}
+		report = types.SuiteReport{
+			SuiteDescription: "My test suite",
+			SuiteSucceeded:   true,
+			Hostname:         "build-host",
+			GoVersion:        "go1.16",
+			StartTime:        time.Date(2021, 6, 1, 10, 30, 0, 0, time.Local),
+			Config: types.SuiteReportConfig{
+				RandomSeed:    17,
+				FocusStrings:  []string{"A", "B"},
+				ParallelTotal: 3,
+			},
+			NumberOfSpecsThatWillBeRun: 2,
+			Specs: []types.SpecReport{
+				types.NewSpecReport(&types.SpecSummary{
+					ID:             "a_test.go:A/B/C",
+					ComponentTexts: []string{"[Top Level]", "A", "B", "C"},
+					ComponentCodeLocations: []types.CodeLocation{
+						{FileName: "suite_test.go", LineNumber: 1},
+						{FileName: "a_test.go", LineNumber: 3},
+						{FileName: "a_test.go", LineNumber: 5},
+						{FileName: "a_test.go", LineNumber: 7},
+					},
+					State:          types.SpecStatePassed,
+					CapturedOutput: "some output",
+					ParallelNode:   2,
+					Measurements: map[string]*types.SpecMeasurement{
+						"speed": {Name: "speed", Average: 1.5, Units: "ms", Precision: 2},
+					},
+				}, time.Now()),
+				types.NewSpecReport(&types.SpecSummary{
+					ComponentTexts: []string{"[Top Level]", "D"},
+					State:          types.SpecStateSkipped,
+				}, time.Now()),
+			},
+		}
+	})
+
+	It("describes the run of the suite", func() {
+		suite := reporters.NewJUnitTestSuite(report, reporterConfig)
+		Ω(suite.Timestamp).Should(Equal("2021-06-01T10:30:00"))
+		Ω(suite.Hostname).Should(Equal("build-host"))
+		Ω(suite.Skipped).Should(Equal(1))
+		Ω(propertyValue(suite.Properties, "SuiteSucceeded")).Should(Equal("true"))
+		Ω(propertyValue(suite.Properties, "RandomSeed")).Should(Equal("17"))
+		Ω(propertyValue(suite.Properties, "FocusStrings")).Should(Equal("A,B"))
+		Ω(propertyValue(suite.Properties, "ParallelTotal")).Should(Equal("3"))
+		Ω(propertyValue(suite.Properties, "GoVersion")).Should(Equal("go1.16"))
+	})
+
+	It("records where each spec is defined, and what node ran it and what it measured", func() {
+		testCase := reporters.NewJUnitTestSuite(report, reporterConfig).TestCases[0]
+		Ω(testCase.File).Should(Equal("a_test.go"))
+		Ω(testCase.Line).Should(Equal(7))
+		Ω(propertyValue(testCase.Properties, "id")).Should(Equal("a_test.go:A/B/C"))
+		Ω(propertyValue(testCase.Properties, "node")).Should(Equal("2"))
+		Ω(propertyValue(testCase.Properties, "measurement: speed")).Should(Equal("1.50 ms"))
+	})
+
+	It("puts captured output in system-err when configured to", func() {
+		reporterConfig.ReportPassed = true
+		testCase := reporters.NewJUnitTestSuite(report, reporterConfig).TestCases[0]
+		Ω(testCase.SystemOut).Should(Equal("some output"))
+		Ω(testCase.SystemErr).Should(BeEmpty())
+
+		reporterConfig.JUnitSystemErr = true
+		testCase = reporters.NewJUnitTestSuite(report, reporterConfig).TestCases[0]
+		Ω(testCase.SystemOut).Should(BeEmpty())
+		Ω(testCase.SystemErr).Should(Equal("some output"))
+	})
+
+	DescribeTable("picking the classname of specs",
+		func(strategy string, nested string, topLevel string) {
+			reporterConfig.JUnitClassName = strategy
+			suite := reporters.NewJUnitTestSuite(report, reporterConfig)
+			Ω(suite.TestCases[0].ClassName).Should(Equal(nested))
+			Ω(suite.TestCases[1].ClassName).Should(Equal(topLevel))
+		},
+		Entry("by default, the suite's description", "", "My test suite", "My test suite"),
+		Entry("the suite's description", "suite", "My test suite", "My test suite"),
+		Entry("the top-level container", "container", "A", "My test suite"),
+		Entry("the path of containers", "path", "A B", "My test suite"),
+	)
+})
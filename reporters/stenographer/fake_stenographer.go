@@ -78,6 +78,10 @@ func (stenographer *FakeStenographer) AnnounceParallelRun(node int, nodes int, s
 	stenographer.registerCall("AnnounceParallelRun", node, nodes, succinct)
 }
 
+func (stenographer *FakeStenographer) AnnounceShard(shard int, shards int, succinct bool) {
+	stenographer.registerCall("AnnounceShard", shard, shards, succinct)
+}
+
 func (stenographer *FakeStenographer) AnnounceNumberOfSpecs(specsToRun int, total int, succinct bool) {
 	stenographer.registerCall("AnnounceNumberOfSpecs", specsToRun, total, succinct)
 }
@@ -105,6 +109,10 @@ func (stenographer *FakeStenographer) AnnounceCapturedOutput(output string) {
 	stenographer.registerCall("AnnounceCapturedOutput", output)
 }
 
+func (stenographer *FakeStenographer) AnnounceLiveOutput(chunk *types.SpecOutputChunk, announceSpec bool) {
+	stenographer.registerCall("AnnounceLiveOutput", chunk, announceSpec)
+}
+
 func (stenographer *FakeStenographer) AnnounceSuccessfulSpec(spec *types.SpecSummary) {
 	stenographer.registerCall("AnnounceSuccessfulSpec", spec)
 }
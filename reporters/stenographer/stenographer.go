@@ -61,6 +61,17 @@ type Stenographer interface {
 	SummarizeFailures(summaries []*types.SpecSummary)
 }
 
+//ShardStenographer is implemented by stenographers that can announce which shard of the suite is running.
+//It is separate from Stenographer so that existing implementations keep compiling; reporters check for it with a type assertion.
+type ShardStenographer interface {
+	AnnounceShard(shard int, shards int, succinct bool)
+}
+
+//LiveOutputStenographer is implemented by stenographers that can show what specs on parallel nodes write while they are still running.
+type LiveOutputStenographer interface {
+	AnnounceLiveOutput(chunk *types.SpecOutputChunk, announceSpec bool)
+}
+
 func New(color bool, enableFlakes bool, writer io.Writer) Stenographer {
 	denoter := "•"
 	if runtime.GOOS == "windows" {
@@ -123,6 +134,19 @@ func (s *consoleStenographer) AnnounceAggregatedParallelRun(nodes int, succinct
 	s.printNewLine()
 }
 
+func (s *consoleStenographer) AnnounceShard(shard int, shards int, succinct bool) {
+	if succinct {
+		s.print(0, "- shard %d/%d ", shard, shards)
+		return
+	}
+	s.println(0,
+		"Running shard %s/%s",
+		s.colorize(boldStyle, "%d", shard),
+		s.colorize(boldStyle, "%d", shards),
+	)
+	s.printNewLine()
+}
+
 func (s *consoleStenographer) AnnounceNumberOfSpecs(specsToRun int, total int, succinct bool) {
 	if succinct {
 		s.print(0, "- %d/%d specs ", specsToRun, total)
@@ -176,11 +200,16 @@ func (s *consoleStenographer) AnnounceSpecRunCompletion(summary *types.SuiteSumm
 		flakes = " | " + s.colorize(yellowColor+boldStyle, "%d Flaked", summary.NumberOfFlakedSpecs)
 	}
 
+	quarantined := ""
+	if summary.NumberOfQuarantinedFailures > 0 {
+		quarantined = " | " + s.colorize(yellowColor+boldStyle, "%d Quarantined", summary.NumberOfQuarantinedFailures)
+	}
+
 	s.print(0,
 		"%s -- %s | %s | %s | %s\n",
 		status,
 		s.colorize(greenColor+boldStyle, "%d Passed", summary.NumberOfPassedSpecs),
-		s.colorize(redColor+boldStyle, "%d Failed", summary.NumberOfFailedSpecs)+flakes,
+		s.colorize(redColor+boldStyle, "%d Failed", summary.NumberOfFailedSpecs)+quarantined+flakes,
 		s.colorize(yellowColor+boldStyle, "%d Pending", summary.NumberOfPendingSpecs),
 		s.colorize(cyanColor+boldStyle, "%d Skipped", summary.NumberOfSkippedSpecs),
 	)
@@ -245,6 +274,23 @@ func (s *consoleStenographer) AnnounceCapturedOutput(output string) {
 	s.midBlock()
 }
 
+func (s *consoleStenographer) AnnounceLiveOutput(chunk *types.SpecOutputChunk, announceSpec bool) {
+	node := s.colorize(lightGrayColor, "[node %d]", chunk.ParallelNode)
+	if announceSpec {
+		s.startBlock()
+		text := ""
+		if len(chunk.ComponentTexts) > 1 {
+			text = strings.Join(chunk.ComponentTexts[1:], " ")
+		}
+		s.println(0, "%s %s", node, s.colorize(boldStyle, "%s", text))
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(chunk.Output, "\n"), "\n") {
+		s.println(0, "%s %s", node, line)
+	}
+	s.midBlock()
+}
+
 func (s *consoleStenographer) AnnounceSuccessfulSpec(spec *types.SpecSummary) {
 	s.print(0, s.colorize(greenColor, s.denoter))
 	s.stream()
@@ -313,27 +359,26 @@ func (s *consoleStenographer) AnnounceSpecFailed(spec *types.SpecSummary, succin
 
 func (s *consoleStenographer) SummarizeFailures(summaries []*types.SpecSummary) {
 	failingSpecs := []*types.SpecSummary{}
+	quarantinedSpecs := []*types.SpecSummary{}
 
 	for _, summary := range summaries {
-		if summary.HasFailureState() {
+		if summary.HasQuarantinedFailure() {
+			quarantinedSpecs = append(quarantinedSpecs, summary)
+		} else if summary.HasFailureState() {
 			failingSpecs = append(failingSpecs, summary)
 		}
 	}
 
-	if len(failingSpecs) == 0 {
-		return
-	}
-
-	s.printNewLine()
-	s.printNewLine()
-	plural := "s"
-	if len(failingSpecs) == 1 {
-		plural = ""
-	}
-	s.println(0, s.colorize(redColor+boldStyle, "Summarizing %d Failure%s:", len(failingSpecs), plural))
-	for _, summary := range failingSpecs {
+	if len(failingSpecs) > 0 {
+		s.printNewLine()
 		s.printNewLine()
-		if summary.HasFailureState() {
+		plural := "s"
+		if len(failingSpecs) == 1 {
+			plural = ""
+		}
+		s.println(0, s.colorize(redColor+boldStyle, "Summarizing %d Failure%s:", len(failingSpecs), plural))
+		for _, summary := range failingSpecs {
+			s.printNewLine()
 			if summary.TimedOut() {
 				s.print(0, s.colorize(redColor+boldStyle, "[Timeout...] "))
 			} else if summary.Panicked() {
@@ -346,6 +391,26 @@ func (s *consoleStenographer) SummarizeFailures(summaries []*types.SpecSummary)
 			s.println(0, s.colorize(lightGrayColor, summary.Failure.Location.String()))
 		}
 	}
+
+	if len(quarantinedSpecs) > 0 {
+		s.printNewLine()
+		s.printNewLine()
+		plural := "s"
+		if len(quarantinedSpecs) == 1 {
+			plural = ""
+		}
+		s.println(0, s.colorize(yellowColor+boldStyle, "Summarizing %d Quarantined Failure%s:", len(quarantinedSpecs), plural))
+		for _, summary := range quarantinedSpecs {
+			s.printNewLine()
+			s.print(0, s.colorize(yellowColor+boldStyle, "[Quarantined] "))
+			s.printSpecContext(summary.ComponentTexts, summary.ComponentCodeLocations, summary.Failure.ComponentType, summary.Failure.ComponentIndex, summary.State, true)
+			s.printNewLine()
+			s.println(0, s.colorize(lightGrayColor, summary.Failure.Location.String()))
+			if summary.QuarantineReason != "" {
+				s.println(0, s.colorize(yellowColor, "Reason: %s", summary.QuarantineReason))
+			}
+		}
+	}
 }
 
 func (s *consoleStenographer) startBlock() {
@@ -386,12 +451,20 @@ func (s *consoleStenographer) printBlockWithMessage(header string, message strin
 
 func (s *consoleStenographer) printSpecFailure(message string, spec *types.SpecSummary, succinct bool, fullTrace bool) {
 	s.startBlock()
-	s.println(0, s.colorize(redColor+boldStyle, "%s%s [%.3f seconds]", message, s.failureContext(spec.Failure.ComponentType), spec.RunTime.Seconds()))
+	header := s.colorize(redColor+boldStyle, "%s%s [%.3f seconds]", message, s.failureContext(spec.Failure.ComponentType), spec.RunTime.Seconds())
+	if spec.Quarantined {
+		header += s.colorize(yellowColor+boldStyle, " [QUARANTINED]")
+	}
+	s.println(0, header)
 
 	indentation := s.printCodeLocationBlock(spec.ComponentTexts, spec.ComponentCodeLocations, spec.Failure.ComponentType, spec.Failure.ComponentIndex, spec.State, succinct)
 
 	s.printNewLine()
 	s.printFailure(indentation, spec.State, spec.Failure, fullTrace)
+	if spec.Quarantined && spec.QuarantineReason != "" {
+		s.printNewLine()
+		s.println(indentation, s.colorize(yellowColor, "Quarantined: %s", spec.QuarantineReason))
+	}
 	s.endBlock()
 }
 
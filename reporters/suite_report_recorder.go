@@ -0,0 +1,189 @@
+package reporters
+
+import (
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+/*
+SuiteReportRecorder is a reporter that records a types.SuiteReport of a run of a suite.
+
+It may be handed the reports of several parallel nodes running the same suite, either as they arrive (as the Ginkgo CLI's server does) or,
+with AddReport, as complete reports of each node.  The recorded report then covers the whole suite: it holds every node's specs and the sum of their counts,
+and its BeforeSuite and AfterSuite are those of the first node to report them, unless another node's failed.
+*/
+type SuiteReportRecorder struct {
+	report types.SuiteReport
+	began  bool
+	ended  bool
+	lock   *sync.Mutex
+}
+
+func NewSuiteReportRecorder() *SuiteReportRecorder {
+	return &SuiteReportRecorder{
+		lock: &sync.Mutex{},
+	}
+}
+
+//Report returns the report recorded so far
+func (recorder *SuiteReportRecorder) Report() types.SuiteReport {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	report := recorder.report
+	report.Specs = append([]types.SpecReport{}, recorder.report.Specs...)
+	return report
+}
+
+//SetSuitePath overrides the suite path, which otherwise is the working directory of the process recording the report
+func (recorder *SuiteReportRecorder) SetSuitePath(path string) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.report.SuitePath = path
+}
+
+func (recorder *SuiteReportRecorder) SpecSuiteWillBegin(ginkgoConfig config.GinkgoConfigType, summary *types.SuiteSummary) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	if recorder.began {
+		return
+	}
+	recorder.began = true
+
+	suitePath := recorder.report.SuitePath
+	if suitePath == "" {
+		suitePath, _ = os.Getwd()
+	}
+	hostname, _ := os.Hostname()
+	recorder.report = types.SuiteReport{
+		Version:   types.JSONReportVersion,
+		SuitePath: suitePath,
+		Hostname:  hostname,
+		GoVersion: runtime.Version(),
+		Config: types.SuiteReportConfig{
+			RandomSeed:        ginkgoConfig.RandomSeed,
+			RandomizeAllSpecs: ginkgoConfig.RandomizeAllSpecs,
+			FocusStrings:      ginkgoConfig.FocusStrings,
+			SkipStrings:       ginkgoConfig.SkipStrings,
+			FocusFiles:        ginkgoConfig.FocusFiles,
+			SkipFiles:         ginkgoConfig.SkipFiles,
+			SkipMeasurements:  ginkgoConfig.SkipMeasurements,
+			FailOnPending:     ginkgoConfig.FailOnPending,
+			FailFast:          ginkgoConfig.FailFast,
+			FlakeAttempts:     ginkgoConfig.FlakeAttempts,
+			DryRun:            ginkgoConfig.DryRun,
+			ParallelTotal:     ginkgoConfig.ParallelTotal,
+			ShardIndex:        ginkgoConfig.ShardIndex,
+			ShardTotal:        ginkgoConfig.ShardTotal,
+		},
+		StartTime: time.Now(),
+		Specs:     []types.SpecReport{},
+	}
+	if summary != nil {
+		recorder.report.SuiteDescription = summary.SuiteDescription
+	}
+}
+
+func (recorder *SuiteReportRecorder) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.report.BeforeSuite = preferredSetupReport(recorder.report.BeforeSuite, types.NewSetupReport(setupSummary, time.Now()))
+}
+
+func (recorder *SuiteReportRecorder) SpecWillRun(specSummary *types.SpecSummary) {
+}
+
+func (recorder *SuiteReportRecorder) SpecDidComplete(specSummary *types.SpecSummary) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.report.Specs = append(recorder.report.Specs, types.NewSpecReport(specSummary, time.Now()))
+}
+
+func (recorder *SuiteReportRecorder) AfterSuiteDidRun(setupSummary *types.SetupSummary) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.report.AfterSuite = preferredSetupReport(recorder.report.AfterSuite, types.NewSetupReport(setupSummary, time.Now()))
+}
+
+func (recorder *SuiteReportRecorder) SpecSuiteDidEnd(summary *types.SuiteSummary) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.addEnding(types.SuiteReport{
+		SuiteSucceeded:              summary.SuiteSucceeded,
+		EndTime:                     time.Now(),
+		RunTime:                     summary.RunTime,
+		NumberOfTotalSpecs:          summary.NumberOfTotalSpecs,
+		NumberOfSpecsThatWillBeRun:  summary.NumberOfSpecsThatWillBeRun,
+		NumberOfPassedSpecs:         summary.NumberOfPassedSpecs,
+		NumberOfFailedSpecs:         summary.NumberOfFailedSpecs,
+		NumberOfPendingSpecs:        summary.NumberOfPendingSpecs,
+		NumberOfSkippedSpecs:        summary.NumberOfSkippedSpecs,
+		NumberOfFlakedSpecs:         summary.NumberOfFlakedSpecs,
+		NumberOfQuarantinedFailures: summary.NumberOfQuarantinedFailures,
+	})
+}
+
+//AddReport adds the complete report of a parallel node, e.g. one read from a file written by a JSONReporter
+func (recorder *SuiteReportRecorder) AddReport(report types.SuiteReport) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	if !recorder.began {
+		recorder.began = true
+		recorder.ended = true
+		suitePath := recorder.report.SuitePath
+		recorder.report = report
+		recorder.report.Specs = append([]types.SpecReport{}, report.Specs...)
+		if suitePath != "" {
+			recorder.report.SuitePath = suitePath
+		}
+		return
+	}
+
+	if report.StartTime.Before(recorder.report.StartTime) {
+		recorder.report.StartTime = report.StartTime
+	}
+	recorder.report.BeforeSuite = preferredSetupReport(recorder.report.BeforeSuite, report.BeforeSuite)
+	recorder.report.AfterSuite = preferredSetupReport(recorder.report.AfterSuite, report.AfterSuite)
+	recorder.report.Specs = append(recorder.report.Specs, report.Specs...)
+	recorder.addEnding(report)
+}
+
+//addEnding adds the outcome and counts of a node's run of the suite.  It must be called with the lock held.
+func (recorder *SuiteReportRecorder) addEnding(ending types.SuiteReport) {
+	report := &recorder.report
+	if !recorder.ended {
+		recorder.ended = true
+		report.SuiteSucceeded = ending.SuiteSucceeded
+	} else {
+		report.SuiteSucceeded = report.SuiteSucceeded && ending.SuiteSucceeded
+	}
+	if ending.EndTime.After(report.EndTime) {
+		report.EndTime = ending.EndTime
+	}
+	if ending.RunTime > report.RunTime {
+		report.RunTime = ending.RunTime
+	}
+	report.NumberOfTotalSpecs += ending.NumberOfTotalSpecs
+	report.NumberOfSpecsThatWillBeRun += ending.NumberOfSpecsThatWillBeRun
+	report.NumberOfPassedSpecs += ending.NumberOfPassedSpecs
+	report.NumberOfFailedSpecs += ending.NumberOfFailedSpecs
+	report.NumberOfPendingSpecs += ending.NumberOfPendingSpecs
+	report.NumberOfSkippedSpecs += ending.NumberOfSkippedSpecs
+	report.NumberOfFlakedSpecs += ending.NumberOfFlakedSpecs
+	report.NumberOfQuarantinedFailures += ending.NumberOfQuarantinedFailures
+}
+
+//preferredSetupReport picks the BeforeSuite or AfterSuite to report out of those of two nodes: the current one, unless only the other one failed
+func preferredSetupReport(current *types.SetupReport, other *types.SetupReport) *types.SetupReport {
+	if current == nil {
+		return other
+	}
+	if other != nil && other.HasFailureState() && !current.HasFailureState() {
+		return other
+	}
+	return current
+}
@@ -0,0 +1,115 @@
+package reporters_test
+
+import (
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/reporters"
+	"github.com/hackrish007/ginkgo/types"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("SuiteReportRecorder", func() {
+	var recorder *reporters.SuiteReportRecorder
+
+	spec := func(text string, state types.SpecState, node int) *types.SpecSummary {
+		return &types.SpecSummary{
+			ID:             "a_test.go:" + text,
+			ComponentTexts: []string{"[Top Level]", text},
+			State:          state,
+			ParallelNode:   node,
+		}
+	}
+
+	BeforeEach(func() {
+		recorder = reporters.NewSuiteReportRecorder()
+		recorder.SetSuitePath("/path/to/suite")
+	})
+
+	Context("when handed the reports of several parallel nodes as they arrive", func() {
+		BeforeEach(func() {
+			for node := 1; node <= 2; node++ {
+				recorder.SpecSuiteWillBegin(config.GinkgoConfigType{RandomSeed: 17, ParallelNode: node, ParallelTotal: 2}, &types.SuiteSummary{SuiteDescription: "My test suite"})
+			}
+			recorder.BeforeSuiteDidRun(&types.SetupSummary{State: types.SpecStatePassed, ParallelNode: 1})
+			recorder.BeforeSuiteDidRun(&types.SetupSummary{State: types.SpecStateFailed, ParallelNode: 2, Failure: types.SpecFailure{Message: "node 2 failed"}})
+			recorder.SpecDidComplete(spec("A", types.SpecStatePassed, 1))
+			recorder.SpecDidComplete(spec("B", types.SpecStateFailed, 2))
+			recorder.AfterSuiteDidRun(&types.SetupSummary{State: types.SpecStatePassed, ParallelNode: 2})
+			recorder.AfterSuiteDidRun(&types.SetupSummary{State: types.SpecStatePassed, ParallelNode: 1})
+			recorder.SpecSuiteDidEnd(&types.SuiteSummary{SuiteSucceeded: true, NumberOfSpecsThatWillBeRun: 1, NumberOfPassedSpecs: 1, RunTime: time.Second})
+			recorder.SpecSuiteDidEnd(&types.SuiteSummary{SuiteSucceeded: false, NumberOfSpecsThatWillBeRun: 1, NumberOfFailedSpecs: 1, RunTime: 2 * time.Second})
+		})
+
+		It("records a single report of the suite", func() {
+			report := recorder.Report()
+			Ω(report.Version).Should(Equal(types.JSONReportVersion))
+			Ω(report.SuiteDescription).Should(Equal("My test suite"))
+			Ω(report.SuitePath).Should(Equal("/path/to/suite"))
+			Ω(report.Config.RandomSeed).Should(BeEquivalentTo(17))
+			Ω(report.Config.ParallelTotal).Should(Equal(2))
+			Ω(report.Specs).Should(HaveLen(2))
+			Ω(report.Specs[0].ParallelNode).Should(Equal(1))
+			Ω(report.Specs[1].ParallelNode).Should(Equal(2))
+		})
+
+		It("adds up the nodes' outcomes", func() {
+			report := recorder.Report()
+			Ω(report.SuiteSucceeded).Should(BeFalse())
+			Ω(report.NumberOfSpecsThatWillBeRun).Should(Equal(2))
+			Ω(report.NumberOfPassedSpecs).Should(Equal(1))
+			Ω(report.NumberOfFailedSpecs).Should(Equal(1))
+			Ω(report.RunTime).Should(Equal(2 * time.Second))
+		})
+
+		It("reports the first BeforeSuite and AfterSuite, unless another node's failed", func() {
+			report := recorder.Report()
+			Ω(report.BeforeSuite.ParallelNode).Should(Equal(2))
+			Ω(report.BeforeSuite.Failure.Message).Should(Equal("node 2 failed"))
+			Ω(report.AfterSuite.ParallelNode).Should(Equal(2))
+		})
+	})
+
+	Context("when handed the complete reports of several parallel nodes", func() {
+		var start time.Time
+
+		BeforeEach(func() {
+			start = time.Now()
+			recorder.AddReport(types.SuiteReport{
+				Version:             types.JSONReportVersion,
+				SuiteDescription:    "My test suite",
+				SuitePath:           "/node/working/directory",
+				SuiteSucceeded:      true,
+				StartTime:           start.Add(time.Second),
+				EndTime:             start.Add(3 * time.Second),
+				RunTime:             2 * time.Second,
+				NumberOfPassedSpecs: 1,
+				Specs:               []types.SpecReport{types.NewSpecReport(spec("A", types.SpecStatePassed, 1), start)},
+			})
+			recorder.AddReport(types.SuiteReport{
+				Version:             types.JSONReportVersion,
+				SuiteDescription:    "My test suite",
+				SuiteSucceeded:      false,
+				StartTime:           start,
+				EndTime:             start.Add(2 * time.Second),
+				RunTime:             2 * time.Second,
+				NumberOfFailedSpecs: 1,
+				Specs:               []types.SpecReport{types.NewSpecReport(spec("B", types.SpecStateFailed, 2), start)},
+			})
+		})
+
+		It("combines them into a single report of the suite", func() {
+			report := recorder.Report()
+			Ω(report.SuiteDescription).Should(Equal("My test suite"))
+			Ω(report.SuitePath).Should(Equal("/path/to/suite"))
+			Ω(report.SuiteSucceeded).Should(BeFalse())
+			Ω(report.StartTime).Should(BeTemporally("==", start))
+			Ω(report.EndTime).Should(BeTemporally("==", start.Add(3*time.Second)))
+			Ω(report.NumberOfPassedSpecs).Should(Equal(1))
+			Ω(report.NumberOfFailedSpecs).Should(Equal(1))
+			Ω(report.Specs).Should(HaveLen(2))
+			Ω(report.Specs[1].HasFailureState()).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,205 @@
+/*
+
+TAP Reporter for Ginkgo
+
+Writes a Test Anything Protocol document describing the run of a suite: a plan, then a test point for each spec, along with any failed BeforeSuite or AfterSuite.
+Skipped specs carry a SKIP directive and pending specs, and failures of quarantined specs, a TODO directive.  Failures are described by a YAML diagnostic block.
+
+A document describing a single suite is a TAP version 13 document.  A document describing several suites is a TAP version 14 document with a subtest per suite.
+https://testanything.org/tap-version-13-specification.html
+https://testanything.org/tap-version-14-specification.html
+
+*/
+
+package reporters
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hackrish007/ginkgo/types"
+)
+
+type TAPReporter struct {
+	*SuiteReportRecorder
+	filename string
+}
+
+//NewTAPReporter creates a new TAP reporter.  The report will be stored in the passed in filename.
+func NewTAPReporter(filename string) *TAPReporter {
+	return &TAPReporter{
+		SuiteReportRecorder: NewSuiteReportRecorder(),
+		filename:            filename,
+	}
+}
+
+func (reporter *TAPReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
+	reporter.SuiteReportRecorder.SpecSuiteDidEnd(summary)
+
+	_, err := WriteTAPReport(reporter.filename, []types.SuiteReport{reporter.Report()})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nFailed to generate TAP report:\n\t%s\n", err.Error())
+	}
+}
+
+//WriteTAPReport writes a TAP document describing reports to filename, creating any missing parent directories, and returns the absolute path of the file
+func WriteTAPReport(filename string, reports []types.SuiteReport) (string, error) {
+	filePath, err := filepath.Abs(filename)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	return filePath, ioutil.WriteFile(filePath, []byte(NewTAPDocument(reports)), 0644)
+}
+
+//NewTAPDocument returns a TAP document describing reports
+func NewTAPDocument(reports []types.SuiteReport) string {
+	if len(reports) == 1 {
+		return "TAP version 13\n" + tapTestPoints(reports[0], "")
+	}
+
+	document := "TAP version 14\n"
+	document += fmt.Sprintf("1..%d\n", len(reports))
+	for i, report := range reports {
+		document += fmt.Sprintf("# Subtest: %s\n", tapDescription(report.SuiteDescription))
+		document += tapTestPoints(report, "    ")
+		succeeded := report.SuiteSucceeded && report.CompilationFailure == ""
+		document += tapTestPoint{ok: succeeded, description: report.SuiteDescription}.format(i+1, "")
+	}
+	return document
+}
+
+type tapTestPoint struct {
+	ok          bool
+	description string
+	directive   string
+	diagnostics string
+}
+
+//tapTestPoints returns the plan and the test points of a suite, each line prefixed with indent
+func tapTestPoints(report types.SuiteReport, indent string) string {
+	points := []tapTestPoint{}
+	if report.CompilationFailure != "" {
+		points = append(points, tapTestPoint{
+			description: "Compilation",
+			diagnostics: tapDiagnostics(report.CompilationFailure, "failed", nil, 0, ""),
+		})
+	}
+	if report.BeforeSuite != nil && report.BeforeSuite.HasFailureState() {
+		points = append(points, newTAPSetupTestPoint("BeforeSuite", report.BeforeSuite))
+	}
+	for _, spec := range report.Specs {
+		points = append(points, newTAPSpecTestPoint(spec))
+	}
+	if report.AfterSuite != nil && report.AfterSuite.HasFailureState() {
+		points = append(points, newTAPSetupTestPoint("AfterSuite", report.AfterSuite))
+	}
+
+	document := fmt.Sprintf("%s1..%d\n", indent, len(points))
+	for i, point := range points {
+		document += point.format(i+1, indent)
+	}
+	return document
+}
+
+func newTAPSetupTestPoint(name string, setup *types.SetupReport) tapTestPoint {
+	point := tapTestPoint{description: name}
+	if setup.Failure != nil {
+		point.diagnostics = tapDiagnostics(setup.Failure.Message, setup.State, &setup.Failure.Location, setup.RunTime.Seconds()*1000, setup.CapturedOutput)
+	}
+	return point
+}
+
+func newTAPSpecTestPoint(spec types.SpecReport) tapTestPoint {
+	point := tapTestPoint{
+		ok:          !spec.HasFailureState() && spec.State != types.SpecStatePending.String(),
+		description: strings.Join(spec.ComponentTexts[1:], " "),
+	}
+	switch {
+	case spec.State == types.SpecStateSkipped.String():
+		point.directive = "SKIP"
+		if spec.Failure != nil && spec.Failure.Message != "" {
+			point.directive += " " + spec.Failure.Message
+		}
+	case spec.State == types.SpecStatePending.String():
+		point.directive = "TODO pending"
+	case spec.HasQuarantinedFailure():
+		point.directive = "TODO " + quarantinedFailureMessage(spec.QuarantineReason)
+	}
+	if spec.HasFailureState() && spec.Failure != nil {
+		point.diagnostics = tapDiagnostics(spec.Failure.Message, spec.State, &spec.Failure.Location, spec.RunTime.Seconds()*1000, spec.CapturedOutput)
+	}
+	return point
+}
+
+//format returns the test point's line and diagnostics, each line prefixed with indent
+func (point tapTestPoint) format(number int, indent string) string {
+	status := "ok"
+	if !point.ok {
+		status = "not ok"
+	}
+	line := fmt.Sprintf("%s%s %d - %s", indent, status, number, tapDescription(point.description))
+	if point.directive != "" {
+		line += " # " + tapDescription(point.directive)
+	}
+	line += "\n"
+	if point.diagnostics != "" {
+		for _, diagnostic := range strings.SplitAfter(point.diagnostics, "\n") {
+			if diagnostic != "" && diagnostic != "\n" {
+				diagnostic = indent + "  " + diagnostic
+			}
+			line += diagnostic
+		}
+	}
+	return line
+}
+
+//tapDiagnostics returns a YAML block describing a failure
+func tapDiagnostics(message string, state string, location *types.CodeLocation, durationInMilliseconds float64, output string) string {
+	block := "---\n"
+	block += "message: " + tapYAMLString(message, "  ") + "\n"
+	block += "severity: " + state + "\n"
+	if location != nil && location.FileName != "" {
+		block += "at:\n"
+		block += "  file: " + tapYAMLString(location.FileName, "    ") + "\n"
+		block += fmt.Sprintf("  line: %d\n", location.LineNumber)
+	}
+	if location != nil {
+		block += fmt.Sprintf("duration_ms: %v\n", durationInMilliseconds)
+	}
+	if output != "" {
+		block += "output: " + tapYAMLString(output, "  ") + "\n"
+	}
+	return block + "...\n"
+}
+
+//tapYAMLString formats s as a YAML scalar: a literal block, with each line prefixed with indent, if it spans several lines and a double-quoted string otherwise
+func tapYAMLString(s string, indent string) string {
+	s = strings.TrimRight(s, "\n")
+	if !strings.Contains(s, "\n") || strings.HasPrefix(strings.TrimLeft(s, "\n"), " ") {
+		return strconv.Quote(s)
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return "|-\n" + strings.Join(lines, "\n")
+}
+
+//tapDescription escapes the characters that would otherwise end a test point's description
+func tapDescription(description string) string {
+	description = strings.Replace(description, "\\", "\\\\", -1)
+	description = strings.Replace(description, "#", "\\#", -1)
+	description = strings.Replace(description, "\r", " ", -1)
+	description = strings.Replace(description, "\n", " ", -1)
+	return description
+}
@@ -0,0 +1,141 @@
+package reporters_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/reporters"
+	"github.com/hackrish007/ginkgo/types"
+	. "github.com/hackrish007/gomega"
+)
+
+var _ = Describe("TAP Reporter", func() {
+	var report types.SuiteReport
+
+	specReport := func(text string, state types.SpecState) types.SpecReport {
+		return types.SpecReport{
+			ComponentTexts: []string{"[Top Level]", "A", text},
+			State:          state.String(),
+			RunTime:        1500 * time.Millisecond,
+		}
+	}
+
+	BeforeEach(func() {
+		failed := specReport("fails", types.SpecStateFailed)
+		failed.Failure = &types.FailureReport{
+			Message:  "Expected\n    <int>: 1\nto equal\n    <int>: 2",
+			Location: types.CodeLocation{FileName: "a_test.go", LineNumber: 12},
+		}
+		failed.CapturedOutput = "some output\n"
+
+		skipped := specReport("skips", types.SpecStateSkipped)
+		skipped.Failure = &types.FailureReport{Message: "not on Tuesdays"}
+
+		quarantined := specReport("is quarantined", types.SpecStatePanicked)
+		quarantined.Quarantined = true
+		quarantined.QuarantineReason = "ISSUE-123"
+		quarantined.Failure = &types.FailureReport{Message: "boom"}
+
+		report = types.SuiteReport{
+			SuiteDescription: "My test suite",
+			SuiteSucceeded:   false,
+			BeforeSuite:      &types.SetupReport{State: types.SpecStatePassed.String()},
+			AfterSuite: &types.SetupReport{
+				State:   types.SpecStateFailed.String(),
+				Failure: &types.FailureReport{Message: "cleanup failed"},
+			},
+			Specs: []types.SpecReport{
+				specReport("passes #1", types.SpecStatePassed),
+				failed,
+				skipped,
+				specReport("pends", types.SpecStatePending),
+				quarantined,
+			},
+		}
+	})
+
+	It("describes a single suite with a TAP version 13 document", func() {
+		Ω(reporters.NewTAPDocument([]types.SuiteReport{report})).Should(Equal(`TAP version 13
+1..6
+ok 1 - A passes \#1
+not ok 2 - A fails
+  ---
+  message: |-
+    Expected
+        <int>: 1
+    to equal
+        <int>: 2
+  severity: failed
+  at:
+    file: "a_test.go"
+    line: 12
+  duration_ms: 1500
+  output: "some output"
+  ...
+ok 3 - A skips # SKIP not on Tuesdays
+not ok 4 - A pends # TODO pending
+not ok 5 - A is quarantined # TODO Quarantined failure (ISSUE-123)
+  ---
+  message: "boom"
+  severity: panicked
+  duration_ms: 1500
+  ...
+not ok 6 - AfterSuite
+  ---
+  message: "cleanup failed"
+  severity: failed
+  duration_ms: 0
+  ...
+`))
+	})
+
+	It("describes several suites with a TAP version 14 document holding a subtest per suite", func() {
+		passing := types.SuiteReport{
+			SuiteDescription: "Passing suite",
+			SuiteSucceeded:   true,
+			Specs:            []types.SpecReport{specReport("passes", types.SpecStatePassed)},
+		}
+		uncompilable := types.SuiteReport{
+			SuiteDescription:   "broken",
+			CompilationFailure: "Failed to compile broken:\n\nundefined: x",
+		}
+
+		Ω(reporters.NewTAPDocument([]types.SuiteReport{passing, uncompilable})).Should(Equal(`TAP version 14
+1..2
+# Subtest: Passing suite
+    1..1
+    ok 1 - A passes
+ok 1 - Passing suite
+# Subtest: broken
+    1..1
+    not ok 1 - Compilation
+      ---
+      message: |-
+        Failed to compile broken:
+
+        undefined: x
+      severity: failed
+      ...
+not ok 2 - broken
+`))
+	})
+
+	It("writes the report of the suite it ran to a file", func() {
+		tmpDir, err := ioutil.TempDir("", "tap-reporter")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+
+		reporter := reporters.NewTAPReporter(filepath.Join(tmpDir, "reports", "report.tap"))
+		reporter.SpecSuiteWillBegin(config.GinkgoConfigType{}, &types.SuiteSummary{SuiteDescription: "My test suite"})
+		reporter.SpecDidComplete(&types.SpecSummary{ComponentTexts: []string{"[Top Level]", "A"}, State: types.SpecStatePassed})
+		reporter.SpecSuiteDidEnd(&types.SuiteSummary{SuiteSucceeded: true})
+
+		content, err := ioutil.ReadFile(filepath.Join(tmpDir, "reports", "report.tap"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(Equal("TAP version 13\n1..1\nok 1 - A\n"))
+	})
+})
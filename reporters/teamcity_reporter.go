@@ -4,6 +4,9 @@ TeamCity Reporter for Ginkgo
 
 Makes use of TeamCity's support for Service Messages
 http://confluence.jetbrains.com/display/TCD7/Build+Script+Interaction+with+TeamCity#BuildScriptInteractionwithTeamCity-ReportingTests
+
+Every message names the flow it belongs to, so that TeamCity can tell apart the specs of parallel nodes running at the same time.
+A parallel node reports its specs in a flow of its own; the Ginkgo CLI, which reports the specs of all of a suite's parallel nodes, nests a flow per node in the suite's flow.
 */
 
 package reporters
@@ -11,7 +14,9 @@ package reporters
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/hackrish007/ginkgo/config"
 	"github.com/hackrish007/ginkgo/types"
@@ -25,17 +30,58 @@ type TeamCityReporter struct {
 	writer         io.Writer
 	testSuiteName  string
 	ReporterConfig config.DefaultReporterConfigType
+
+	parallelTotal int
+	flowId        string
+	flakeAttempts int
+	began         bool
+	ended         int
+	flows         map[int]*teamCityFlow
+	lock          *sync.Mutex
 }
 
+//teamCityFlow tracks the spec a node is running, and the last one it ran, so that retries of flaky specs can be numbered
+type teamCityFlow struct {
+	id         string
+	running    string
+	lastTest   string
+	lastFailed bool
+	attempt    int
+}
+
+//NewTeamCityReporter creates a reporter of the specs of a single Ginkgo process
 func NewTeamCityReporter(writer io.Writer) *TeamCityReporter {
+	return NewParallelTeamCityReporter(writer, 1)
+}
+
+//NewParallelTeamCityReporter creates a reporter of the specs of all of a suite's parallel nodes, as forwarded by the Ginkgo CLI's server
+func NewParallelTeamCityReporter(writer io.Writer, parallelTotal int) *TeamCityReporter {
 	return &TeamCityReporter{
-		writer: writer,
+		writer:        writer,
+		parallelTotal: parallelTotal,
+		flows:         map[int]*teamCityFlow{},
+		lock:          &sync.Mutex{},
 	}
 }
 
 func (reporter *TeamCityReporter) SpecSuiteWillBegin(config config.GinkgoConfigType, summary *types.SuiteSummary) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+	if reporter.began {
+		return
+	}
+	reporter.began = true
+
 	reporter.testSuiteName = escape(summary.SuiteDescription)
-	fmt.Fprintf(reporter.writer, "%s[testSuiteStarted name='%s']\n", messageId, reporter.testSuiteName)
+	reporter.flowId = reporter.testSuiteName
+	if reporter.parallelTotal <= 1 && config.ParallelTotal > 1 {
+		reporter.flowId = nodeFlowId(reporter.testSuiteName, config.ParallelNode)
+	}
+	reporter.flakeAttempts = config.FlakeAttempts
+	reporter.emit(reporter.flowId, "testSuiteStarted name='%s'", reporter.testSuiteName)
+	if reporter.flakeAttempts > 1 {
+		reporter.emit(reporter.flowId, "testRetrySupport enabled='true'")
+	}
 }
 
 func (reporter *TeamCityReporter) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {
@@ -47,44 +93,141 @@ func (reporter *TeamCityReporter) AfterSuiteDidRun(setupSummary *types.SetupSumm
 }
 
 func (reporter *TeamCityReporter) handleSetupSummary(name string, setupSummary *types.SetupSummary) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	flow := reporter.flow(setupSummary.ParallelNode)
+	testName := escape(name)
+	reporter.emit(flow.id, "testStarted name='%s'", testName)
 	if setupSummary.State != types.SpecStatePassed {
-		testName := escape(name)
-		fmt.Fprintf(reporter.writer, "%s[testStarted name='%s']\n", messageId, testName)
 		message := reporter.failureMessage(setupSummary.Failure)
 		details := reporter.failureDetails(setupSummary.Failure)
-		fmt.Fprintf(reporter.writer, "%s[testFailed name='%s' message='%s' details='%s']\n", messageId, testName, message, details)
-		durationInMilliseconds := setupSummary.RunTime.Seconds() * 1000
-		fmt.Fprintf(reporter.writer, "%s[testFinished name='%s' duration='%v']\n", messageId, testName, durationInMilliseconds)
+		reporter.emit(flow.id, "testFailed name='%s' message='%s' details='%s'", testName, message, details)
 	}
+	durationInMilliseconds := setupSummary.RunTime.Seconds() * 1000
+	reporter.emit(flow.id, "testFinished name='%s' duration='%v'", testName, durationInMilliseconds)
+	flow.running, flow.lastTest = "", ""
 }
 
 func (reporter *TeamCityReporter) SpecWillRun(specSummary *types.SpecSummary) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	reporter.startTest(reporter.flow(specSummary.ParallelNode), specSummary)
+}
+
+//startTest reports that a spec started.  A spec that starts again right after failing is a retry of a flaky spec.  It must be called with the lock held.
+func (reporter *TeamCityReporter) startTest(flow *teamCityFlow, specSummary *types.SpecSummary) {
 	testName := escape(strings.Join(specSummary.ComponentTexts[1:], " "))
-	fmt.Fprintf(reporter.writer, "%s[testStarted name='%s']\n", messageId, testName)
+	if reporter.flakeAttempts > 1 && flow.lastTest == testName && flow.lastFailed {
+		flow.attempt++
+	} else {
+		flow.attempt = 1
+	}
+	flow.running, flow.lastTest, flow.lastFailed = testName, testName, false
+
+	reporter.emit(flow.id, "testStarted name='%s'", testName)
+	if specSummary.ID != "" {
+		reporter.emit(flow.id, "testMetadata testName='%s' name='id' value='%s'", testName, escape(specSummary.ID))
+	}
+	if flow.attempt > 1 {
+		reporter.emit(flow.id, "testMetadata testName='%s' name='attempt' value='%d' type='number'", testName, flow.attempt)
+	}
 }
 
 func (reporter *TeamCityReporter) SpecDidComplete(specSummary *types.SpecSummary) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	flow := reporter.flow(specSummary.ParallelNode)
 	testName := escape(strings.Join(specSummary.ComponentTexts[1:], " "))
+	if flow.running != testName {
+		//the node never reported starting the spec, e.g. because it crashed before it could
+		reporter.startTest(flow, specSummary)
+	}
 
 	if reporter.ReporterConfig.ReportPassed && specSummary.State == types.SpecStatePassed {
 		details := escape(specSummary.CapturedOutput)
-		fmt.Fprintf(reporter.writer, "%s[testPassed name='%s' details='%s']\n", messageId, testName, details)
+		reporter.emit(flow.id, "testPassed name='%s' details='%s'", testName, details)
 	}
-	if specSummary.State == types.SpecStateFailed || specSummary.State == types.SpecStateTimedOut || specSummary.State == types.SpecStatePanicked {
+	if specSummary.HasQuarantinedFailure() {
+		message := escape(quarantinedFailureMessage(specSummary.QuarantineReason))
+		reporter.emit(flow.id, "testIgnored name='%s' message='%s'", testName, message)
+	} else if specSummary.HasFailureState() {
 		message := reporter.failureMessage(specSummary.Failure)
 		details := reporter.failureDetails(specSummary.Failure)
-		fmt.Fprintf(reporter.writer, "%s[testFailed name='%s' message='%s' details='%s']\n", messageId, testName, message, details)
+		reporter.emit(flow.id, "testFailed name='%s' message='%s' details='%s'", testName, message, details)
 	}
 	if specSummary.State == types.SpecStateSkipped || specSummary.State == types.SpecStatePending {
-		fmt.Fprintf(reporter.writer, "%s[testIgnored name='%s']\n", messageId, testName)
+		reporter.emit(flow.id, "testIgnored name='%s' message='%s'", testName, escape(ignoredMessage(specSummary)))
 	}
+	reporter.emitSpecMetadata(flow, testName, specSummary)
 
 	durationInMilliseconds := specSummary.RunTime.Seconds() * 1000
-	fmt.Fprintf(reporter.writer, "%s[testFinished name='%s' duration='%v']\n", messageId, testName, durationInMilliseconds)
+	reporter.emit(flow.id, "testFinished name='%s' duration='%v'", testName, durationInMilliseconds)
+	flow.running, flow.lastFailed = "", specSummary.HasFailureState()
+}
+
+//emitSpecMetadata reports the node that ran a spec and the average of each of its measurements.  It must be called with the lock held.
+func (reporter *TeamCityReporter) emitSpecMetadata(flow *teamCityFlow, testName string, specSummary *types.SpecSummary) {
+	if specSummary.ParallelNode > 0 {
+		reporter.emit(flow.id, "testMetadata testName='%s' name='node' value='%d' type='number'", testName, specSummary.ParallelNode)
+	}
+	names := []string{}
+	for name := range specSummary.Measurements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		measurement := specSummary.Measurements[name]
+		if measurement.Units != "" {
+			name = fmt.Sprintf("%s (%s)", name, measurement.Units)
+		}
+		reporter.emit(flow.id, "testMetadata testName='%s' name='%s' value='%v' type='number'", testName, escape(name), measurement.Average)
+	}
 }
 
 func (reporter *TeamCityReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
-	fmt.Fprintf(reporter.writer, "%s[testSuiteFinished name='%s']\n", messageId, reporter.testSuiteName)
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	reporter.ended++
+	if reporter.ended < reporter.parallelTotal {
+		return
+	}
+	nodes := []int{}
+	for node := range reporter.flows {
+		if node > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Ints(nodes)
+	for _, node := range nodes {
+		reporter.emit(reporter.flows[node].id, "flowFinished")
+	}
+	reporter.emit(reporter.flowId, "testSuiteFinished name='%s'", reporter.testSuiteName)
+}
+
+//flow returns the flow of a node's messages: a flow of its own when reporting several parallel nodes, and the suite's flow otherwise.  It must be called with the lock held.
+func (reporter *TeamCityReporter) flow(node int) *teamCityFlow {
+	if reporter.parallelTotal <= 1 {
+		node = 0
+	}
+	flow, ok := reporter.flows[node]
+	if !ok {
+		flow = &teamCityFlow{id: reporter.flowId}
+		if node > 0 {
+			flow.id = nodeFlowId(reporter.testSuiteName, node)
+			reporter.emit(flow.id, "flowStarted parent='%s'", reporter.flowId)
+		}
+		reporter.flows[node] = flow
+	}
+	return flow
+}
+
+//emit writes a service message, tagged with the flow it belongs to
+func (reporter *TeamCityReporter) emit(flowId string, format string, args ...interface{}) {
+	fmt.Fprintf(reporter.writer, "%s[%s flowId='%s']\n", messageId, fmt.Sprintf(format, args...), flowId)
 }
 
 func (reporter *TeamCityReporter) failureMessage(failure types.SpecFailure) string {
@@ -95,6 +238,18 @@ func (reporter *TeamCityReporter) failureDetails(failure types.SpecFailure) stri
 	return escape(fmt.Sprintf("%s\n%s", failure.Message, failure.Location.String()))
 }
 
+func nodeFlowId(testSuiteName string, node int) string {
+	return fmt.Sprintf("%s-%d", testSuiteName, node)
+}
+
+//ignoredMessage returns why a spec didn't run: the message passed to Skip, or whether it was pending or skipped
+func ignoredMessage(specSummary *types.SpecSummary) string {
+	if specSummary.Failure.Message != "" {
+		return specSummary.Failure.Message
+	}
+	return specSummary.State.String()
+}
+
 func escape(output string) string {
 	output = strings.Replace(output, "|", "||", -1)
 	output = strings.Replace(output, "'", "|'", -1)
@@ -31,12 +31,14 @@ var _ = Describe("TeamCity Reporter", func() {
 	Describe("a passing test", func() {
 		BeforeEach(func() {
 			beforeSuite := &types.SetupSummary{
-				State: types.SpecStatePassed,
+				State:   types.SpecStatePassed,
+				RunTime: time.Second,
 			}
 			reporter.BeforeSuiteDidRun(beforeSuite)
 
 			afterSuite := &types.SetupSummary{
-				State: types.SpecStatePassed,
+				State:   types.SpecStatePassed,
+				RunTime: 2 * time.Second,
 			}
 			reporter.AfterSuiteDidRun(afterSuite)
 
@@ -44,6 +46,7 @@ var _ = Describe("TeamCity Reporter", func() {
 			reporter.ReporterConfig.ReportPassed = true
 
 			spec := &types.SpecSummary{
+				ID:             "a_test.go:A/B/C",
 				ComponentTexts: []string{"[Top Level]", "A", "B", "C"},
 				CapturedOutput: "Test scenario...",
 				State:          types.SpecStatePassed,
@@ -59,14 +62,19 @@ var _ = Describe("TeamCity Reporter", func() {
 			})
 		})
 
-		It("should record the test as passing", func() {
+		It("should record the test as passing, after the BeforeSuite and AfterSuite", func() {
 			actual := buffer.String()
 			expected :=
-				"##teamcity[testSuiteStarted name='Foo|'s test suite']\n" +
-					"##teamcity[testStarted name='A B C']\n" +
-					"##teamcity[testPassed name='A B C' details='Test scenario...']\n" +
-					"##teamcity[testFinished name='A B C' duration='5000']\n" +
-					"##teamcity[testSuiteFinished name='Foo|'s test suite']\n"
+				"##teamcity[testSuiteStarted name='Foo|'s test suite' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testStarted name='BeforeSuite' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testFinished name='BeforeSuite' duration='1000' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testStarted name='AfterSuite' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testFinished name='AfterSuite' duration='2000' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testStarted name='A B C' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testMetadata testName='A B C' name='id' value='a_test.go:A/B/C' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testPassed name='A B C' details='Test scenario...' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testFinished name='A B C' duration='5000' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testSuiteFinished name='Foo|'s test suite' flowId='Foo|'s test suite']\n"
 			Ω(actual).Should(Equal(expected))
 		})
 	})
@@ -96,11 +104,11 @@ var _ = Describe("TeamCity Reporter", func() {
 		It("should record the test as having failed", func() {
 			actual := buffer.String()
 			expected := fmt.Sprintf(
-				"##teamcity[testSuiteStarted name='Foo|'s test suite']\n"+
-					"##teamcity[testStarted name='BeforeSuite']\n"+
-					"##teamcity[testFailed name='BeforeSuite' message='%s' details='failed to setup|n|n%s']\n"+
-					"##teamcity[testFinished name='BeforeSuite' duration='3000']\n"+
-					"##teamcity[testSuiteFinished name='Foo|'s test suite']\n",
+				"##teamcity[testSuiteStarted name='Foo|'s test suite' flowId='Foo|'s test suite']\n"+
+					"##teamcity[testStarted name='BeforeSuite' flowId='Foo|'s test suite']\n"+
+					"##teamcity[testFailed name='BeforeSuite' message='%s' details='failed to setup|n|n%s' flowId='Foo|'s test suite']\n"+
+					"##teamcity[testFinished name='BeforeSuite' duration='3000' flowId='Foo|'s test suite']\n"+
+					"##teamcity[testSuiteFinished name='Foo|'s test suite' flowId='Foo|'s test suite']\n",
 				beforeSuite.Failure.ComponentCodeLocation.String(),
 				beforeSuite.Failure.Location.String(),
 			)
@@ -133,11 +141,11 @@ var _ = Describe("TeamCity Reporter", func() {
 		It("should record the test as having failed", func() {
 			actual := buffer.String()
 			expected := fmt.Sprintf(
-				"##teamcity[testSuiteStarted name='Foo|'s test suite']\n"+
-					"##teamcity[testStarted name='AfterSuite']\n"+
-					"##teamcity[testFailed name='AfterSuite' message='%s' details='failed to setup|n|n%s']\n"+
-					"##teamcity[testFinished name='AfterSuite' duration='3000']\n"+
-					"##teamcity[testSuiteFinished name='Foo|'s test suite']\n",
+				"##teamcity[testSuiteStarted name='Foo|'s test suite' flowId='Foo|'s test suite']\n"+
+					"##teamcity[testStarted name='AfterSuite' flowId='Foo|'s test suite']\n"+
+					"##teamcity[testFailed name='AfterSuite' message='%s' details='failed to setup|n|n%s' flowId='Foo|'s test suite']\n"+
+					"##teamcity[testFinished name='AfterSuite' duration='3000' flowId='Foo|'s test suite']\n"+
+					"##teamcity[testSuiteFinished name='Foo|'s test suite' flowId='Foo|'s test suite']\n",
 				afterSuite.Failure.ComponentCodeLocation.String(),
 				afterSuite.Failure.Location.String(),
 			)
@@ -181,11 +189,11 @@ var _ = Describe("TeamCity Reporter", func() {
 			It("should record test as failing", func() {
 				actual := buffer.String()
 				expected :=
-					fmt.Sprintf("##teamcity[testSuiteStarted name='Foo|'s test suite']\n"+
-						"##teamcity[testStarted name='A B C']\n"+
-						"##teamcity[testFailed name='A B C' message='%s' details='I failed|n%s']\n"+
-						"##teamcity[testFinished name='A B C' duration='5000']\n"+
-						"##teamcity[testSuiteFinished name='Foo|'s test suite']\n",
+					fmt.Sprintf("##teamcity[testSuiteStarted name='Foo|'s test suite' flowId='Foo|'s test suite']\n"+
+						"##teamcity[testStarted name='A B C' flowId='Foo|'s test suite']\n"+
+						"##teamcity[testFailed name='A B C' message='%s' details='I failed|n%s' flowId='Foo|'s test suite']\n"+
+						"##teamcity[testFinished name='A B C' duration='5000' flowId='Foo|'s test suite']\n"+
+						"##teamcity[testSuiteFinished name='Foo|'s test suite' flowId='Foo|'s test suite']\n",
 						spec.Failure.ComponentCodeLocation.String(),
 						spec.Failure.Location.String(),
 					)
@@ -194,6 +202,40 @@ var _ = Describe("TeamCity Reporter", func() {
 		})
 	}
 
+	Describe("a quarantined failing test", func() {
+		BeforeEach(func() {
+			spec := &types.SpecSummary{
+				ComponentTexts:   []string{"[Top Level]", "A", "B", "C"},
+				State:            types.SpecStateFailed,
+				RunTime:          5 * time.Second,
+				Quarantined:      true,
+				QuarantineReason: "ISSUE-123",
+				Failure: types.SpecFailure{
+					Message: "I failed",
+				},
+			}
+			reporter.SpecWillRun(spec)
+			reporter.SpecDidComplete(spec)
+
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{
+				NumberOfSpecsThatWillBeRun:  1,
+				NumberOfQuarantinedFailures: 1,
+				RunTime:                     10 * time.Second,
+			})
+		})
+
+		It("should record test as ignored", func() {
+			actual := buffer.String()
+			expected :=
+				"##teamcity[testSuiteStarted name='Foo|'s test suite' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testStarted name='A B C' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testIgnored name='A B C' message='Quarantined failure (ISSUE-123)' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testFinished name='A B C' duration='5000' flowId='Foo|'s test suite']\n" +
+					"##teamcity[testSuiteFinished name='Foo|'s test suite' flowId='Foo|'s test suite']\n"
+			Ω(actual).Should(Equal(expected))
+		})
+	})
+
 	for _, specStateCase := range []types.SpecState{types.SpecStatePending, types.SpecStateSkipped} {
 		specStateCase := specStateCase
 		Describe("a skipped test", func() {
@@ -214,16 +256,144 @@ var _ = Describe("TeamCity Reporter", func() {
 				})
 			})
 
-			It("should record test as ignored", func() {
+			It("should record test as ignored, with its state as the reason", func() {
 				actual := buffer.String()
 				expected :=
-					"##teamcity[testSuiteStarted name='Foo|'s test suite']\n" +
-						"##teamcity[testStarted name='A B C']\n" +
-						"##teamcity[testIgnored name='A B C']\n" +
-						"##teamcity[testFinished name='A B C' duration='5000']\n" +
-						"##teamcity[testSuiteFinished name='Foo|'s test suite']\n"
+					"##teamcity[testSuiteStarted name='Foo|'s test suite' flowId='Foo|'s test suite']\n" +
+						"##teamcity[testStarted name='A B C' flowId='Foo|'s test suite']\n" +
+						fmt.Sprintf("##teamcity[testIgnored name='A B C' message='%s' flowId='Foo|'s test suite']\n", specStateCase) +
+						"##teamcity[testFinished name='A B C' duration='5000' flowId='Foo|'s test suite']\n" +
+						"##teamcity[testSuiteFinished name='Foo|'s test suite' flowId='Foo|'s test suite']\n"
 				Ω(actual).Should(Equal(expected))
 			})
 		})
 	}
 })
+
+var _ = Describe("TeamCity Reporter", func() {
+	var (
+		buffer   bytes.Buffer
+		reporter *reporters.TeamCityReporter
+	)
+
+	spec := func(text string, state types.SpecState, node int) *types.SpecSummary {
+		return &types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", text},
+			State:          state,
+			RunTime:        time.Second,
+			ParallelNode:   node,
+		}
+	}
+
+	BeforeEach(func() {
+		buffer.Truncate(0)
+	})
+
+	Describe("a test skipped with a reason", func() {
+		It("should record the reason it was ignored", func() {
+			reporter = reporters.NewTeamCityReporter(&buffer)
+			reporter.SpecSuiteWillBegin(config.GinkgoConfigType{}, &types.SuiteSummary{SuiteDescription: "S"})
+			skipped := spec("A", types.SpecStateSkipped, 0)
+			skipped.Failure.Message = "not on Tuesdays"
+			reporter.SpecWillRun(skipped)
+			reporter.SpecDidComplete(skipped)
+
+			Ω(buffer.String()).Should(ContainSubstring("##teamcity[testIgnored name='A' message='not on Tuesdays' flowId='S']\n"))
+		})
+	})
+
+	Describe("retrying flaky tests", func() {
+		BeforeEach(func() {
+			reporter = reporters.NewTeamCityReporter(&buffer)
+			reporter.SpecSuiteWillBegin(config.GinkgoConfigType{FlakeAttempts: 3}, &types.SuiteSummary{SuiteDescription: "S"})
+			for _, attempt := range []*types.SpecSummary{spec("A", types.SpecStateFailed, 0), spec("A", types.SpecStatePassed, 0), spec("B", types.SpecStatePassed, 0)} {
+				reporter.SpecWillRun(attempt)
+				reporter.SpecDidComplete(attempt)
+			}
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{})
+		})
+
+		It("should enable TeamCity's support for retries and number each retry", func() {
+			Ω(buffer.String()).Should(Equal(
+				"##teamcity[testSuiteStarted name='S' flowId='S']\n" +
+					"##teamcity[testRetrySupport enabled='true' flowId='S']\n" +
+					"##teamcity[testStarted name='A' flowId='S']\n" +
+					"##teamcity[testFailed name='A' message=':0' details='|n:0' flowId='S']\n" +
+					"##teamcity[testFinished name='A' duration='1000' flowId='S']\n" +
+					"##teamcity[testStarted name='A' flowId='S']\n" +
+					"##teamcity[testMetadata testName='A' name='attempt' value='2' type='number' flowId='S']\n" +
+					"##teamcity[testFinished name='A' duration='1000' flowId='S']\n" +
+					"##teamcity[testStarted name='B' flowId='S']\n" +
+					"##teamcity[testFinished name='B' duration='1000' flowId='S']\n" +
+					"##teamcity[testSuiteFinished name='S' flowId='S']\n"))
+		})
+	})
+
+	Describe("a single parallel node", func() {
+		It("should report in a flow of its own", func() {
+			reporter = reporters.NewTeamCityReporter(&buffer)
+			reporter.SpecSuiteWillBegin(config.GinkgoConfigType{ParallelNode: 2, ParallelTotal: 3}, &types.SuiteSummary{SuiteDescription: "S"})
+			passed := spec("A", types.SpecStatePassed, 2)
+			reporter.SpecWillRun(passed)
+			reporter.SpecDidComplete(passed)
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{})
+
+			Ω(buffer.String()).Should(Equal(
+				"##teamcity[testSuiteStarted name='S' flowId='S-2']\n" +
+					"##teamcity[testStarted name='A' flowId='S-2']\n" +
+					"##teamcity[testMetadata testName='A' name='node' value='2' type='number' flowId='S-2']\n" +
+					"##teamcity[testFinished name='A' duration='1000' flowId='S-2']\n" +
+					"##teamcity[testSuiteFinished name='S' flowId='S-2']\n"))
+		})
+	})
+
+	Describe("reporting all of a suite's parallel nodes", func() {
+		BeforeEach(func() {
+			reporter = reporters.NewParallelTeamCityReporter(&buffer, 2)
+			for node := 1; node <= 2; node++ {
+				reporter.SpecSuiteWillBegin(config.GinkgoConfigType{ParallelNode: node, ParallelTotal: 2}, &types.SuiteSummary{SuiteDescription: "S"})
+			}
+
+			reporter.BeforeSuiteDidRun(&types.SetupSummary{State: types.SpecStatePassed, ParallelNode: 1})
+			a, b := spec("A", types.SpecStatePassed, 1), spec("B", types.SpecStatePassed, 2)
+			b.Measurements = map[string]*types.SpecMeasurement{"speed": {Name: "speed", Average: 1.5, Units: "ms"}}
+			reporter.SpecWillRun(a)
+			reporter.SpecWillRun(b)
+			reporter.SpecDidComplete(b)
+			reporter.SpecDidComplete(a)
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{})
+		})
+
+		It("should report each node's tests in a flow nested in the suite's flow", func() {
+			Ω(buffer.String()).Should(Equal(
+				"##teamcity[testSuiteStarted name='S' flowId='S']\n" +
+					"##teamcity[flowStarted parent='S' flowId='S-1']\n" +
+					"##teamcity[testStarted name='BeforeSuite' flowId='S-1']\n" +
+					"##teamcity[testFinished name='BeforeSuite' duration='0' flowId='S-1']\n" +
+					"##teamcity[testStarted name='A' flowId='S-1']\n" +
+					"##teamcity[flowStarted parent='S' flowId='S-2']\n" +
+					"##teamcity[testStarted name='B' flowId='S-2']\n" +
+					"##teamcity[testMetadata testName='B' name='node' value='2' type='number' flowId='S-2']\n" +
+					"##teamcity[testMetadata testName='B' name='speed (ms)' value='1.5' type='number' flowId='S-2']\n" +
+					"##teamcity[testFinished name='B' duration='1000' flowId='S-2']\n" +
+					"##teamcity[testMetadata testName='A' name='node' value='1' type='number' flowId='S-1']\n" +
+					"##teamcity[testFinished name='A' duration='1000' flowId='S-1']\n"))
+		})
+
+		It("should finish the suite once every node has", func() {
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{})
+			Ω(buffer.String()).Should(HaveSuffix(
+				"##teamcity[testFinished name='A' duration='1000' flowId='S-1']\n" +
+					"##teamcity[flowFinished flowId='S-1']\n" +
+					"##teamcity[flowFinished flowId='S-2']\n" +
+					"##teamcity[testSuiteFinished name='S' flowId='S']\n"))
+		})
+
+		It("should start tests that a node completed without starting, such as those a crashed node was running", func() {
+			reporter.SpecDidComplete(spec("C", types.SpecStateFailed, 2))
+			Ω(buffer.String()).Should(ContainSubstring(
+				"##teamcity[testStarted name='C' flowId='S-2']\n" +
+					"##teamcity[testFailed name='C' message=':0' details='|n:0' flowId='S-2']\n"))
+		})
+	})
+})
@@ -0,0 +1,288 @@
+/*
+
+test2json Reporter for Ginkgo
+
+Prints the events `go test -json` prints, one JSON object per line, so that tools that understand test2json can display each spec as a test of its own.
+https://golang.org/cmd/test2json/
+
+The suite is reported as a test named after its description, and each spec as a subtest named after its containers and text, e.g. "My_Suite/Widget/renders".
+Names are rewritten the way the testing package rewrites the names of subtests.  A spec's captured output, and its failure, are reported as its output.
+Skipped and pending specs, and failures of quarantined specs, are reported as skipped.  BeforeSuites and AfterSuites are only reported if they fail.
+
+*/
+
+package reporters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/types"
+)
+
+//test2JSONEvent mirrors the events printed by cmd/test2json
+type test2JSONEvent struct {
+	Time    time.Time
+	Action  string
+	Package string   `json:",omitempty"`
+	Test    string   `json:",omitempty"`
+	Elapsed *float64 `json:",omitempty"`
+	Output  string   `json:",omitempty"`
+}
+
+type Test2JSONReporter struct {
+	writer      io.Writer
+	packagePath string
+	suiteName   string
+
+	parallelTotal int
+	began         bool
+	ended         int
+	failed        bool
+	runTime       time.Duration
+	running       map[int]string
+	lock          *sync.Mutex
+}
+
+//NewTest2JSONReporter creates a reporter of the specs of a single Ginkgo process
+func NewTest2JSONReporter(writer io.Writer) *Test2JSONReporter {
+	return NewParallelTest2JSONReporter(writer, 1)
+}
+
+//NewParallelTest2JSONReporter creates a reporter of the specs of all of a suite's parallel nodes, as forwarded by the Ginkgo CLI's server
+func NewParallelTest2JSONReporter(writer io.Writer, parallelTotal int) *Test2JSONReporter {
+	reporter := &Test2JSONReporter{
+		writer:        writer,
+		parallelTotal: parallelTotal,
+		running:       map[int]string{},
+		lock:          &sync.Mutex{},
+	}
+	cwd, err := os.Getwd()
+	if err == nil {
+		reporter.SetSuitePath(cwd)
+	}
+	return reporter
+}
+
+//SetSuitePath sets the directory of the suite, which determines the import path events name as their package.  It defaults to the working directory.
+func (reporter *Test2JSONReporter) SetSuitePath(path string) {
+	reporter.packagePath = goPackagePath(path)
+}
+
+func (reporter *Test2JSONReporter) SpecSuiteWillBegin(config config.GinkgoConfigType, summary *types.SuiteSummary) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+	if reporter.began {
+		return
+	}
+	reporter.began = true
+
+	reporter.suiteName = test2JSONName(summary.SuiteDescription)
+	reporter.emit("run", reporter.suiteName, nil, "")
+	reporter.emit("output", reporter.suiteName, nil, fmt.Sprintf("=== RUN   %s\n", reporter.suiteName))
+}
+
+func (reporter *Test2JSONReporter) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {
+	reporter.handleSetupSummary("BeforeSuite", setupSummary)
+}
+
+func (reporter *Test2JSONReporter) AfterSuiteDidRun(setupSummary *types.SetupSummary) {
+	reporter.handleSetupSummary("AfterSuite", setupSummary)
+}
+
+func (reporter *Test2JSONReporter) handleSetupSummary(name string, setupSummary *types.SetupSummary) {
+	if setupSummary.State == types.SpecStatePassed {
+		return
+	}
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	testName := reporter.suiteName + "/" + name
+	reporter.startTest(testName)
+	reporter.emitOutput(testName, setupSummary.CapturedOutput)
+	reporter.emitFailure(testName, setupSummary.Failure)
+	reporter.endTest(testName, "fail", setupSummary.RunTime)
+}
+
+func (reporter *Test2JSONReporter) SpecWillRun(specSummary *types.SpecSummary) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	testName := reporter.specName(specSummary)
+	reporter.startTest(testName)
+	reporter.running[specSummary.ParallelNode] = testName
+}
+
+func (reporter *Test2JSONReporter) SpecDidComplete(specSummary *types.SpecSummary) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	testName := reporter.specName(specSummary)
+	if reporter.running[specSummary.ParallelNode] != testName {
+		//the node never reported starting the spec, e.g. because it crashed before it could
+		reporter.startTest(testName)
+	}
+	delete(reporter.running, specSummary.ParallelNode)
+
+	reporter.emitOutput(testName, specSummary.CapturedOutput)
+	action := "pass"
+	switch {
+	case specSummary.HasQuarantinedFailure():
+		reporter.emit("output", testName, nil, "    "+quarantinedFailureMessage(specSummary.QuarantineReason)+"\n")
+		reporter.emitFailure(testName, specSummary.Failure)
+		action = "skip"
+	case specSummary.HasFailureState():
+		reporter.emitFailure(testName, specSummary.Failure)
+		action = "fail"
+	case specSummary.State == types.SpecStateSkipped:
+		if specSummary.Failure.Message != "" {
+			reporter.emitFailure(testName, specSummary.Failure)
+		}
+		action = "skip"
+	case specSummary.State == types.SpecStatePending:
+		action = "skip"
+	}
+	reporter.endTest(testName, action, specSummary.RunTime)
+}
+
+func (reporter *Test2JSONReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	reporter.ended++
+	reporter.failed = reporter.failed || !summary.SuiteSucceeded
+	if summary.RunTime > reporter.runTime {
+		reporter.runTime = summary.RunTime
+	}
+	if reporter.ended < reporter.parallelTotal {
+		return
+	}
+
+	action, status := "pass", "ok  "
+	if reporter.failed {
+		action, status = "fail", "FAIL"
+	}
+	reporter.endTest(reporter.suiteName, action, reporter.runTime)
+	reporter.emit("output", "", nil, strings.ToUpper(action)+"\n")
+	elapsed := test2JSONElapsed(reporter.runTime)
+	reporter.emit("output", "", nil, fmt.Sprintf("%s\t%s\t%.3fs\n", status, reporter.packagePath, reporter.runTime.Seconds()))
+	reporter.emit(action, "", &elapsed, "")
+}
+
+func (reporter *Test2JSONReporter) specName(specSummary *types.SpecSummary) string {
+	names := []string{reporter.suiteName}
+	for _, text := range specSummary.ComponentTexts[1:] {
+		names = append(names, test2JSONName(text))
+	}
+	return strings.Join(names, "/")
+}
+
+//startTest, endTest and the emit functions must be called with the lock held
+func (reporter *Test2JSONReporter) startTest(testName string) {
+	reporter.emit("run", testName, nil, "")
+	reporter.emit("output", testName, nil, fmt.Sprintf("=== RUN   %s\n", testName))
+}
+
+func (reporter *Test2JSONReporter) endTest(testName string, action string, runTime time.Duration) {
+	elapsed := test2JSONElapsed(runTime)
+	reporter.emit("output", testName, nil, fmt.Sprintf("--- %s: %s (%.2fs)\n", strings.ToUpper(action), testName, elapsed))
+	reporter.emit(action, testName, &elapsed, "")
+}
+
+//emitFailure reports a failure the way the testing package reports a call to t.Error: prefixed with its location, with each further line indented
+func (reporter *Test2JSONReporter) emitFailure(testName string, failure types.SpecFailure) {
+	if failure.Message == "" && failure.Location.FileName == "" {
+		return
+	}
+	message := failure.Message
+	if failure.ForwardedPanic != "" {
+		message += "\n" + failure.ForwardedPanic
+	}
+	prefix := "    "
+	if failure.Location.FileName != "" {
+		prefix += fmt.Sprintf("%s:%d: ", filepath.Base(failure.Location.FileName), failure.Location.LineNumber)
+	}
+	reporter.emitOutput(testName, prefix+strings.Replace(strings.TrimRight(message, "\n"), "\n", "\n        ", -1)+"\n")
+}
+
+//emitOutput reports each line of output as an output event of its own, as test2json does
+func (reporter *Test2JSONReporter) emitOutput(testName string, output string) {
+	for _, line := range strings.SplitAfter(output, "\n") {
+		if line != "" {
+			reporter.emit("output", testName, nil, line)
+		}
+	}
+}
+
+func (reporter *Test2JSONReporter) emit(action string, testName string, elapsed *float64, output string) {
+	event, err := json.Marshal(test2JSONEvent{
+		Time:    time.Now(),
+		Action:  action,
+		Package: reporter.packagePath,
+		Test:    testName,
+		Elapsed: elapsed,
+		Output:  output,
+	})
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(reporter.writer, "%s\n", event)
+}
+
+//test2JSONElapsed returns a run time in seconds, with the precision `go test` prints run times with
+func test2JSONElapsed(runTime time.Duration) float64 {
+	elapsed, _ := strconv.ParseFloat(fmt.Sprintf("%.2f", runTime.Seconds()), 64)
+	return elapsed
+}
+
+//test2JSONName rewrites a name the way the testing package rewrites the names of subtests: spaces become underscores and unprintable characters are escaped
+func test2JSONName(name string) string {
+	rewritten := ""
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r):
+			rewritten += "_"
+		case !strconv.IsPrint(r):
+			s := strconv.QuoteRune(r)
+			rewritten += s[1 : len(s)-1]
+		default:
+			rewritten += string(r)
+		}
+	}
+	return rewritten
+}
+
+var moduleDirectiveRegExp = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)"?`)
+
+//goPackagePath returns the import path of the package in dir, or "" if dir isn't part of a module
+func goPackagePath(dir string) string {
+	root := moduleRoot(dir)
+	if root == "" {
+		return ""
+	}
+	goMod, err := ioutil.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	match := moduleDirectiveRegExp.FindSubmatch(goMod)
+	if match == nil {
+		return ""
+	}
+	absDir, _ := filepath.Abs(dir)
+	relative := relativePath(root, absDir)
+	if relative == "." {
+		return string(match[1])
+	}
+	return string(match[1]) + "/" + relative
+}
@@ -0,0 +1,246 @@
+package reporters_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/hackrish007/ginkgo"
+	"github.com/hackrish007/ginkgo/config"
+	"github.com/hackrish007/ginkgo/reporters"
+	"github.com/hackrish007/ginkgo/types"
+	. "github.com/hackrish007/gomega"
+)
+
+type test2JSONEvent struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed *float64
+	Output  string
+}
+
+var _ = Describe("test2json Reporter", func() {
+	var (
+		buffer   *bytes.Buffer
+		root     string
+		reporter *reporters.Test2JSONReporter
+	)
+
+	events := func() []test2JSONEvent {
+		events := []test2JSONEvent{}
+		for _, line := range strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n") {
+			event := test2JSONEvent{}
+			Ω(json.Unmarshal([]byte(line), &event)).Should(Succeed())
+			Ω(event.Time).ShouldNot(BeZero())
+			events = append(events, event)
+		}
+		return events
+	}
+
+	//describe summarizes events as "Action Test Elapsed" or "output Test Output", leaving out times
+	describe := func(events []test2JSONEvent) []string {
+		descriptions := []string{}
+		for _, event := range events {
+			description := event.Action + " " + event.Test
+			if event.Elapsed != nil {
+				description += fmt.Sprintf(" %v", *event.Elapsed)
+			}
+			if event.Output != "" {
+				description += " " + event.Output
+			}
+			descriptions = append(descriptions, description)
+		}
+		return descriptions
+	}
+
+	spec := func(text string, state types.SpecState, runTime time.Duration) *types.SpecSummary {
+		return &types.SpecSummary{
+			ComponentTexts: []string{"[Top Level]", "A widget", text},
+			State:          state,
+			RunTime:        runTime,
+		}
+	}
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		var err error
+		root, err = ioutil.TempDir("", "test2json-reporter")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(os.MkdirAll(filepath.Join(root, "pkg"), os.ModePerm)).Should(Succeed())
+		Ω(ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644)).Should(Succeed())
+
+		reporter = reporters.NewTest2JSONReporter(buffer)
+		reporter.SetSuitePath(filepath.Join(root, "pkg"))
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	It("reports the suite as a test, and each spec as a subtest with its output and elapsed time", func() {
+		reporter.SpecSuiteWillBegin(config.GinkgoConfigType{}, &types.SuiteSummary{SuiteDescription: "My test suite"})
+		reporter.BeforeSuiteDidRun(&types.SetupSummary{State: types.SpecStatePassed})
+
+		passed := spec("renders", types.SpecStatePassed, 10*time.Millisecond)
+		passed.CapturedOutput = "rendering\ndone\n"
+		reporter.SpecWillRun(passed)
+		reporter.SpecDidComplete(passed)
+
+		failed := spec("resizes", types.SpecStateFailed, 1500*time.Millisecond)
+		failed.Failure = types.SpecFailure{
+			Message:  "Expected\n    <int>: 1\nto equal 2",
+			Location: types.CodeLocation{FileName: "/src/widget_test.go", LineNumber: 12},
+		}
+		reporter.SpecWillRun(failed)
+		reporter.SpecDidComplete(failed)
+
+		reporter.SpecSuiteDidEnd(&types.SuiteSummary{SuiteSucceeded: false, RunTime: 2 * time.Second})
+
+		all := events()
+		for _, event := range all {
+			Ω(event.Package).Should(Equal("example.com/m/pkg"))
+		}
+		Ω(describe(all)).Should(Equal([]string{
+			"run My_test_suite",
+			"output My_test_suite === RUN   My_test_suite\n",
+			"run My_test_suite/A_widget/renders",
+			"output My_test_suite/A_widget/renders === RUN   My_test_suite/A_widget/renders\n",
+			"output My_test_suite/A_widget/renders rendering\n",
+			"output My_test_suite/A_widget/renders done\n",
+			"output My_test_suite/A_widget/renders --- PASS: My_test_suite/A_widget/renders (0.01s)\n",
+			"pass My_test_suite/A_widget/renders 0.01",
+			"run My_test_suite/A_widget/resizes",
+			"output My_test_suite/A_widget/resizes === RUN   My_test_suite/A_widget/resizes\n",
+			"output My_test_suite/A_widget/resizes     widget_test.go:12: Expected\n",
+			"output My_test_suite/A_widget/resizes             <int>: 1\n",
+			"output My_test_suite/A_widget/resizes         to equal 2\n",
+			"output My_test_suite/A_widget/resizes --- FAIL: My_test_suite/A_widget/resizes (1.50s)\n",
+			"fail My_test_suite/A_widget/resizes 1.5",
+			"output My_test_suite --- FAIL: My_test_suite (2.00s)\n",
+			"fail My_test_suite 2",
+			"output  FAIL\n",
+			"output  FAIL\texample.com/m/pkg\t2.000s\n",
+			"fail  2",
+		}))
+	})
+
+	It("reports skipped and pending specs, and failures of quarantined specs, as skipped", func() {
+		reporter.SpecSuiteWillBegin(config.GinkgoConfigType{}, &types.SuiteSummary{SuiteDescription: "My test suite"})
+
+		skipped := spec("skips", types.SpecStateSkipped, 0)
+		skipped.Failure = types.SpecFailure{Message: "not on Tuesdays", Location: types.CodeLocation{FileName: "/src/widget_test.go", LineNumber: 3}}
+		pending := spec("pends", types.SpecStatePending, 0)
+		quarantined := spec("is quarantined", types.SpecStatePanicked, 0)
+		quarantined.Quarantined = true
+		quarantined.QuarantineReason = "ISSUE-123"
+		quarantined.Failure = types.SpecFailure{Message: "Test Panicked", ForwardedPanic: "boom"}
+		for _, summary := range []*types.SpecSummary{skipped, pending, quarantined} {
+			reporter.SpecWillRun(summary)
+			reporter.SpecDidComplete(summary)
+		}
+
+		reporter.SpecSuiteDidEnd(&types.SuiteSummary{SuiteSucceeded: true})
+
+		Ω(describe(events())).Should(Equal([]string{
+			"run My_test_suite",
+			"output My_test_suite === RUN   My_test_suite\n",
+			"run My_test_suite/A_widget/skips",
+			"output My_test_suite/A_widget/skips === RUN   My_test_suite/A_widget/skips\n",
+			"output My_test_suite/A_widget/skips     widget_test.go:3: not on Tuesdays\n",
+			"output My_test_suite/A_widget/skips --- SKIP: My_test_suite/A_widget/skips (0.00s)\n",
+			"skip My_test_suite/A_widget/skips 0",
+			"run My_test_suite/A_widget/pends",
+			"output My_test_suite/A_widget/pends === RUN   My_test_suite/A_widget/pends\n",
+			"output My_test_suite/A_widget/pends --- SKIP: My_test_suite/A_widget/pends (0.00s)\n",
+			"skip My_test_suite/A_widget/pends 0",
+			"run My_test_suite/A_widget/is_quarantined",
+			"output My_test_suite/A_widget/is_quarantined === RUN   My_test_suite/A_widget/is_quarantined\n",
+			"output My_test_suite/A_widget/is_quarantined     Quarantined failure (ISSUE-123)\n",
+			"output My_test_suite/A_widget/is_quarantined     Test Panicked\n",
+			"output My_test_suite/A_widget/is_quarantined         boom\n",
+			"output My_test_suite/A_widget/is_quarantined --- SKIP: My_test_suite/A_widget/is_quarantined (0.00s)\n",
+			"skip My_test_suite/A_widget/is_quarantined 0",
+			"output My_test_suite --- PASS: My_test_suite (0.00s)\n",
+			"pass My_test_suite 0",
+			"output  PASS\n",
+			"output  ok  \texample.com/m/pkg\t0.000s\n",
+			"pass  0",
+		}))
+	})
+
+	It("reports failed BeforeSuites and AfterSuites as subtests", func() {
+		reporter.SpecSuiteWillBegin(config.GinkgoConfigType{}, &types.SuiteSummary{SuiteDescription: "My test suite"})
+		reporter.BeforeSuiteDidRun(&types.SetupSummary{
+			State:          types.SpecStateFailed,
+			RunTime:        time.Second,
+			Failure:        types.SpecFailure{Message: "no database", Location: types.CodeLocation{FileName: "/src/suite_test.go", LineNumber: 20}},
+			CapturedOutput: "connecting\n",
+		})
+
+		Ω(describe(events())[2:]).Should(Equal([]string{
+			"run My_test_suite/BeforeSuite",
+			"output My_test_suite/BeforeSuite === RUN   My_test_suite/BeforeSuite\n",
+			"output My_test_suite/BeforeSuite connecting\n",
+			"output My_test_suite/BeforeSuite     suite_test.go:20: no database\n",
+			"output My_test_suite/BeforeSuite --- FAIL: My_test_suite/BeforeSuite (1.00s)\n",
+			"fail My_test_suite/BeforeSuite 1",
+		}))
+	})
+
+	Context("when reporting the specs of all of a suite's parallel nodes", func() {
+		BeforeEach(func() {
+			reporter = reporters.NewParallelTest2JSONReporter(buffer, 2)
+			reporter.SetSuitePath(filepath.Join(root, "pkg"))
+		})
+
+		It("ends the suite once every node has ended, and tells apart the specs the nodes run at the same time", func() {
+			for node := 1; node <= 2; node++ {
+				reporter.SpecSuiteWillBegin(config.GinkgoConfigType{ParallelNode: node, ParallelTotal: 2}, &types.SuiteSummary{SuiteDescription: "My test suite"})
+			}
+			first := spec("renders", types.SpecStatePassed, 0)
+			first.ParallelNode = 1
+			second := spec("resizes", types.SpecStateFailed, 0)
+			second.ParallelNode = 2
+			crashed := spec("crashes", types.SpecStatePanicked, 0)
+			crashed.ParallelNode = 2
+
+			reporter.SpecWillRun(first)
+			reporter.SpecWillRun(second)
+			reporter.SpecDidComplete(first)
+			reporter.SpecDidComplete(second)
+			reporter.SpecDidComplete(crashed)
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{SuiteSucceeded: true, RunTime: time.Second})
+			Ω(buffer.String()).ShouldNot(ContainSubstring(`"Test":"My_test_suite","Elapsed"`))
+			reporter.SpecSuiteDidEnd(&types.SuiteSummary{SuiteSucceeded: false, RunTime: 3 * time.Second})
+
+			Ω(describe(events())).Should(Equal([]string{
+				"run My_test_suite",
+				"output My_test_suite === RUN   My_test_suite\n",
+				"run My_test_suite/A_widget/renders",
+				"output My_test_suite/A_widget/renders === RUN   My_test_suite/A_widget/renders\n",
+				"run My_test_suite/A_widget/resizes",
+				"output My_test_suite/A_widget/resizes === RUN   My_test_suite/A_widget/resizes\n",
+				"output My_test_suite/A_widget/renders --- PASS: My_test_suite/A_widget/renders (0.00s)\n",
+				"pass My_test_suite/A_widget/renders 0",
+				"output My_test_suite/A_widget/resizes --- FAIL: My_test_suite/A_widget/resizes (0.00s)\n",
+				"fail My_test_suite/A_widget/resizes 0",
+				"run My_test_suite/A_widget/crashes",
+				"output My_test_suite/A_widget/crashes === RUN   My_test_suite/A_widget/crashes\n",
+				"output My_test_suite/A_widget/crashes --- FAIL: My_test_suite/A_widget/crashes (0.00s)\n",
+				"fail My_test_suite/A_widget/crashes 0",
+				"output My_test_suite --- FAIL: My_test_suite (3.00s)\n",
+				"fail My_test_suite 3",
+				"output  FAIL\n",
+				"output  FAIL\texample.com/m/pkg\t3.000s\n",
+				"fail  3",
+			}))
+		})
+	})
+})
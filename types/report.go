@@ -0,0 +1,198 @@
+package types
+
+import "time"
+
+// JSONReportVersion is the version of the format of the JSON reports written
+// with -jsonReport.  It changes whenever a change to the format could break
+// programs that read the reports.
+const JSONReportVersion = 1
+
+// SuiteReport is the document Ginkgo writes with -jsonReport.  It describes a
+// complete run of a suite and can be unmarshaled with encoding/json.
+type SuiteReport struct {
+	Version int `json:"version"`
+
+	SuiteDescription string            `json:"suiteDescription"`
+	SuitePath        string            `json:"suitePath"`
+	SuiteSucceeded   bool              `json:"suiteSucceeded"`
+	Config           SuiteReportConfig `json:"config"`
+
+	// Hostname and GoVersion describe the machine and Go release that ran
+	// the suite.
+	Hostname  string `json:"hostname,omitempty"`
+	GoVersion string `json:"goVersion,omitempty"`
+
+	// CompilationFailure holds the compiler's output for suites the Ginkgo
+	// CLI failed to compile.  Such suites have no specs.
+	CompilationFailure string `json:"compilationFailure,omitempty"`
+
+	StartTime time.Time     `json:"startTime"`
+	EndTime   time.Time     `json:"endTime"`
+	RunTime   time.Duration `json:"runTime"`
+
+	NumberOfTotalSpecs          int `json:"numberOfTotalSpecs"`
+	NumberOfSpecsThatWillBeRun  int `json:"numberOfSpecsThatWillBeRun"`
+	NumberOfPassedSpecs         int `json:"numberOfPassedSpecs"`
+	NumberOfFailedSpecs         int `json:"numberOfFailedSpecs"`
+	NumberOfPendingSpecs        int `json:"numberOfPendingSpecs"`
+	NumberOfSkippedSpecs        int `json:"numberOfSkippedSpecs"`
+	NumberOfFlakedSpecs         int `json:"numberOfFlakedSpecs"`
+	NumberOfQuarantinedFailures int `json:"numberOfQuarantinedFailures"`
+
+	BeforeSuite *SetupReport `json:"beforeSuite,omitempty"`
+	AfterSuite  *SetupReport `json:"afterSuite,omitempty"`
+	Specs       []SpecReport `json:"specs"`
+}
+
+// SuiteReportConfig holds the parts of the Ginkgo configuration that affect
+// which specs a run of a suite runs, and in what order.
+type SuiteReportConfig struct {
+	RandomSeed        int64    `json:"randomSeed"`
+	RandomizeAllSpecs bool     `json:"randomizeAllSpecs"`
+	FocusStrings      []string `json:"focusStrings,omitempty"`
+	SkipStrings       []string `json:"skipStrings,omitempty"`
+	FocusFiles        []string `json:"focusFiles,omitempty"`
+	SkipFiles         []string `json:"skipFiles,omitempty"`
+	SkipMeasurements  bool     `json:"skipMeasurements"`
+	FailOnPending     bool     `json:"failOnPending"`
+	FailFast          bool     `json:"failFast"`
+	FlakeAttempts     int      `json:"flakeAttempts"`
+	DryRun            bool     `json:"dryRun"`
+	ParallelTotal     int      `json:"parallelTotal"`
+	ShardIndex        int      `json:"shardIndex,omitempty"`
+	ShardTotal        int      `json:"shardTotal,omitempty"`
+}
+
+// SetupReport describes a run of the suite's BeforeSuite or AfterSuite.
+type SetupReport struct {
+	CodeLocation CodeLocation  `json:"codeLocation"`
+	State        string        `json:"state"`
+	StartTime    time.Time     `json:"startTime"`
+	EndTime      time.Time     `json:"endTime"`
+	RunTime      time.Duration `json:"runTime"`
+	ParallelNode int           `json:"parallelNode"`
+
+	Failure        *FailureReport `json:"failure,omitempty"`
+	CapturedOutput string         `json:"capturedOutput,omitempty"`
+}
+
+// SpecReport describes a run of a spec.  State is one of the strings returned
+// by SpecState.String.
+type SpecReport struct {
+	ID                     string         `json:"id"`
+	ComponentTexts         []string       `json:"componentTexts"`
+	ComponentCodeLocations []CodeLocation `json:"componentCodeLocations"`
+
+	State        string        `json:"state"`
+	StartTime    time.Time     `json:"startTime"`
+	EndTime      time.Time     `json:"endTime"`
+	RunTime      time.Duration `json:"runTime"`
+	ParallelNode int           `json:"parallelNode"`
+
+	Failure        *FailureReport `json:"failure,omitempty"`
+	CapturedOutput string         `json:"capturedOutput,omitempty"`
+
+	IsMeasurement   bool                        `json:"isMeasurement,omitempty"`
+	NumberOfSamples int                         `json:"numberOfSamples,omitempty"`
+	Measurements    map[string]*SpecMeasurement `json:"measurements,omitempty"`
+
+	Quarantined      bool   `json:"quarantined,omitempty"`
+	QuarantineReason string `json:"quarantineReason,omitempty"`
+	Shard            int    `json:"shard,omitempty"`
+}
+
+// FailureReport describes why a spec, BeforeSuite or AfterSuite failed, or
+// why a spec was skipped.  Location.FullStackTrace holds the stack at the
+// point of failure.
+type FailureReport struct {
+	Message        string       `json:"message"`
+	Location       CodeLocation `json:"location"`
+	ForwardedPanic string       `json:"forwardedPanic,omitempty"`
+
+	ComponentType         string       `json:"componentType"`
+	ComponentCodeLocation CodeLocation `json:"componentCodeLocation"`
+}
+
+// NewSetupReport builds the report of a BeforeSuite or AfterSuite from its
+// summary.  reportedTime is when the summary was reported, and stands in for
+// the end time of summaries that don't record when they started.
+func NewSetupReport(summary *SetupSummary, reportedTime time.Time) *SetupReport {
+	startTime, endTime := reportTimes(summary.StartTime, summary.RunTime, reportedTime)
+	return &SetupReport{
+		CodeLocation:   summary.CodeLocation,
+		State:          summary.State.String(),
+		StartTime:      startTime,
+		EndTime:        endTime,
+		RunTime:        summary.RunTime,
+		ParallelNode:   summary.ParallelNode,
+		Failure:        newFailureReport(summary.State, summary.Failure),
+		CapturedOutput: summary.CapturedOutput,
+	}
+}
+
+// NewSpecReport builds the report of a spec from its summary.  reportedTime is
+// when the summary was reported, and stands in for the start and end times of
+// specs that didn't run.
+func NewSpecReport(summary *SpecSummary, reportedTime time.Time) SpecReport {
+	startTime, endTime := reportTimes(summary.StartTime, summary.RunTime, reportedTime)
+	return SpecReport{
+		ID:                     summary.ID,
+		ComponentTexts:         summary.ComponentTexts,
+		ComponentCodeLocations: summary.ComponentCodeLocations,
+		State:                  summary.State.String(),
+		StartTime:              startTime,
+		EndTime:                endTime,
+		RunTime:                summary.RunTime,
+		ParallelNode:           summary.ParallelNode,
+		Failure:                newFailureReport(summary.State, summary.Failure),
+		CapturedOutput:         summary.CapturedOutput,
+		IsMeasurement:          summary.IsMeasurement,
+		NumberOfSamples:        summary.NumberOfSamples,
+		Measurements:           summary.Measurements,
+		Quarantined:            summary.Quarantined,
+		QuarantineReason:       summary.QuarantineReason,
+		Shard:                  summary.Shard,
+	}
+}
+
+// reportTimes returns when a spec or setup node started and ended.  Summaries
+// without a start time are taken to have ended when they were reported.
+func reportTimes(startTime time.Time, runTime time.Duration, reportedTime time.Time) (time.Time, time.Time) {
+	if startTime.IsZero() {
+		return reportedTime.Add(-runTime), reportedTime
+	}
+	return startTime, startTime.Add(runTime)
+}
+
+func newFailureReport(state SpecState, failure SpecFailure) *FailureReport {
+	if !state.IsFailure() && failure.Message == "" {
+		return nil
+	}
+	return &FailureReport{
+		Message:               failure.Message,
+		Location:              failure.Location,
+		ForwardedPanic:        failure.ForwardedPanic,
+		ComponentType:         failure.ComponentType.String(),
+		ComponentCodeLocation: failure.ComponentCodeLocation,
+	}
+}
+
+// HasFailureState returns true if the spec failed, panicked or timed out.
+func (report SpecReport) HasFailureState() bool {
+	return isFailureStateString(report.State)
+}
+
+// HasQuarantinedFailure returns true if the spec failed but was quarantined.
+func (report SpecReport) HasQuarantinedFailure() bool {
+	return report.Quarantined && report.HasFailureState()
+}
+
+// HasFailureState returns true if the BeforeSuite or AfterSuite failed,
+// panicked or timed out.
+func (report SetupReport) HasFailureState() bool {
+	return isFailureStateString(report.State)
+}
+
+func isFailureStateString(state string) bool {
+	return state == SpecStateFailed.String() || state == SpecStatePanicked.String() || state == SpecStateTimedOut.String()
+}
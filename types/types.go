@@ -35,10 +35,17 @@ type SuiteSummary struct {
 	// Flaked specs are those that failed initially, but then passed on a
 	// subsequent try.
 	NumberOfFlakedSpecs int
-	RunTime             time.Duration
+	// Quarantined failures are failures of specs listed in the quarantine
+	// file.  They are not included in NumberOfFailedSpecs and do not fail
+	// the suite.
+	NumberOfQuarantinedFailures int
+	RunTime                     time.Duration
 }
 
 type SpecSummary struct {
+	// ID is a stable identifier for the spec, built from its file, texts and
+	// table entry index.  Unlike ComponentTexts it is unique within a suite.
+	ID                     string
 	ComponentTexts         []string
 	ComponentCodeLocations []CodeLocation
 
@@ -51,12 +58,28 @@ type SpecSummary struct {
 
 	CapturedOutput string
 	SuiteID        string
+	ParallelNode   int
+
+	Quarantined      bool
+	QuarantineReason string
+
+	// Shard is the one-indexed shard the spec was assigned to when running
+	// with -shard=i/n, and zero otherwise.
+	Shard int
+
+	// StartTime is when the spec's last attempt started running.  It is zero
+	// for specs that didn't run.
+	StartTime time.Time
 }
 
 func (s SpecSummary) HasFailureState() bool {
 	return s.State.IsFailure()
 }
 
+func (s SpecSummary) HasQuarantinedFailure() bool {
+	return s.Quarantined && s.State.IsFailure()
+}
+
 func (s SpecSummary) TimedOut() bool {
 	return s.State == SpecStateTimedOut
 }
@@ -81,16 +104,33 @@ func (s SpecSummary) Pending() bool {
 	return s.State == SpecStatePending
 }
 
+// SpecOutputChunk holds output a spec running on a parallel node wrote to the
+// GinkgoWriter.  Nodes send chunks while the spec runs so that the CLI can show
+// the output live, before the spec completes.
+type SpecOutputChunk struct {
+	ParallelNode   int
+	SpecID         string
+	ComponentTexts []string
+	Output         string
+
+	// Intercepted is true for chunks of what the node wrote to stdout and
+	// stderr.  The CLI doesn't show them live but keeps them so that it can
+	// report them should the node crash.
+	Intercepted bool
+}
+
 type SetupSummary struct {
 	ComponentType SpecComponentType
 	CodeLocation  CodeLocation
 
-	State   SpecState
-	RunTime time.Duration
-	Failure SpecFailure
+	State     SpecState
+	StartTime time.Time
+	RunTime   time.Duration
+	Failure   SpecFailure
 
 	CapturedOutput string
 	SuiteID        string
+	ParallelNode   int
 }
 
 type SpecFailure struct {
@@ -149,6 +189,25 @@ func (state SpecState) IsFailure() bool {
 	return state == SpecStateTimedOut || state == SpecStatePanicked || state == SpecStateFailed
 }
 
+func (state SpecState) String() string {
+	switch state {
+	case SpecStatePending:
+		return "pending"
+	case SpecStateSkipped:
+		return "skipped"
+	case SpecStatePassed:
+		return "passed"
+	case SpecStateFailed:
+		return "failed"
+	case SpecStatePanicked:
+		return "panicked"
+	case SpecStateTimedOut:
+		return "timedout"
+	default:
+		return "invalid"
+	}
+}
+
 type SpecComponentType uint
 
 const (
@@ -165,6 +224,31 @@ const (
 	SpecComponentTypeMeasure
 )
 
+func (componentType SpecComponentType) String() string {
+	switch componentType {
+	case SpecComponentTypeContainer:
+		return "container"
+	case SpecComponentTypeBeforeSuite:
+		return "BeforeSuite"
+	case SpecComponentTypeAfterSuite:
+		return "AfterSuite"
+	case SpecComponentTypeBeforeEach:
+		return "BeforeEach"
+	case SpecComponentTypeJustBeforeEach:
+		return "JustBeforeEach"
+	case SpecComponentTypeJustAfterEach:
+		return "JustAfterEach"
+	case SpecComponentTypeAfterEach:
+		return "AfterEach"
+	case SpecComponentTypeIt:
+		return "It"
+	case SpecComponentTypeMeasure:
+		return "Measure"
+	default:
+		return "invalid"
+	}
+}
+
 type FlagType uint
 
 const (
@@ -1,6 +1,8 @@
 package types_test
 
 import (
+	"time"
+
 	. "github.com/hackrish007/ginkgo/types"
 
 	. "github.com/hackrish007/ginkgo"
@@ -35,6 +37,17 @@ func verifySpecSummary(caller func(SpecSummary) bool, trueStates ...SpecState) {
 }
 
 var _ = Describe("Types", func() {
+	Describe("SpecState", func() {
+		It("names each state", func() {
+			names := []string{}
+			for _, state := range specStates {
+				names = append(names, state.String())
+			}
+			Ω(names).Should(Equal([]string{"passed", "timedout", "panicked", "failed", "pending", "skipped"}))
+			Ω(SpecStateInvalid.String()).Should(Equal("invalid"))
+		})
+	})
+
 	Describe("IsFailureState", func() {
 		It("knows when it is in a failure-like state", func() {
 			verifySpecSummary(func(summary SpecSummary) bool {
@@ -96,4 +109,34 @@ var _ = Describe("Types", func() {
 			Ω(SpecMeasurement{Precision: 3}.PrecisionFmt()).Should(Equal("%.3f"))
 		})
 	})
+
+	Describe("NewSpecReport", func() {
+		var reportedTime time.Time
+
+		BeforeEach(func() {
+			reportedTime = time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		})
+
+		It("records when the spec started and ended", func() {
+			startTime := reportedTime.Add(-time.Minute)
+			report := NewSpecReport(&SpecSummary{StartTime: startTime, RunTime: time.Second}, reportedTime)
+			Ω(report.StartTime).Should(BeTemporally("==", startTime))
+			Ω(report.EndTime).Should(BeTemporally("==", startTime.Add(time.Second)))
+		})
+
+		It("takes specs that didn't record a start time to have ended when they were reported", func() {
+			report := NewSpecReport(&SpecSummary{RunTime: time.Second}, reportedTime)
+			Ω(report.StartTime).Should(BeTemporally("==", reportedTime.Add(-time.Second)))
+			Ω(report.EndTime).Should(BeTemporally("==", reportedTime))
+		})
+	})
+
+	Describe("NewSetupReport", func() {
+		It("records when the setup node started and ended", func() {
+			startTime := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+			report := NewSetupReport(&SetupSummary{StartTime: startTime, RunTime: time.Second}, startTime.Add(time.Minute))
+			Ω(report.StartTime).Should(BeTemporally("==", startTime))
+			Ω(report.EndTime).Should(BeTemporally("==", startTime.Add(time.Second)))
+		})
+	})
 })